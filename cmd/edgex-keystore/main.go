@@ -0,0 +1,161 @@
+// Command edgex-keystore imports Stark private keys into encrypted keystores and
+// exports them again.
+//
+//	edgex-keystore import -out key.json [-account-id 123] [-env mainnet] [-light] [-force]
+//	edgex-keystore export -in key.json
+//
+// import reads the private key from EDGEX_STARK_PRIVATE_KEY or standard input. Both
+// subcommands read the passphrase from EDGEX_KEYSTORE_PASSPHRASE or standard input.
+// export writes the private key to standard output.
+package main
+
+import (
+	"bufio"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/coin-quant/go-edgex/keystore"
+	"github.com/coin-quant/go-edgex/starkkey"
+	"golang.org/x/term"
+)
+
+const usage = `usage:
+  edgex-keystore import -out key.json [-account-id 123] [-env mainnet] [-light] [-force]
+  edgex-keystore export -in key.json`
+
+func main() {
+	if len(os.Args) < 2 {
+		fmt.Fprintln(os.Stderr, usage)
+		os.Exit(2)
+	}
+	var err error
+	stdin := bufio.NewReader(os.Stdin)
+	switch os.Args[1] {
+	case "import":
+		err = importKey(os.Args[2:], stdin)
+	case "export":
+		err = exportKey(os.Args[2:], stdin)
+	default:
+		fmt.Fprintln(os.Stderr, usage)
+		os.Exit(2)
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "edgex-keystore: %v\n", err)
+		os.Exit(1)
+	}
+}
+
+// importKey encrypts a private key into a new keystore
+func importKey(args []string, stdin *bufio.Reader) error {
+	flags := flag.NewFlagSet("import", flag.ExitOnError)
+	out := flags.String("out", "", "keystore file to write")
+	accountID := flags.Int64("account-id", 0, "account ID of the key")
+	env := flags.String("env", "", "exchange environment of the account, such as mainnet or testnet")
+	light := flags.Bool("light", false, "use light scrypt parameters")
+	force := flags.Bool("force", false, "overwrite an existing keystore")
+	_ = flags.Parse(args)
+	if *out == "" {
+		return errors.New("-out is required")
+	}
+	if _, err := os.Stat(*out); err == nil && !*force {
+		return fmt.Errorf("%s already exists, use -force to overwrite it", *out)
+	}
+
+	privateKey := os.Getenv("EDGEX_STARK_PRIVATE_KEY")
+	if privateKey == "" {
+		var err error
+		if privateKey, err = promptSecret(stdin, "Stark private key: "); err != nil {
+			return err
+		}
+	}
+	keyPair, err := starkkey.FromPrivateKey(privateKey)
+	if err != nil {
+		return err
+	}
+
+	passphrase, err := readPassphrase(stdin, true)
+	if err != nil {
+		return err
+	}
+	params := keystore.StandardScryptParams
+	if *light {
+		params = keystore.LightScryptParams
+	}
+	data, err := keystore.Encrypt(&keystore.Key{KeyPair: keyPair, AccountID: *accountID, Environment: *env}, passphrase, params)
+	if err != nil {
+		return err
+	}
+	if err := keystore.WriteFile(*out, data); err != nil {
+		return err
+	}
+	fmt.Fprintf(os.Stderr, "stored key %s in %s\n", keyPair.PublicKeyHex(), *out)
+	return nil
+}
+
+// exportKey decrypts a keystore and prints its private key
+func exportKey(args []string, stdin *bufio.Reader) error {
+	flags := flag.NewFlagSet("export", flag.ExitOnError)
+	in := flags.String("in", "", "keystore file to read")
+	_ = flags.Parse(args)
+	if *in == "" {
+		return errors.New("-in is required")
+	}
+	passphrase, err := readPassphrase(stdin, false)
+	if err != nil {
+		return err
+	}
+	key, err := keystore.Load(*in, passphrase)
+	if err != nil {
+		return err
+	}
+	fmt.Fprintf(os.Stderr, "key %s of account %d (%s)\n", key.KeyPair.PublicKeyHex(), key.AccountID, key.Environment)
+	fmt.Println(key.KeyPair.PrivateKeyHex())
+	return nil
+}
+
+// readPassphrase reads the passphrase from the environment or standard input, asking
+// twice for a new passphrase
+func readPassphrase(stdin *bufio.Reader, confirm bool) (string, error) {
+	if passphrase := os.Getenv("EDGEX_KEYSTORE_PASSPHRASE"); passphrase != "" {
+		return passphrase, nil
+	}
+	passphrase, err := promptSecret(stdin, "Passphrase: ")
+	if err != nil {
+		return "", err
+	}
+	if passphrase == "" {
+		return "", errors.New("passphrase is empty")
+	}
+	if confirm {
+		repeated, err := promptSecret(stdin, "Repeat passphrase: ")
+		if err != nil {
+			return "", err
+		}
+		if repeated != passphrase {
+			return "", errors.New("passphrases do not match")
+		}
+	}
+	return passphrase, nil
+}
+
+// promptSecret reads a line from standard input without echoing it when standard
+// input is a terminal
+func promptSecret(stdin *bufio.Reader, label string) (string, error) {
+	fmt.Fprint(os.Stderr, label)
+	if fd := int(os.Stdin.Fd()); term.IsTerminal(fd) {
+		secret, err := term.ReadPassword(fd)
+		fmt.Fprintln(os.Stderr)
+		if err != nil {
+			return "", fmt.Errorf("failed to read input: %w", err)
+		}
+		return strings.TrimSpace(string(secret)), nil
+	}
+	line, err := stdin.ReadString('\n')
+	if err != nil && line == "" {
+		return "", fmt.Errorf("failed to read input: %w", err)
+	}
+	return strings.TrimSpace(line), nil
+}
@@ -6,6 +6,7 @@ require (
 	github.com/google/uuid v1.6.0
 	github.com/shopspring/decimal v1.4.0
 	golang.org/x/crypto v0.32.0
+	golang.org/x/term v0.28.0
 )
 
 require (
@@ -0,0 +1,309 @@
+// Package keystore stores Stark private keys encrypted with a passphrase. The format
+// follows the Ethereum JSON v3 keystore: the key is encrypted with AES-128-CTR under a
+// key derived with scrypt, and a Keccak-256 MAC detects a wrong passphrase. The file
+// also records the public key, account ID and environment of the key in clear text.
+// Unlike the Ethereum MAC, the MAC also covers these fields, so that they cannot be
+// changed without the passphrase.
+package keystore
+
+import (
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+
+	"github.com/coin-quant/go-edgex/starkkey"
+	"github.com/google/uuid"
+	"golang.org/x/crypto/scrypt"
+	"golang.org/x/crypto/sha3"
+)
+
+const (
+	version    = 3
+	cipherName = "aes-128-ctr"
+	kdfName    = "scrypt"
+	dkLen      = 32
+	saltLen    = 32
+	// maxScryptMemory and maxScryptP bound the cost of the scrypt parameters read from
+	// a file, so that a crafted keystore cannot exhaust memory or CPU
+	maxScryptMemory = 512 << 20
+	maxScryptP      = 16
+)
+
+// ErrDecrypt is returned when the passphrase does not match the keystore
+var ErrDecrypt = errors.New("could not decrypt key with given passphrase")
+
+// ScryptParams are the scrypt cost parameters
+type ScryptParams struct {
+	N int
+	R int
+	P int
+}
+
+var (
+	// StandardScryptParams take about a second and 256MB of memory to derive a key
+	StandardScryptParams = ScryptParams{N: 1 << 18, R: 8, P: 1}
+	// LightScryptParams take about 100ms and 4MB of memory, for constrained hosts
+	LightScryptParams = ScryptParams{N: 1 << 12, R: 8, P: 6}
+)
+
+// Key is a Stark key pair with the account it signs for
+type Key struct {
+	ID        string
+	KeyPair   *starkkey.KeyPair
+	AccountID int64
+	// Environment names the exchange environment of the account, such as mainnet
+	Environment string
+}
+
+// encryptedKey is the JSON layout of a keystore
+type encryptedKey struct {
+	Version     int        `json:"version"`
+	ID          string     `json:"id"`
+	PublicKey   string     `json:"publicKey"`
+	AccountID   string     `json:"accountId,omitempty"`
+	Environment string     `json:"environment,omitempty"`
+	Crypto      cryptoJSON `json:"crypto"`
+}
+
+type cryptoJSON struct {
+	Cipher       string       `json:"cipher"`
+	CipherText   string       `json:"ciphertext"`
+	CipherParams cipherParams `json:"cipherparams"`
+	KDF          string       `json:"kdf"`
+	KDFParams    kdfParams    `json:"kdfparams"`
+	MAC          string       `json:"mac"`
+}
+
+type cipherParams struct {
+	IV string `json:"iv"`
+}
+
+type kdfParams struct {
+	DKLen int    `json:"dklen"`
+	N     int    `json:"n"`
+	R     int    `json:"r"`
+	P     int    `json:"p"`
+	Salt  string `json:"salt"`
+}
+
+// Encrypt encrypts a key with a passphrase and returns the keystore JSON
+func Encrypt(key *Key, passphrase string, params ScryptParams) ([]byte, error) {
+	if key == nil || key.KeyPair == nil {
+		return nil, fmt.Errorf("key is nil")
+	}
+	if err := checkScryptParams(params); err != nil {
+		return nil, err
+	}
+	salt := make([]byte, saltLen)
+	if _, err := io.ReadFull(rand.Reader, salt); err != nil {
+		return nil, fmt.Errorf("failed to read salt: %w", err)
+	}
+	derivedKey, err := scrypt.Key([]byte(passphrase), salt, params.N, params.R, params.P, dkLen)
+	if err != nil {
+		return nil, fmt.Errorf("failed to derive key: %w", err)
+	}
+	iv := make([]byte, aes.BlockSize)
+	if _, err := io.ReadFull(rand.Reader, iv); err != nil {
+		return nil, fmt.Errorf("failed to read iv: %w", err)
+	}
+	privateKey, err := hex.DecodeString(key.KeyPair.PrivateKeyHex())
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode private key: %w", err)
+	}
+	cipherText, err := aesCTR(derivedKey[:16], iv, privateKey)
+	if err != nil {
+		return nil, err
+	}
+
+	id := key.ID
+	if id == "" {
+		id = uuid.NewString()
+	}
+	var accountID string
+	if key.AccountID != 0 {
+		accountID = strconv.FormatInt(key.AccountID, 10)
+	}
+	stored := encryptedKey{
+		Version:     version,
+		ID:          id,
+		PublicKey:   key.KeyPair.PublicKeyHex(),
+		AccountID:   accountID,
+		Environment: key.Environment,
+		Crypto: cryptoJSON{
+			Cipher:       cipherName,
+			CipherText:   hex.EncodeToString(cipherText),
+			CipherParams: cipherParams{IV: hex.EncodeToString(iv)},
+			KDF:          kdfName,
+			KDFParams: kdfParams{
+				DKLen: dkLen,
+				N:     params.N,
+				R:     params.R,
+				P:     params.P,
+				Salt:  hex.EncodeToString(salt),
+			},
+		},
+	}
+	stored.Crypto.MAC = hex.EncodeToString(mac(derivedKey, cipherText, stored))
+	return json.MarshalIndent(stored, "", "  ")
+}
+
+// Decrypt decrypts keystore JSON with a passphrase. It returns ErrDecrypt if the
+// passphrase is wrong.
+func Decrypt(data []byte, passphrase string) (*Key, error) {
+	var stored encryptedKey
+	if err := json.Unmarshal(data, &stored); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal keystore: %w", err)
+	}
+	if stored.Version != version {
+		return nil, fmt.Errorf("unsupported keystore version: %d", stored.Version)
+	}
+	if stored.Crypto.Cipher != cipherName {
+		return nil, fmt.Errorf("unsupported cipher: %s", stored.Crypto.Cipher)
+	}
+	if stored.Crypto.KDF != kdfName {
+		return nil, fmt.Errorf("unsupported kdf: %s", stored.Crypto.KDF)
+	}
+	params := stored.Crypto.KDFParams
+	if params.DKLen != dkLen {
+		return nil, fmt.Errorf("unsupported derived key length: %d", params.DKLen)
+	}
+	if err := checkScryptParams(ScryptParams{N: params.N, R: params.R, P: params.P}); err != nil {
+		return nil, err
+	}
+	salt, err := hex.DecodeString(params.Salt)
+	if err != nil {
+		return nil, fmt.Errorf("invalid salt: %w", err)
+	}
+	iv, err := hex.DecodeString(stored.Crypto.CipherParams.IV)
+	if err != nil {
+		return nil, fmt.Errorf("invalid iv: %w", err)
+	}
+	cipherText, err := hex.DecodeString(stored.Crypto.CipherText)
+	if err != nil {
+		return nil, fmt.Errorf("invalid ciphertext: %w", err)
+	}
+	expectedMAC, err := hex.DecodeString(stored.Crypto.MAC)
+	if err != nil {
+		return nil, fmt.Errorf("invalid mac: %w", err)
+	}
+
+	derivedKey, err := scrypt.Key([]byte(passphrase), salt, params.N, params.R, params.P, params.DKLen)
+	if err != nil {
+		return nil, fmt.Errorf("failed to derive key: %w", err)
+	}
+	if !bytes.Equal(mac(derivedKey, cipherText, stored), expectedMAC) {
+		return nil, ErrDecrypt
+	}
+	privateKey, err := aesCTR(derivedKey[:16], iv, cipherText)
+	if err != nil {
+		return nil, err
+	}
+	keyPair, err := starkkey.FromPrivateKey(hex.EncodeToString(privateKey))
+	if err != nil {
+		return nil, err
+	}
+	if err := keyPair.Validate(stored.PublicKey, ""); err != nil {
+		return nil, fmt.Errorf("keystore public key mismatch: %w", err)
+	}
+
+	key := &Key{ID: stored.ID, KeyPair: keyPair, Environment: stored.Environment}
+	if stored.AccountID != "" {
+		if key.AccountID, err = strconv.ParseInt(stored.AccountID, 10, 64); err != nil {
+			return nil, fmt.Errorf("invalid account id %s: %w", stored.AccountID, err)
+		}
+	}
+	return key, nil
+}
+
+// Save encrypts a key with StandardScryptParams and writes it to path, readable by
+// the owner only
+func Save(path string, key *Key, passphrase string) error {
+	data, err := Encrypt(key, passphrase, StandardScryptParams)
+	if err != nil {
+		return err
+	}
+	return WriteFile(path, data)
+}
+
+// Load reads and decrypts the keystore at path
+func Load(path, passphrase string) (*Key, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read keystore: %w", err)
+	}
+	return Decrypt(data, passphrase)
+}
+
+// WriteFile writes keystore JSON to path with owner only permissions. The file is
+// written to a temporary file first and renamed, so that an existing keystore is
+// never left half written.
+func WriteFile(path string, data []byte) error {
+	tmp, err := os.CreateTemp(filepath.Dir(path), "."+filepath.Base(path)+".tmp")
+	if err != nil {
+		return fmt.Errorf("failed to create keystore file: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+	if err := tmp.Chmod(0o600); err != nil {
+		tmp.Close()
+		return fmt.Errorf("failed to set keystore permissions: %w", err)
+	}
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return fmt.Errorf("failed to write keystore: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("failed to write keystore: %w", err)
+	}
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		return fmt.Errorf("failed to write keystore: %w", err)
+	}
+	return nil
+}
+
+// aesCTR encrypts or decrypts data with AES in counter mode
+func aesCTR(key, iv, data []byte) ([]byte, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create cipher: %w", err)
+	}
+	if len(iv) != block.BlockSize() {
+		return nil, fmt.Errorf("invalid iv length: %d", len(iv))
+	}
+	out := make([]byte, len(data))
+	cipher.NewCTR(block, iv).XORKeyStream(out, data)
+	return out, nil
+}
+
+// checkScryptParams rejects invalid parameters and parameters above the cost bounds
+func checkScryptParams(params ScryptParams) error {
+	if params.N <= 1 || params.N&(params.N-1) != 0 {
+		return fmt.Errorf("scrypt n must be a power of two above 1: %d", params.N)
+	}
+	if params.R <= 0 || params.P <= 0 {
+		return fmt.Errorf("scrypt r and p must be positive: %d, %d", params.R, params.P)
+	}
+	if params.P > maxScryptP || params.R > maxScryptMemory/128/params.N {
+		return fmt.Errorf("scrypt parameters above bounds: n %d, r %d, p %d", params.N, params.R, params.P)
+	}
+	return nil
+}
+
+// mac is the Keccak-256 of the second half of the derived key, the ciphertext and
+// the clear text public key, account ID and environment
+func mac(derivedKey, cipherText []byte, stored encryptedKey) []byte {
+	metadata, _ := json.Marshal([]string{stored.PublicKey, stored.AccountID, stored.Environment})
+	hash := sha3.NewLegacyKeccak256()
+	hash.Write(derivedKey[16:32])
+	hash.Write(cipherText)
+	hash.Write(metadata)
+	return hash.Sum(nil)
+}
@@ -24,22 +24,30 @@ func NewClient(client *internal.Client) *Client {
 
 // GetAccountAsset gets the account asset information
 func (c *Client) GetAccountAsset(ctx context.Context) (*GetAccountAssetResponse, error) {
-	url := fmt.Sprintf("%s/api/v1/private/account/getAccountAsset", c.Client.GetBaseURL())
-	params := map[string]string{
-		"accountId": strconv.FormatInt(c.Client.GetAccountID(), 10),
+	body, err := c.getAccountAsset(ctx)
+	if err != nil {
+		return nil, err
+	}
+	var result GetAccountAssetResponse
+	if err := json.Unmarshal(body, &result); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal response: %w", err)
 	}
 
-	resp, err := c.Client.HttpRequest(url, "GET", nil, params)
-	if err != nil {
-		return nil, fmt.Errorf("failed to get account asset: %w", err)
+	if result.Code != "SUCCESS" {
+		return nil, fmt.Errorf("request failed with code: %s, errorParam: %v", result.Code, result.ErrorParam)
 	}
-	defer resp.Body.Close()
 
-	body, err := io.ReadAll(resp.Body)
+	return &result, nil
+}
+
+// GetAccountAssetData gets the account asset information as the full account,
+// position and collateral models of this package
+func (c *Client) GetAccountAssetData(ctx context.Context) (*GetAccountAssetDataResponse, error) {
+	body, err := c.getAccountAsset(ctx)
 	if err != nil {
-		return nil, fmt.Errorf("failed to read response body: %w", err)
+		return nil, err
 	}
-	var result GetAccountAssetResponse
+	var result GetAccountAssetDataResponse
 	if err := json.Unmarshal(body, &result); err != nil {
 		return nil, fmt.Errorf("failed to unmarshal response: %w", err)
 	}
@@ -51,41 +59,37 @@ func (c *Client) GetAccountAsset(ctx context.Context) (*GetAccountAssetResponse,
 	return &result, nil
 }
 
+// getAccountAsset returns the raw body of a getAccountAsset request
+func (c *Client) getAccountAsset(ctx context.Context) ([]byte, error) {
+	url := fmt.Sprintf("%s/api/v1/private/account/getAccountAsset", c.Client.GetBaseURL())
+	params := map[string]string{
+		"accountId": strconv.FormatInt(c.Client.GetAccountID(), 10),
+	}
+
+	resp, err := c.Client.HttpRequest(url, "GET", nil, params)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get account asset: %w", err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read response body: %w", err)
+	}
+	return body, nil
+}
+
 // GetAccountPositions gets the account positions
 func (c *Client) GetAccountPositions(ctx context.Context) (*ListPositionResponse, error) {
-	//url := fmt.Sprintf("%s/api/v1/private/account/getAccountAsset", c.Client.GetBaseURL())
-	//params := map[string]string{
-	//	"accountId": strconv.FormatInt(c.Client.GetAccountID(), 10),
-	//}
-	//
-	//resp, err := c.Client.HttpRequest(url, "GET", nil, params)
-	//if err != nil {
-	//	return nil, fmt.Errorf("failed to get account positions: %w", err)
-	//}
-	//defer resp.Body.Close()
-	//
-	//body, err := io.ReadAll(resp.Body)
-	//if err != nil {
-	//	return nil, fmt.Errorf("failed to read response body: %w", err)
-	//}
-	//fmt.Println(string(body))
-	//
-	//var assetResp GetAccountAssetResponse
-	//if err := json.Unmarshal(body, &assetResp); err != nil {
-	//	return nil, fmt.Errorf("failed to unmarshal response: %w", err)
-	//}
-	//
-	//if assetResp.Code != "SUCCESS" {
-	//	return nil, fmt.Errorf("request failed with code: %s", assetResp.Code)
-	//}
-	//
-	//result := &ListPositionResponse{
-	//	Code: assetResp.Code,
-	//	Data: assetResp.Data.PositionList,
-	//}
-	//
-	//return result, nil
-	return nil, nil
+	assetResp, err := c.GetAccountAssetData(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get account positions: %w", err)
+	}
+
+	return &ListPositionResponse{
+		Code: assetResp.Code,
+		Data: assetResp.Data.PositionList,
+	}, nil
 }
 
 // GetPositionTransactionPage gets the position transactions with pagination
@@ -489,6 +493,71 @@ func (c *Client) GetAccountDeleverageLight(ctx context.Context) (*GetAccountDele
 	return &result, nil
 }
 
+// GetAccountPage gets the accounts of the user with pagination
+func (c *Client) GetAccountPage(ctx context.Context, params GetAccountPageParams) (*PageDataAccountResponse, error) {
+	url := fmt.Sprintf("%s/api/v1/private/account/getAccountPage", c.Client.GetBaseURL())
+	queryParams := map[string]string{
+		"size": strconv.FormatInt(int64(params.Size), 10),
+	}
+	if params.OffsetData != "" {
+		queryParams["offsetData"] = params.OffsetData
+	}
+
+	resp, err := c.Client.HttpRequest(url, "GET", nil, queryParams)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get account page: %w", err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read response body: %w", err)
+	}
+
+	var result PageDataAccountResponse
+	if err := json.Unmarshal(body, &result); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal response: %w", err)
+	}
+
+	if result.Code != "SUCCESS" {
+		return nil, fmt.Errorf("request failed with code: %s", result.Code)
+	}
+
+	return &result, nil
+}
+
+// RegisterAccount registers a new account for a stark key under the current user
+func (c *Client) RegisterAccount(ctx context.Context, params RegisterAccountParams) (*RegisterAccountResponse, error) {
+	url := fmt.Sprintf("%s/api/v1/private/account/registerAccount", c.Client.GetBaseURL())
+	data := map[string]interface{}{
+		"l2Key":            params.L2Key,
+		"l2KeyYCoordinate": params.L2KeyYCoordinate,
+		"clientAccountId":  params.ClientAccountID,
+	}
+
+	resp, err := c.Client.HttpRequest(url, "POST", data, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to register account: %w", err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read response body: %w", err)
+	}
+
+	var result RegisterAccountResponse
+	if err := json.Unmarshal(body, &result); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal response: %w", err)
+	}
+
+	if result.Code != "SUCCESS" {
+		return nil, fmt.Errorf("request failed with code: %s, errorParam: %v", result.Code, result.ErrorParam)
+	}
+
+	return &result, nil
+}
+
 // UpdateLeverageSetting updates the account leverage settings
 func (c *Client) UpdateLeverageSetting(ctx context.Context, contractID string, leverage string) error {
 	url := fmt.Sprintf("%s/api/v1/private/account/updateLeverageSetting", c.Client.GetBaseURL())
@@ -0,0 +1,562 @@
+package account
+
+import (
+	"fmt"
+
+	"github.com/shopspring/decimal"
+)
+
+// Decimal accessors return zero for an empty field and an error for a field that
+// is not a valid number, so that a malformed amount never passes for zero.
+
+// parseDecimal parses an API amount, returning zero for empty input
+func parseDecimal(name, value string) (decimal.Decimal, error) {
+	if value == "" {
+		return decimal.Zero, nil
+	}
+	d, err := decimal.NewFromString(value)
+	if err != nil {
+		return decimal.Zero, fmt.Errorf("invalid %s %q: %w", name, value, err)
+	}
+	return d, nil
+}
+
+// parseDecimalPtr parses an optional API amount, returning zero for nil or empty input
+func parseDecimalPtr(name string, value *string) (decimal.Decimal, error) {
+	if value == nil {
+		return decimal.Zero, nil
+	}
+	return parseDecimal(name, *value)
+}
+
+// Position decimal accessors
+
+// OpenSizeDecimal returns OpenSize as a decimal
+func (p Position) OpenSizeDecimal() (decimal.Decimal, error) {
+	return parseDecimal("openSize", p.OpenSize)
+}
+
+// OpenValueDecimal returns OpenValue as a decimal
+func (p Position) OpenValueDecimal() (decimal.Decimal, error) {
+	return parseDecimal("openValue", p.OpenValue)
+}
+
+// OpenFeeDecimal returns OpenFee as a decimal
+func (p Position) OpenFeeDecimal() (decimal.Decimal, error) {
+	return parseDecimal("openFee", p.OpenFee)
+}
+
+// FundingFeeDecimal returns FundingFee as a decimal
+func (p Position) FundingFeeDecimal() (decimal.Decimal, error) {
+	return parseDecimal("fundingFee", p.FundingFee)
+}
+
+// PositionStat decimal accessors
+
+// CumOpenSizeDecimal returns CumOpenSize as a decimal
+func (s PositionStat) CumOpenSizeDecimal() (decimal.Decimal, error) {
+	return parseDecimal("cumOpenSize", s.CumOpenSize)
+}
+
+// CumOpenValueDecimal returns CumOpenValue as a decimal
+func (s PositionStat) CumOpenValueDecimal() (decimal.Decimal, error) {
+	return parseDecimal("cumOpenValue", s.CumOpenValue)
+}
+
+// CumOpenFeeDecimal returns CumOpenFee as a decimal
+func (s PositionStat) CumOpenFeeDecimal() (decimal.Decimal, error) {
+	return parseDecimal("cumOpenFee", s.CumOpenFee)
+}
+
+// CumCloseSizeDecimal returns CumCloseSize as a decimal
+func (s PositionStat) CumCloseSizeDecimal() (decimal.Decimal, error) {
+	return parseDecimal("cumCloseSize", s.CumCloseSize)
+}
+
+// CumCloseValueDecimal returns CumCloseValue as a decimal
+func (s PositionStat) CumCloseValueDecimal() (decimal.Decimal, error) {
+	return parseDecimal("cumCloseValue", s.CumCloseValue)
+}
+
+// CumCloseFeeDecimal returns CumCloseFee as a decimal
+func (s PositionStat) CumCloseFeeDecimal() (decimal.Decimal, error) {
+	return parseDecimal("cumCloseFee", s.CumCloseFee)
+}
+
+// CumFundingFeeDecimal returns CumFundingFee as a decimal
+func (s PositionStat) CumFundingFeeDecimal() (decimal.Decimal, error) {
+	return parseDecimal("cumFundingFee", s.CumFundingFee)
+}
+
+// CumLiquidateFeeDecimal returns CumLiquidateFee as a decimal
+func (s PositionStat) CumLiquidateFeeDecimal() (decimal.Decimal, error) {
+	return parseDecimal("cumLiquidateFee", s.CumLiquidateFee)
+}
+
+// PositionAsset decimal accessors
+
+// PositionValueDecimal returns PositionValue as a decimal
+func (a PositionAsset) PositionValueDecimal() (decimal.Decimal, error) {
+	return parseDecimal("positionValue", a.PositionValue)
+}
+
+// MaxLeverageDecimal returns MaxLeverage as a decimal
+func (a PositionAsset) MaxLeverageDecimal() (decimal.Decimal, error) {
+	return parseDecimal("maxLeverage", a.MaxLeverage)
+}
+
+// InitialMarginRequirementDecimal returns InitialMarginRequirement as a decimal
+func (a PositionAsset) InitialMarginRequirementDecimal() (decimal.Decimal, error) {
+	return parseDecimal("initialMarginRequirement", a.InitialMarginRequirement)
+}
+
+// StarkExRiskRateDecimal returns StarkExRiskRate as a decimal
+func (a PositionAsset) StarkExRiskRateDecimal() (decimal.Decimal, error) {
+	return parseDecimal("starkExRiskRate", a.StarkExRiskRate)
+}
+
+// StarkExRiskValueDecimal returns StarkExRiskValue as a decimal
+func (a PositionAsset) StarkExRiskValueDecimal() (decimal.Decimal, error) {
+	return parseDecimal("starkExRiskValue", a.StarkExRiskValue)
+}
+
+// AvgEntryPriceDecimal returns AvgEntryPrice as a decimal
+func (a PositionAsset) AvgEntryPriceDecimal() (decimal.Decimal, error) {
+	return parseDecimal("avgEntryPrice", a.AvgEntryPrice)
+}
+
+// LiquidatePriceDecimal returns LiquidatePrice as a decimal
+func (a PositionAsset) LiquidatePriceDecimal() (decimal.Decimal, error) {
+	return parseDecimal("liquidatePrice", a.LiquidatePrice)
+}
+
+// BankruptPriceDecimal returns BankruptPrice as a decimal
+func (a PositionAsset) BankruptPriceDecimal() (decimal.Decimal, error) {
+	return parseDecimal("bankruptPrice", a.BankruptPrice)
+}
+
+// WorstClosePriceDecimal returns WorstClosePrice as a decimal
+func (a PositionAsset) WorstClosePriceDecimal() (decimal.Decimal, error) {
+	return parseDecimal("worstClosePrice", a.WorstClosePrice)
+}
+
+// UnrealizePnlDecimal returns UnrealizePnl as a decimal
+func (a PositionAsset) UnrealizePnlDecimal() (decimal.Decimal, error) {
+	return parseDecimal("unrealizePnl", a.UnrealizePnl)
+}
+
+// TermRealizePnlDecimal returns TermRealizePnl as a decimal
+func (a PositionAsset) TermRealizePnlDecimal() (decimal.Decimal, error) {
+	return parseDecimal("termRealizePnl", a.TermRealizePnl)
+}
+
+// TotalRealizePnlDecimal returns TotalRealizePnl as a decimal
+func (a PositionAsset) TotalRealizePnlDecimal() (decimal.Decimal, error) {
+	return parseDecimal("totalRealizePnl", a.TotalRealizePnl)
+}
+
+// Collateral decimal accessors
+
+// AmountDecimal returns Amount as a decimal
+func (c Collateral) AmountDecimal() (decimal.Decimal, error) {
+	return parseDecimal("amount", c.Amount)
+}
+
+// LegacyAmountDecimal returns LegacyAmount as a decimal
+func (c Collateral) LegacyAmountDecimal() (decimal.Decimal, error) {
+	return parseDecimal("legacyAmount", c.LegacyAmount)
+}
+
+// CumDepositAmountDecimal returns CumDepositAmount as a decimal
+func (c Collateral) CumDepositAmountDecimal() (decimal.Decimal, error) {
+	return parseDecimal("cumDepositAmount", c.CumDepositAmount)
+}
+
+// CumWithdrawAmountDecimal returns CumWithdrawAmount as a decimal
+func (c Collateral) CumWithdrawAmountDecimal() (decimal.Decimal, error) {
+	return parseDecimal("cumWithdrawAmount", c.CumWithdrawAmount)
+}
+
+// CumTransferInAmountDecimal returns CumTransferInAmount as a decimal
+func (c Collateral) CumTransferInAmountDecimal() (decimal.Decimal, error) {
+	return parseDecimal("cumTransferInAmount", c.CumTransferInAmount)
+}
+
+// CumTransferOutAmountDecimal returns CumTransferOutAmount as a decimal
+func (c Collateral) CumTransferOutAmountDecimal() (decimal.Decimal, error) {
+	return parseDecimal("cumTransferOutAmount", c.CumTransferOutAmount)
+}
+
+// CumPositionBuyAmountDecimal returns CumPositionBuyAmount as a decimal
+func (c Collateral) CumPositionBuyAmountDecimal() (decimal.Decimal, error) {
+	return parseDecimal("cumPositionBuyAmount", c.CumPositionBuyAmount)
+}
+
+// CumPositionSellAmountDecimal returns CumPositionSellAmount as a decimal
+func (c Collateral) CumPositionSellAmountDecimal() (decimal.Decimal, error) {
+	return parseDecimal("cumPositionSellAmount", c.CumPositionSellAmount)
+}
+
+// CumFillFeeAmountDecimal returns CumFillFeeAmount as a decimal
+func (c Collateral) CumFillFeeAmountDecimal() (decimal.Decimal, error) {
+	return parseDecimal("cumFillFeeAmount", c.CumFillFeeAmount)
+}
+
+// CumFundingFeeAmountDecimal returns CumFundingFeeAmount as a decimal
+func (c Collateral) CumFundingFeeAmountDecimal() (decimal.Decimal, error) {
+	return parseDecimal("cumFundingFeeAmount", c.CumFundingFeeAmount)
+}
+
+// CumFillFeeIncomeAmountDecimal returns CumFillFeeIncomeAmount as a decimal
+func (c Collateral) CumFillFeeIncomeAmountDecimal() (decimal.Decimal, error) {
+	return parseDecimal("cumFillFeeIncomeAmount", c.CumFillFeeIncomeAmount)
+}
+
+// CollateralAsset decimal accessors
+
+// TotalEquityDecimal returns TotalEquity as a decimal
+func (a CollateralAsset) TotalEquityDecimal() (decimal.Decimal, error) {
+	return parseDecimal("totalEquity", a.TotalEquity)
+}
+
+// TotalPositionValueAbsDecimal returns TotalPositionValueAbs as a decimal
+func (a CollateralAsset) TotalPositionValueAbsDecimal() (decimal.Decimal, error) {
+	return parseDecimal("totalPositionValueAbs", a.TotalPositionValueAbs)
+}
+
+// InitialMarginRequirementDecimal returns InitialMarginRequirement as a decimal
+func (a CollateralAsset) InitialMarginRequirementDecimal() (decimal.Decimal, error) {
+	return parseDecimal("initialMarginRequirement", a.InitialMarginRequirement)
+}
+
+// StarkExRiskValueDecimal returns StarkExRiskValue as a decimal
+func (a CollateralAsset) StarkExRiskValueDecimal() (decimal.Decimal, error) {
+	return parseDecimal("starkExRiskValue", a.StarkExRiskValue)
+}
+
+// PendingWithdrawAmountDecimal returns PendingWithdrawAmount as a decimal
+func (a CollateralAsset) PendingWithdrawAmountDecimal() (decimal.Decimal, error) {
+	return parseDecimal("pendingWithdrawAmount", a.PendingWithdrawAmount)
+}
+
+// PendingTransferOutAmountDecimal returns PendingTransferOutAmount as a decimal
+func (a CollateralAsset) PendingTransferOutAmountDecimal() (decimal.Decimal, error) {
+	return parseDecimal("pendingTransferOutAmount", a.PendingTransferOutAmount)
+}
+
+// OrderFrozenAmountDecimal returns OrderFrozenAmount as a decimal
+func (a CollateralAsset) OrderFrozenAmountDecimal() (decimal.Decimal, error) {
+	return parseDecimal("orderFrozenAmount", a.OrderFrozenAmount)
+}
+
+// AvailableAmountDecimal returns AvailableAmount as a decimal
+func (a CollateralAsset) AvailableAmountDecimal() (decimal.Decimal, error) {
+	return parseDecimal("availableAmount", a.AvailableAmount)
+}
+
+// OraclePrice decimal accessors
+
+// PriceValueDecimal returns PriceValue as a decimal
+func (o OraclePrice) PriceValueDecimal() (decimal.Decimal, error) {
+	return parseDecimal("priceValue", o.PriceValue)
+}
+
+// PositionTransaction decimal accessors
+
+// DeltaOpenSizeDecimal returns DeltaOpenSize as a decimal
+func (t PositionTransaction) DeltaOpenSizeDecimal() (decimal.Decimal, error) {
+	return parseDecimalPtr("deltaOpenSize", t.DeltaOpenSize)
+}
+
+// DeltaOpenValueDecimal returns DeltaOpenValue as a decimal
+func (t PositionTransaction) DeltaOpenValueDecimal() (decimal.Decimal, error) {
+	return parseDecimalPtr("deltaOpenValue", t.DeltaOpenValue)
+}
+
+// DeltaOpenFeeDecimal returns DeltaOpenFee as a decimal
+func (t PositionTransaction) DeltaOpenFeeDecimal() (decimal.Decimal, error) {
+	return parseDecimalPtr("deltaOpenFee", t.DeltaOpenFee)
+}
+
+// DeltaFundingFeeDecimal returns DeltaFundingFee as a decimal
+func (t PositionTransaction) DeltaFundingFeeDecimal() (decimal.Decimal, error) {
+	return parseDecimalPtr("deltaFundingFee", t.DeltaFundingFee)
+}
+
+// BeforeOpenSizeDecimal returns BeforeOpenSize as a decimal
+func (t PositionTransaction) BeforeOpenSizeDecimal() (decimal.Decimal, error) {
+	return parseDecimalPtr("beforeOpenSize", t.BeforeOpenSize)
+}
+
+// BeforeOpenValueDecimal returns BeforeOpenValue as a decimal
+func (t PositionTransaction) BeforeOpenValueDecimal() (decimal.Decimal, error) {
+	return parseDecimalPtr("beforeOpenValue", t.BeforeOpenValue)
+}
+
+// BeforeOpenFeeDecimal returns BeforeOpenFee as a decimal
+func (t PositionTransaction) BeforeOpenFeeDecimal() (decimal.Decimal, error) {
+	return parseDecimalPtr("beforeOpenFee", t.BeforeOpenFee)
+}
+
+// BeforeFundingFeeDecimal returns BeforeFundingFee as a decimal
+func (t PositionTransaction) BeforeFundingFeeDecimal() (decimal.Decimal, error) {
+	return parseDecimalPtr("beforeFundingFee", t.BeforeFundingFee)
+}
+
+// FillCloseSizeDecimal returns FillCloseSize as a decimal
+func (t PositionTransaction) FillCloseSizeDecimal() (decimal.Decimal, error) {
+	return parseDecimalPtr("fillCloseSize", t.FillCloseSize)
+}
+
+// FillCloseValueDecimal returns FillCloseValue as a decimal
+func (t PositionTransaction) FillCloseValueDecimal() (decimal.Decimal, error) {
+	return parseDecimalPtr("fillCloseValue", t.FillCloseValue)
+}
+
+// FillCloseFeeDecimal returns FillCloseFee as a decimal
+func (t PositionTransaction) FillCloseFeeDecimal() (decimal.Decimal, error) {
+	return parseDecimalPtr("fillCloseFee", t.FillCloseFee)
+}
+
+// FillOpenSizeDecimal returns FillOpenSize as a decimal
+func (t PositionTransaction) FillOpenSizeDecimal() (decimal.Decimal, error) {
+	return parseDecimalPtr("fillOpenSize", t.FillOpenSize)
+}
+
+// FillOpenValueDecimal returns FillOpenValue as a decimal
+func (t PositionTransaction) FillOpenValueDecimal() (decimal.Decimal, error) {
+	return parseDecimalPtr("fillOpenValue", t.FillOpenValue)
+}
+
+// FillOpenFeeDecimal returns FillOpenFee as a decimal
+func (t PositionTransaction) FillOpenFeeDecimal() (decimal.Decimal, error) {
+	return parseDecimalPtr("fillOpenFee", t.FillOpenFee)
+}
+
+// FillPriceDecimal returns FillPrice as a decimal
+func (t PositionTransaction) FillPriceDecimal() (decimal.Decimal, error) {
+	return parseDecimalPtr("fillPrice", t.FillPrice)
+}
+
+// LiquidateFeeDecimal returns LiquidateFee as a decimal
+func (t PositionTransaction) LiquidateFeeDecimal() (decimal.Decimal, error) {
+	return parseDecimalPtr("liquidateFee", t.LiquidateFee)
+}
+
+// RealizePnlDecimal returns RealizePnl as a decimal
+func (t PositionTransaction) RealizePnlDecimal() (decimal.Decimal, error) {
+	return parseDecimalPtr("realizePnl", t.RealizePnl)
+}
+
+// FundingRateDecimal returns FundingRate as a decimal
+func (t PositionTransaction) FundingRateDecimal() (decimal.Decimal, error) {
+	return parseDecimalPtr("fundingRate", t.FundingRate)
+}
+
+// FundingIndexPriceDecimal returns FundingIndexPrice as a decimal
+func (t PositionTransaction) FundingIndexPriceDecimal() (decimal.Decimal, error) {
+	return parseDecimalPtr("fundingIndexPrice", t.FundingIndexPrice)
+}
+
+// FundingOraclePriceDecimal returns FundingOraclePrice as a decimal
+func (t PositionTransaction) FundingOraclePriceDecimal() (decimal.Decimal, error) {
+	return parseDecimalPtr("fundingOraclePrice", t.FundingOraclePrice)
+}
+
+// FundingPositionSizeDecimal returns FundingPositionSize as a decimal
+func (t PositionTransaction) FundingPositionSizeDecimal() (decimal.Decimal, error) {
+	return parseDecimalPtr("fundingPositionSize", t.FundingPositionSize)
+}
+
+// CollateralTransaction decimal accessors
+
+// DeltaAmountDecimal returns DeltaAmount as a decimal
+func (t CollateralTransaction) DeltaAmountDecimal() (decimal.Decimal, error) {
+	return parseDecimalPtr("deltaAmount", t.DeltaAmount)
+}
+
+// DeltaLegacyAmountDecimal returns DeltaLegacyAmount as a decimal
+func (t CollateralTransaction) DeltaLegacyAmountDecimal() (decimal.Decimal, error) {
+	return parseDecimalPtr("deltaLegacyAmount", t.DeltaLegacyAmount)
+}
+
+// BeforeAmountDecimal returns BeforeAmount as a decimal
+func (t CollateralTransaction) BeforeAmountDecimal() (decimal.Decimal, error) {
+	return parseDecimalPtr("beforeAmount", t.BeforeAmount)
+}
+
+// BeforeLegacyAmountDecimal returns BeforeLegacyAmount as a decimal
+func (t CollateralTransaction) BeforeLegacyAmountDecimal() (decimal.Decimal, error) {
+	return parseDecimalPtr("beforeLegacyAmount", t.BeforeLegacyAmount)
+}
+
+// FillCloseSizeDecimal returns FillCloseSize as a decimal
+func (t CollateralTransaction) FillCloseSizeDecimal() (decimal.Decimal, error) {
+	return parseDecimalPtr("fillCloseSize", t.FillCloseSize)
+}
+
+// FillCloseValueDecimal returns FillCloseValue as a decimal
+func (t CollateralTransaction) FillCloseValueDecimal() (decimal.Decimal, error) {
+	return parseDecimalPtr("fillCloseValue", t.FillCloseValue)
+}
+
+// FillCloseFeeDecimal returns FillCloseFee as a decimal
+func (t CollateralTransaction) FillCloseFeeDecimal() (decimal.Decimal, error) {
+	return parseDecimalPtr("fillCloseFee", t.FillCloseFee)
+}
+
+// FillOpenSizeDecimal returns FillOpenSize as a decimal
+func (t CollateralTransaction) FillOpenSizeDecimal() (decimal.Decimal, error) {
+	return parseDecimalPtr("fillOpenSize", t.FillOpenSize)
+}
+
+// FillOpenValueDecimal returns FillOpenValue as a decimal
+func (t CollateralTransaction) FillOpenValueDecimal() (decimal.Decimal, error) {
+	return parseDecimalPtr("fillOpenValue", t.FillOpenValue)
+}
+
+// FillOpenFeeDecimal returns FillOpenFee as a decimal
+func (t CollateralTransaction) FillOpenFeeDecimal() (decimal.Decimal, error) {
+	return parseDecimalPtr("fillOpenFee", t.FillOpenFee)
+}
+
+// FillPriceDecimal returns FillPrice as a decimal
+func (t CollateralTransaction) FillPriceDecimal() (decimal.Decimal, error) {
+	return parseDecimalPtr("fillPrice", t.FillPrice)
+}
+
+// LiquidateFeeDecimal returns LiquidateFee as a decimal
+func (t CollateralTransaction) LiquidateFeeDecimal() (decimal.Decimal, error) {
+	return parseDecimalPtr("liquidateFee", t.LiquidateFee)
+}
+
+// RealizePnlDecimal returns RealizePnl as a decimal
+func (t CollateralTransaction) RealizePnlDecimal() (decimal.Decimal, error) {
+	return parseDecimalPtr("realizePnl", t.RealizePnl)
+}
+
+// FundingRateDecimal returns FundingRate as a decimal
+func (t CollateralTransaction) FundingRateDecimal() (decimal.Decimal, error) {
+	return parseDecimalPtr("fundingRate", t.FundingRate)
+}
+
+// FundingIndexPriceDecimal returns FundingIndexPrice as a decimal
+func (t CollateralTransaction) FundingIndexPriceDecimal() (decimal.Decimal, error) {
+	return parseDecimalPtr("fundingIndexPrice", t.FundingIndexPrice)
+}
+
+// FundingOraclePriceDecimal returns FundingOraclePrice as a decimal
+func (t CollateralTransaction) FundingOraclePriceDecimal() (decimal.Decimal, error) {
+	return parseDecimalPtr("fundingOraclePrice", t.FundingOraclePrice)
+}
+
+// FundingPositionSizeDecimal returns FundingPositionSize as a decimal
+func (t CollateralTransaction) FundingPositionSizeDecimal() (decimal.Decimal, error) {
+	return parseDecimalPtr("fundingPositionSize", t.FundingPositionSize)
+}
+
+// PositionTerm decimal accessors
+
+// CumOpenSizeDecimal returns CumOpenSize as a decimal
+func (t PositionTerm) CumOpenSizeDecimal() (decimal.Decimal, error) {
+	return parseDecimalPtr("cumOpenSize", t.CumOpenSize)
+}
+
+// CumOpenValueDecimal returns CumOpenValue as a decimal
+func (t PositionTerm) CumOpenValueDecimal() (decimal.Decimal, error) {
+	return parseDecimalPtr("cumOpenValue", t.CumOpenValue)
+}
+
+// CumOpenFeeDecimal returns CumOpenFee as a decimal
+func (t PositionTerm) CumOpenFeeDecimal() (decimal.Decimal, error) {
+	return parseDecimalPtr("cumOpenFee", t.CumOpenFee)
+}
+
+// CumCloseSizeDecimal returns CumCloseSize as a decimal
+func (t PositionTerm) CumCloseSizeDecimal() (decimal.Decimal, error) {
+	return parseDecimalPtr("cumCloseSize", t.CumCloseSize)
+}
+
+// CumCloseValueDecimal returns CumCloseValue as a decimal
+func (t PositionTerm) CumCloseValueDecimal() (decimal.Decimal, error) {
+	return parseDecimalPtr("cumCloseValue", t.CumCloseValue)
+}
+
+// CumCloseFeeDecimal returns CumCloseFee as a decimal
+func (t PositionTerm) CumCloseFeeDecimal() (decimal.Decimal, error) {
+	return parseDecimalPtr("cumCloseFee", t.CumCloseFee)
+}
+
+// CumFundingFeeDecimal returns CumFundingFee as a decimal
+func (t PositionTerm) CumFundingFeeDecimal() (decimal.Decimal, error) {
+	return parseDecimalPtr("cumFundingFee", t.CumFundingFee)
+}
+
+// CumLiquidateFeeDecimal returns CumLiquidateFee as a decimal
+func (t PositionTerm) CumLiquidateFeeDecimal() (decimal.Decimal, error) {
+	return parseDecimalPtr("cumLiquidateFee", t.CumLiquidateFee)
+}
+
+// CurrentLeverageDecimal returns CurrentLeverage as a decimal
+func (t PositionTerm) CurrentLeverageDecimal() (decimal.Decimal, error) {
+	return parseDecimalPtr("currentLeverage", t.CurrentLeverage)
+}
+
+// AccountAssetSnapshot decimal accessors
+
+// TotalEquityDecimal returns TotalEquity as a decimal
+func (s AccountAssetSnapshot) TotalEquityDecimal() (decimal.Decimal, error) {
+	return parseDecimalPtr("totalEquity", s.TotalEquity)
+}
+
+// TermRealizePnlDecimal returns TermRealizePnl as a decimal
+func (s AccountAssetSnapshot) TermRealizePnlDecimal() (decimal.Decimal, error) {
+	return parseDecimalPtr("termRealizePnl", s.TermRealizePnl)
+}
+
+// UnrealizePnlDecimal returns UnrealizePnl as a decimal
+func (s AccountAssetSnapshot) UnrealizePnlDecimal() (decimal.Decimal, error) {
+	return parseDecimalPtr("unrealizePnl", s.UnrealizePnl)
+}
+
+// TotalRealizePnlDecimal returns TotalRealizePnl as a decimal
+func (s AccountAssetSnapshot) TotalRealizePnlDecimal() (decimal.Decimal, error) {
+	return parseDecimalPtr("totalRealizePnl", s.TotalRealizePnl)
+}
+
+// AvailableAmountDecimal returns AvailableAmount as a decimal
+func (s AccountAssetSnapshot) AvailableAmountDecimal() (decimal.Decimal, error) {
+	return parseDecimalPtr("availableAmount", s.AvailableAmount)
+}
+
+// IsLong reports whether the position is long. A malformed OpenSize is neither long nor short.
+func (p Position) IsLong() bool {
+	size, err := p.OpenSizeDecimal()
+	return err == nil && size.IsPositive()
+}
+
+// IsShort reports whether the position is short. A malformed OpenSize is neither long nor short.
+func (p Position) IsShort() bool {
+	size, err := p.OpenSizeDecimal()
+	return err == nil && size.IsNegative()
+}
+
+// AvgOpenPrice returns the average open price of the position, or zero when it is flat
+func (p Position) AvgOpenPrice() (decimal.Decimal, error) {
+	size, err := p.OpenSizeDecimal()
+	if err != nil || size.IsZero() {
+		return decimal.Zero, err
+	}
+	value, err := p.OpenValueDecimal()
+	if err != nil {
+		return decimal.Zero, err
+	}
+	return value.Div(size).Abs(), nil
+}
+
+// TradeSetting decimal accessors
+
+// MaxLeverageDecimal returns MaxLeverage as a decimal
+func (s TradeSetting) MaxLeverageDecimal() (decimal.Decimal, error) {
+	return parseDecimal("maxLeverage", s.MaxLeverage)
+}
@@ -0,0 +1,306 @@
+package account
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+// Granularity is the spacing of an equity curve
+type Granularity int32
+
+const (
+	// GranularityDefault selects the default granularity, daily snapshots
+	GranularityDefault Granularity = 0
+	// GranularityHour selects hourly snapshots
+	GranularityHour Granularity = 1
+	// GranularityDay selects daily snapshots
+	GranularityDay Granularity = 2
+)
+
+// timeTag returns the filterTimeTag of the granularity: 0 for hourly and 1 for
+// daily snapshots
+func (g Granularity) timeTag() int32 {
+	if g == GranularityHour {
+		return 0
+	}
+	return 1
+}
+
+// Duration returns the spacing of the granularity
+func (g Granularity) Duration() time.Duration {
+	if g == GranularityHour {
+		return time.Hour
+	}
+	return 24 * time.Hour
+}
+
+// periodsPerYear returns the number of periods used to annualize volatility
+func (g Granularity) periodsPerYear() float64 {
+	return float64(365*24*time.Hour) / float64(g.Duration())
+}
+
+// snapshotPageSize is the page size used when paging asset snapshots
+const snapshotPageSize = 100
+
+// EquityPoint is one point of an equity curve
+type EquityPoint struct {
+	Time        time.Time
+	TotalEquity decimal.Decimal
+	// Available is the available amount of the snapshot. The published snapshot
+	// schema does not include it, so GetEquityCurve sets the last point of a curve
+	// up to now to the current available amount when the snapshots lack it.
+	Available       decimal.Decimal
+	UnrealizePnl    decimal.Decimal
+	TotalRealizePnl decimal.Decimal
+	// Filled is set on points carried forward over a missing snapshot
+	Filled bool
+}
+
+// EquityStats holds statistics derived from an equity curve. Returns are computed on
+// total equity, so deposits, withdrawals and transfers show up as returns.
+type EquityStats struct {
+	StartEquity decimal.Decimal
+	EndEquity   decimal.Decimal
+	// TotalReturn is EndEquity / StartEquity - 1
+	TotalReturn decimal.Decimal
+	// PnL is the change in realized plus unrealized PnL, unaffected by transfers
+	PnL decimal.Decimal
+	// MaxDrawdown is the largest peak to trough equity drop, as an amount and as a
+	// fraction of the peak
+	MaxDrawdown        decimal.Decimal
+	MaxDrawdownPercent decimal.Decimal
+	PeakTime           time.Time
+	TroughTime         time.Time
+	// Volatility is the standard deviation of period returns
+	Volatility           float64
+	AnnualizedVolatility float64
+}
+
+// EquityCurve is a time series of account equity at a fixed granularity
+type EquityCurve struct {
+	CoinID      string
+	Granularity Granularity
+	Points      []EquityPoint
+	// Returns holds the return of each period, one fewer than Points
+	Returns []decimal.Decimal
+	Stats   EquityStats
+}
+
+// GetEquityCurveParams represents the parameters for GetEquityCurve
+type GetEquityCurveParams struct {
+	CoinID      string
+	Granularity Granularity
+	// From is inclusive and To exclusive. A zero To means up to now.
+	From time.Time
+	To   time.Time
+}
+
+// GetEquityCurve pages the asset snapshots of a window and builds an equity curve from them
+func (c *Client) GetEquityCurve(ctx context.Context, params GetEquityCurveParams) (*EquityCurve, error) {
+	if params.Granularity == GranularityDefault {
+		params.Granularity = GranularityDay
+	}
+	to := params.To
+	if to.IsZero() {
+		to = time.Now()
+	}
+
+	timeTag := params.Granularity.timeTag()
+	pageParams := GetAccountAssetSnapshotPageParams{
+		Size:            snapshotPageSize,
+		CoinID:          params.CoinID,
+		FilterTimeTag:   &timeTag,
+		FilterStartTime: params.From.UnixMilli(),
+		FilterEndTime:   to.UnixMilli(),
+	}
+
+	var snapshots []AccountAssetSnapshot
+	for {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		resp, err := c.GetAccountAssetSnapshotPage(ctx, pageParams)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get equity curve: %w", err)
+		}
+		if resp.Data == nil {
+			break
+		}
+		snapshots = append(snapshots, resp.Data.DataList...)
+		next := resp.Data.NextPageOffsetData
+		if next == nil || *next == "" || len(resp.Data.DataList) == 0 {
+			break
+		}
+		pageParams.OffsetData = *next
+	}
+
+	curve := NewEquityCurve(snapshots, params.Granularity)
+	curve.CoinID = params.CoinID
+	if params.To.IsZero() && len(curve.Points) > 0 && !hasAvailable(snapshots) {
+		resp, err := c.GetAccountAssetData(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get equity curve: %w", err)
+		}
+		if asset, ok := currentCollateralAsset(resp.Data, params.CoinID); ok {
+			available, err := asset.AvailableAmountDecimal()
+			if err != nil {
+				return nil, fmt.Errorf("failed to get equity curve: %w", err)
+			}
+			curve.Points[len(curve.Points)-1].Available = available
+		}
+	}
+	return curve, nil
+}
+
+// NewEquityCurve builds an equity curve from snapshots. Points are aligned to the
+// granularity and gaps between the first and last snapshot are filled by carrying
+// the previous point forward. Snapshots with a malformed time or amount are
+// treated as missing.
+func NewEquityCurve(snapshots []AccountAssetSnapshot, granularity Granularity) *EquityCurve {
+	if granularity == GranularityDefault {
+		granularity = GranularityDay
+	}
+	step := granularity.Duration()
+	curve := &EquityCurve{Granularity: granularity}
+
+	byTime := make(map[time.Time]EquityPoint, len(snapshots))
+	for _, snapshot := range snapshots {
+		if snapshot.SnapshotTime == nil {
+			continue
+		}
+		ms, err := strconv.ParseInt(*snapshot.SnapshotTime, 10, 64)
+		if err != nil {
+			continue
+		}
+		point, err := equityPoint(snapshot)
+		if err != nil {
+			continue
+		}
+		point.Time = time.UnixMilli(ms).UTC().Truncate(step)
+		byTime[point.Time] = point
+	}
+	if len(byTime) == 0 {
+		return curve
+	}
+
+	times := make([]time.Time, 0, len(byTime))
+	for t := range byTime {
+		times = append(times, t)
+	}
+	sort.Slice(times, func(i, j int) bool { return times[i].Before(times[j]) })
+
+	for t := times[0]; !t.After(times[len(times)-1]); t = t.Add(step) {
+		point, ok := byTime[t]
+		if !ok {
+			point = curve.Points[len(curve.Points)-1]
+			point.Time = t
+			point.Filled = true
+		}
+		curve.Points = append(curve.Points, point)
+	}
+
+	curve.computeStats()
+	return curve
+}
+
+// equityPoint reads the amounts of a snapshot
+func equityPoint(snapshot AccountAssetSnapshot) (EquityPoint, error) {
+	var point EquityPoint
+	var err error
+	if point.TotalEquity, err = snapshot.TotalEquityDecimal(); err != nil {
+		return point, err
+	}
+	if point.Available, err = snapshot.AvailableAmountDecimal(); err != nil {
+		return point, err
+	}
+	if point.UnrealizePnl, err = snapshot.UnrealizePnlDecimal(); err != nil {
+		return point, err
+	}
+	point.TotalRealizePnl, err = snapshot.TotalRealizePnlDecimal()
+	return point, err
+}
+
+// hasAvailable reports whether any snapshot carries the available amount
+func hasAvailable(snapshots []AccountAssetSnapshot) bool {
+	for _, snapshot := range snapshots {
+		if snapshot.AvailableAmount != nil {
+			return true
+		}
+	}
+	return false
+}
+
+// currentCollateralAsset returns the collateral asset of a coin, or the only one if
+// no coin is given
+func currentCollateralAsset(data AccountAssetData, coinID string) (CollateralAsset, bool) {
+	if coinID != "" {
+		return data.CollateralAsset(coinID)
+	}
+	if len(data.CollateralAssetList) == 1 {
+		return data.CollateralAssetList[0], true
+	}
+	return CollateralAsset{}, false
+}
+
+// computeStats derives returns, drawdown and volatility from the points
+func (c *EquityCurve) computeStats() {
+	first, last := c.Points[0], c.Points[len(c.Points)-1]
+	stats := EquityStats{
+		StartEquity: first.TotalEquity,
+		EndEquity:   last.TotalEquity,
+		PnL: last.TotalRealizePnl.Add(last.UnrealizePnl).
+			Sub(first.TotalRealizePnl).Sub(first.UnrealizePnl),
+	}
+	if first.TotalEquity.IsPositive() {
+		stats.TotalReturn = last.TotalEquity.Div(first.TotalEquity).Sub(decimal.NewFromInt(1))
+	}
+
+	peak := first
+	var returns []float64
+	for i, point := range c.Points {
+		if i > 0 {
+			previous := c.Points[i-1].TotalEquity
+			var r decimal.Decimal
+			if previous.IsPositive() {
+				r = point.TotalEquity.Div(previous).Sub(decimal.NewFromInt(1))
+			}
+			c.Returns = append(c.Returns, r)
+			returns = append(returns, r.InexactFloat64())
+		}
+
+		if point.TotalEquity.GreaterThan(peak.TotalEquity) {
+			peak = point
+		}
+		drawdown := peak.TotalEquity.Sub(point.TotalEquity)
+		if drawdown.GreaterThan(stats.MaxDrawdown) {
+			stats.MaxDrawdown = drawdown
+			stats.PeakTime = peak.Time
+			stats.TroughTime = point.Time
+			if peak.TotalEquity.IsPositive() {
+				stats.MaxDrawdownPercent = drawdown.Div(peak.TotalEquity)
+			}
+		}
+	}
+
+	if len(returns) > 1 {
+		var mean float64
+		for _, r := range returns {
+			mean += r
+		}
+		mean /= float64(len(returns))
+		var variance float64
+		for _, r := range returns {
+			variance += (r - mean) * (r - mean)
+		}
+		variance /= float64(len(returns) - 1)
+		stats.Volatility = math.Sqrt(variance)
+		stats.AnnualizedVolatility = stats.Volatility * math.Sqrt(c.Granularity.periodsPerYear())
+	}
+	c.Stats = stats
+}
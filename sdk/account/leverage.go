@@ -0,0 +1,67 @@
+package account
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/shopspring/decimal"
+)
+
+// LeverageSettings holds the leverage settings of an account
+type LeverageSettings struct {
+	// Default is the account-wide leverage, zero if not set
+	Default decimal.Decimal
+	// Contracts holds the leverage of contracts with an explicit setting
+	Contracts map[string]decimal.Decimal
+}
+
+// Leverage returns the leverage setting of a contract, falling back to the account
+// default. It reports false when neither is set and the contract default leverage applies.
+func (s LeverageSettings) Leverage(contractID string) (decimal.Decimal, bool) {
+	if leverage, ok := s.Contracts[contractID]; ok {
+		return leverage, true
+	}
+	if s.Default.IsPositive() {
+		return s.Default, true
+	}
+	return decimal.Zero, false
+}
+
+// LeverageSettings returns the leverage settings from the account trade settings
+func (a Account) LeverageSettings() (LeverageSettings, error) {
+	settings := LeverageSettings{Contracts: make(map[string]decimal.Decimal, len(a.ContractIDToTradeSetting))}
+	if a.DefaultTradeSetting.IsSetMaxLeverage {
+		leverage, err := a.DefaultTradeSetting.MaxLeverageDecimal()
+		if err != nil {
+			return LeverageSettings{}, fmt.Errorf("default trade setting: %w", err)
+		}
+		settings.Default = leverage
+	}
+	for contractID, setting := range a.ContractIDToTradeSetting {
+		if setting.IsSetMaxLeverage {
+			leverage, err := setting.MaxLeverageDecimal()
+			if err != nil {
+				return LeverageSettings{}, fmt.Errorf("trade setting of contract %s: %w", contractID, err)
+			}
+			settings.Contracts[contractID] = leverage
+		}
+	}
+	return settings, nil
+}
+
+// GetLeverageSettings gets the leverage settings of the account
+func (c *Client) GetLeverageSettings(ctx context.Context) (*LeverageSettings, error) {
+	resp, err := c.GetAccountByID(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get leverage settings: %w", err)
+	}
+	if resp.Data == nil {
+		return nil, fmt.Errorf("failed to get leverage settings: no account data")
+	}
+
+	settings, err := resp.Data.LeverageSettings()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get leverage settings: %w", err)
+	}
+	return &settings, nil
+}
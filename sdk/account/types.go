@@ -4,16 +4,61 @@ import "github.com/coin-quant/go-edgex/openapi"
 
 // PositionTransaction represents a position transaction
 type PositionTransaction struct {
-	Id          *string `json:"id,omitempty"`
-	UserId      *string `json:"userId,omitempty"`
-	AccountId   *string `json:"accountId,omitempty"`
-	ContractId  *string `json:"contractId,omitempty"`
-	CoinId      *string `json:"coinId,omitempty"`
-	Type        *string `json:"type,omitempty"`
-	Size        *string `json:"size,omitempty"`
-	Price       *string `json:"price,omitempty"`
-	Fee         *string `json:"fee,omitempty"`
-	CreatedTime *string `json:"createdTime,omitempty"`
+	Id                      *string `json:"id,omitempty"`
+	UserId                  *string `json:"userId,omitempty"`
+	AccountId               *string `json:"accountId,omitempty"`
+	CoinId                  *string `json:"coinId,omitempty"`
+	ContractId              *string `json:"contractId,omitempty"`
+	Type                    *string `json:"type,omitempty"`
+	DeltaOpenSize           *string `json:"deltaOpenSize,omitempty"`
+	DeltaOpenValue          *string `json:"deltaOpenValue,omitempty"`
+	DeltaOpenFee            *string `json:"deltaOpenFee,omitempty"`
+	DeltaFundingFee         *string `json:"deltaFundingFee,omitempty"`
+	BeforeOpenSize          *string `json:"beforeOpenSize,omitempty"`
+	BeforeOpenValue         *string `json:"beforeOpenValue,omitempty"`
+	BeforeOpenFee           *string `json:"beforeOpenFee,omitempty"`
+	BeforeFundingFee        *string `json:"beforeFundingFee,omitempty"`
+	FillCloseSize           *string `json:"fillCloseSize,omitempty"`
+	FillCloseValue          *string `json:"fillCloseValue,omitempty"`
+	FillCloseFee            *string `json:"fillCloseFee,omitempty"`
+	FillOpenSize            *string `json:"fillOpenSize,omitempty"`
+	FillOpenValue           *string `json:"fillOpenValue,omitempty"`
+	FillOpenFee             *string `json:"fillOpenFee,omitempty"`
+	FillPrice               *string `json:"fillPrice,omitempty"`
+	LiquidateFee            *string `json:"liquidateFee,omitempty"`
+	RealizePnl              *string `json:"realizePnl,omitempty"`
+	IsLiquidate             *bool   `json:"isLiquidate,omitempty"`
+	IsDeleverage            *bool   `json:"isDeleverage,omitempty"`
+	FundingTime             *string `json:"fundingTime,omitempty"`
+	FundingRate             *string `json:"fundingRate,omitempty"`
+	FundingIndexPrice       *string `json:"fundingIndexPrice,omitempty"`
+	FundingOraclePrice      *string `json:"fundingOraclePrice,omitempty"`
+	FundingPositionSize     *string `json:"fundingPositionSize,omitempty"`
+	OrderId                 *string `json:"orderId,omitempty"`
+	OrderFillTransactionId  *string `json:"orderFillTransactionId,omitempty"`
+	CollateralTransactionId *string `json:"collateralTransactionId,omitempty"`
+	ForceTradeId            *string `json:"forceTradeId,omitempty"`
+	ExtraType               *string `json:"extraType,omitempty"`
+	ExtraDataJson           *string `json:"extraDataJson,omitempty"`
+	CensorStatus            *string `json:"censorStatus,omitempty"`
+	CensorTxId              *string `json:"censorTxId,omitempty"`
+	CensorTime              *string `json:"censorTime,omitempty"`
+	CensorFailCode          *string `json:"censorFailCode,omitempty"`
+	CensorFailReason        *string `json:"censorFailReason,omitempty"`
+	L2TxId                  *string `json:"l2TxId,omitempty"`
+	L2RejectTime            *string `json:"l2RejectTime,omitempty"`
+	L2RejectCode            *string `json:"l2RejectCode,omitempty"`
+	L2RejectReason          *string `json:"l2RejectReason,omitempty"`
+	L2ApprovedTime          *string `json:"l2ApprovedTime,omitempty"`
+	CreatedTime             *string `json:"createdTime,omitempty"`
+	UpdatedTime             *string `json:"updatedTime,omitempty"`
+
+	// Deprecated: not returned by the API, kept for compatibility
+	Size *string `json:"size,omitempty"`
+	// Deprecated: not returned by the API, use FillPrice
+	Price *string `json:"price,omitempty"`
+	// Deprecated: not returned by the API, use FillOpenFee and FillCloseFee
+	Fee *string `json:"fee,omitempty"`
 }
 
 // PageDataPositionTransaction represents paginated position transaction data
@@ -24,13 +69,60 @@ type PageDataPositionTransaction struct {
 
 // CollateralTransaction represents a collateral transaction
 type CollateralTransaction struct {
-	Id          *string `json:"id,omitempty"`
-	UserId      *string `json:"userId,omitempty"`
-	AccountId   *string `json:"accountId,omitempty"`
-	CoinId      *string `json:"coinId,omitempty"`
-	Type        *string `json:"type,omitempty"`
-	Amount      *string `json:"amount,omitempty"`
-	CreatedTime *string `json:"createdTime,omitempty"`
+	Id                     *string `json:"id,omitempty"`
+	UserId                 *string `json:"userId,omitempty"`
+	AccountId              *string `json:"accountId,omitempty"`
+	CoinId                 *string `json:"coinId,omitempty"`
+	Type                   *string `json:"type,omitempty"`
+	DeltaAmount            *string `json:"deltaAmount,omitempty"`
+	DeltaLegacyAmount      *string `json:"deltaLegacyAmount,omitempty"`
+	BeforeAmount           *string `json:"beforeAmount,omitempty"`
+	BeforeLegacyAmount     *string `json:"beforeLegacyAmount,omitempty"`
+	FillCloseSize          *string `json:"fillCloseSize,omitempty"`
+	FillCloseValue         *string `json:"fillCloseValue,omitempty"`
+	FillCloseFee           *string `json:"fillCloseFee,omitempty"`
+	FillOpenSize           *string `json:"fillOpenSize,omitempty"`
+	FillOpenValue          *string `json:"fillOpenValue,omitempty"`
+	FillOpenFee            *string `json:"fillOpenFee,omitempty"`
+	FillPrice              *string `json:"fillPrice,omitempty"`
+	LiquidateFee           *string `json:"liquidateFee,omitempty"`
+	RealizePnl             *string `json:"realizePnl,omitempty"`
+	IsLiquidate            *bool   `json:"isLiquidate,omitempty"`
+	IsDeleverage           *bool   `json:"isDeleverage,omitempty"`
+	FundingTime            *string `json:"fundingTime,omitempty"`
+	FundingRate            *string `json:"fundingRate,omitempty"`
+	FundingIndexPrice      *string `json:"fundingIndexPrice,omitempty"`
+	FundingOraclePrice     *string `json:"fundingOraclePrice,omitempty"`
+	FundingPositionSize    *string `json:"fundingPositionSize,omitempty"`
+	DepositId              *string `json:"depositId,omitempty"`
+	WithdrawId             *string `json:"withdrawId,omitempty"`
+	TransferInId           *string `json:"transferInId,omitempty"`
+	TransferOutId          *string `json:"transferOutId,omitempty"`
+	TransferReason         *string `json:"transferReason,omitempty"`
+	OrderId                *string `json:"orderId,omitempty"`
+	OrderFillTransactionId *string `json:"orderFillTransactionId,omitempty"`
+	OrderAccountId         *string `json:"orderAccountId,omitempty"`
+	PositionContractId     *string `json:"positionContractId,omitempty"`
+	PositionTransactionId  *string `json:"positionTransactionId,omitempty"`
+	ForceWithdrawId        *string `json:"forceWithdrawId,omitempty"`
+	ForceTradeId           *string `json:"forceTradeId,omitempty"`
+	ExtraType              *string `json:"extraType,omitempty"`
+	ExtraDataJson          *string `json:"extraDataJson,omitempty"`
+	CensorStatus           *string `json:"censorStatus,omitempty"`
+	CensorTxId             *string `json:"censorTxId,omitempty"`
+	CensorTime             *string `json:"censorTime,omitempty"`
+	CensorFailCode         *string `json:"censorFailCode,omitempty"`
+	CensorFailReason       *string `json:"censorFailReason,omitempty"`
+	L2TxId                 *string `json:"l2TxId,omitempty"`
+	L2RejectTime           *string `json:"l2RejectTime,omitempty"`
+	L2RejectCode           *string `json:"l2RejectCode,omitempty"`
+	L2RejectReason         *string `json:"l2RejectReason,omitempty"`
+	L2ApprovedTime         *string `json:"l2ApprovedTime,omitempty"`
+	CreatedTime            *string `json:"createdTime,omitempty"`
+	UpdatedTime            *string `json:"updatedTime,omitempty"`
+
+	// Deprecated: not returned by the API, use DeltaAmount
+	Amount *string `json:"amount,omitempty"`
 }
 
 // PageDataCollateralTransaction represents paginated collateral transaction data
@@ -41,15 +133,31 @@ type PageDataCollateralTransaction struct {
 
 // PositionTerm represents a position term
 type PositionTerm struct {
-	Id             *string `json:"id,omitempty"`
-	UserId         *string `json:"userId,omitempty"`
-	AccountId      *string `json:"accountId,omitempty"`
-	ContractId     *string `json:"contractId,omitempty"`
-	CoinId         *string `json:"coinId,omitempty"`
-	IsLongPosition *bool   `json:"isLongPosition,omitempty"`
-	Size           *string `json:"size,omitempty"`
-	Price          *string `json:"price,omitempty"`
-	CreatedTime    *string `json:"createdTime,omitempty"`
+	UserId          *string `json:"userId,omitempty"`
+	AccountId       *string `json:"accountId,omitempty"`
+	CoinId          *string `json:"coinId,omitempty"`
+	ContractId      *string `json:"contractId,omitempty"`
+	TermCount       *int32  `json:"termCount,omitempty"`
+	CumOpenSize     *string `json:"cumOpenSize,omitempty"`
+	CumOpenValue    *string `json:"cumOpenValue,omitempty"`
+	CumOpenFee      *string `json:"cumOpenFee,omitempty"`
+	CumCloseSize    *string `json:"cumCloseSize,omitempty"`
+	CumCloseValue   *string `json:"cumCloseValue,omitempty"`
+	CumCloseFee     *string `json:"cumCloseFee,omitempty"`
+	CumFundingFee   *string `json:"cumFundingFee,omitempty"`
+	CumLiquidateFee *string `json:"cumLiquidateFee,omitempty"`
+	CurrentLeverage *string `json:"currentLeverage,omitempty"`
+	CreatedTime     *string `json:"createdTime,omitempty"`
+	UpdatedTime     *string `json:"updatedTime,omitempty"`
+
+	// Deprecated: not returned by the API, kept for compatibility
+	Id *string `json:"id,omitempty"`
+	// Deprecated: not returned by the API, kept for compatibility
+	IsLongPosition *bool `json:"isLongPosition,omitempty"`
+	// Deprecated: not returned by the API, use CumOpenSize and CumCloseSize
+	Size *string `json:"size,omitempty"`
+	// Deprecated: not returned by the API, kept for compatibility
+	Price *string `json:"price,omitempty"`
 }
 
 // PageDataPositionTerm represents paginated position term data
@@ -60,12 +168,23 @@ type PageDataPositionTerm struct {
 
 // AccountAssetSnapshot represents an account asset snapshot
 type AccountAssetSnapshot struct {
-	Id          *string `json:"id,omitempty"`
-	UserId      *string `json:"userId,omitempty"`
-	AccountId   *string `json:"accountId,omitempty"`
-	CoinId      *string `json:"coinId,omitempty"`
-	Amount      *string `json:"amount,omitempty"`
-	TimeTag     *int32  `json:"timeTag,omitempty"`
+	UserId          *string `json:"userId,omitempty"`
+	AccountId       *string `json:"accountId,omitempty"`
+	CoinId          *string `json:"coinId,omitempty"`
+	TimeTag         *int32  `json:"timeTag,omitempty"`
+	SnapshotTime    *string `json:"snapshotTime,omitempty"`
+	TotalEquity     *string `json:"totalEquity,omitempty"`
+	TermRealizePnl  *string `json:"termRealizePnl,omitempty"`
+	UnrealizePnl    *string `json:"unrealizePnl,omitempty"`
+	TotalRealizePnl *string `json:"totalRealizePnl,omitempty"`
+	// AvailableAmount is not in the published schema and is only set when returned
+	AvailableAmount *string `json:"availableAmount,omitempty"`
+
+	// Deprecated: not returned by the API, kept for compatibility
+	Id *string `json:"id,omitempty"`
+	// Deprecated: not returned by the API, use TotalEquity
+	Amount *string `json:"amount,omitempty"`
+	// Deprecated: not returned by the API, use SnapshotTime
 	CreatedTime *string `json:"createdTime,omitempty"`
 }
 
@@ -88,23 +207,195 @@ type GetAccountAssetResponse struct {
 	ErrorMsg   string                  `json:"msg"`
 }
 
+// GetAccountAssetDataResponse represents the response for GetAccountAssetData
+type GetAccountAssetDataResponse struct {
+	Code       string           `json:"code"`
+	Data       AccountAssetData `json:"data"`
+	ErrorParam interface{}      `json:"errorParam"`
+	ErrorMsg   string           `json:"msg"`
+}
+
 // AccountAssetData contains account asset information
 type AccountAssetData struct {
-	PositionList   []Position   `json:"positionList"`
-	CollateralList []Collateral `json:"collateralList"`
+	Account             *Account          `json:"account,omitempty"`
+	CollateralList      []Collateral      `json:"collateralList"`
+	PositionList        []Position        `json:"positionList"`
+	Version             string            `json:"version"`
+	PositionAssetList   []PositionAsset   `json:"positionAssetList"`
+	CollateralAssetList []CollateralAsset `json:"collateralAssetModelList"`
+	OraclePriceList     []OraclePrice     `json:"oraclePriceList"`
+}
+
+// Position returns the position for a contract
+func (d AccountAssetData) Position(contractID string) (Position, bool) {
+	for _, position := range d.PositionList {
+		if position.ContractID == contractID {
+			return position, true
+		}
+	}
+	return Position{}, false
+}
+
+// PositionAsset returns the position asset for a contract
+func (d AccountAssetData) PositionAsset(contractID string) (PositionAsset, bool) {
+	for _, asset := range d.PositionAssetList {
+		if asset.ContractID == contractID {
+			return asset, true
+		}
+	}
+	return PositionAsset{}, false
+}
+
+// Collateral returns the collateral for a coin
+func (d AccountAssetData) Collateral(coinID string) (Collateral, bool) {
+	for _, collateral := range d.CollateralList {
+		if collateral.CoinID == coinID {
+			return collateral, true
+		}
+	}
+	return Collateral{}, false
+}
+
+// CollateralAsset returns the collateral asset for a coin
+func (d AccountAssetData) CollateralAsset(coinID string) (CollateralAsset, bool) {
+	for _, asset := range d.CollateralAssetList {
+		if asset.CoinID == coinID {
+			return asset, true
+		}
+	}
+	return CollateralAsset{}, false
 }
 
-// Position represents a position
+// OraclePrice returns the oracle price for a contract
+func (d AccountAssetData) OraclePrice(contractID string) (OraclePrice, bool) {
+	for _, price := range d.OraclePriceList {
+		if price.ContractID == contractID {
+			return price, true
+		}
+	}
+	return OraclePrice{}, false
+}
+
+// Position represents a position. OpenSize is positive for long and negative for short positions.
 type Position struct {
-	ContractID string `json:"contractId"`
-	Size       string `json:"size"`
-	Price      string `json:"price"`
+	UserID               string       `json:"userId"`
+	AccountID            string       `json:"accountId"`
+	CoinID               string       `json:"coinId"`
+	ContractID           string       `json:"contractId"`
+	OpenSize             string       `json:"openSize"`
+	OpenValue            string       `json:"openValue"`
+	OpenFee              string       `json:"openFee"`
+	FundingFee           string       `json:"fundingFee"`
+	LongTermCount        int32        `json:"longTermCount"`
+	LongTermStat         PositionStat `json:"longTermStat"`
+	LongTermCreatedTime  string       `json:"longTermCreatedTime"`
+	LongTermUpdatedTime  string       `json:"longTermUpdatedTime"`
+	ShortTermCount       int32        `json:"shortTermCount"`
+	ShortTermStat        PositionStat `json:"shortTermStat"`
+	ShortTermCreatedTime string       `json:"shortTermCreatedTime"`
+	ShortTermUpdatedTime string       `json:"shortTermUpdatedTime"`
+	LongTotalStat        PositionStat `json:"longTotalStat"`
+	ShortTotalStat       PositionStat `json:"shortTotalStat"`
+	CreatedTime          string       `json:"createdTime"`
+	UpdatedTime          string       `json:"updatedTime"`
+
+	// Deprecated: not returned by the API, use OpenSize
+	Size string `json:"size,omitempty"`
+	// Deprecated: not returned by the API, use AvgOpenPrice
+	Price string `json:"price,omitempty"`
+}
+
+// PositionStat holds cumulative statistics of a position term or of all terms on one side
+type PositionStat struct {
+	CumOpenSize     string `json:"cumOpenSize"`
+	CumOpenValue    string `json:"cumOpenValue"`
+	CumOpenFee      string `json:"cumOpenFee"`
+	CumCloseSize    string `json:"cumCloseSize"`
+	CumCloseValue   string `json:"cumCloseValue"`
+	CumCloseFee     string `json:"cumCloseFee"`
+	CumFundingFee   string `json:"cumFundingFee"`
+	CumLiquidateFee string `json:"cumLiquidateFee"`
+}
+
+// PositionAsset holds the risk and PnL figures of a position at the current oracle price
+type PositionAsset struct {
+	UserID                   string `json:"userId"`
+	AccountID                string `json:"accountId"`
+	CoinID                   string `json:"coinId"`
+	ContractID               string `json:"contractId"`
+	PositionValue            string `json:"positionValue"`
+	MaxLeverage              string `json:"maxLeverage"`
+	InitialMarginRequirement string `json:"initialMarginRequirement"`
+	StarkExRiskRate          string `json:"starkExRiskRate"`
+	StarkExRiskValue         string `json:"starkExRiskValue"`
+	AvgEntryPrice            string `json:"avgEntryPrice"`
+	LiquidatePrice           string `json:"liquidatePrice"`
+	BankruptPrice            string `json:"bankruptPrice"`
+	WorstClosePrice          string `json:"worstClosePrice"`
+	UnrealizePnl             string `json:"unrealizePnl"`
+	TermRealizePnl           string `json:"termRealizePnl"`
+	TotalRealizePnl          string `json:"totalRealizePnl"`
 }
 
 // Collateral represents collateral information
 type Collateral struct {
-	CoinID string `json:"coinId"`
-	Amount string `json:"amount"`
+	UserID                 string `json:"userId"`
+	AccountID              string `json:"accountId"`
+	CoinID                 string `json:"coinId"`
+	Amount                 string `json:"amount"`
+	LegacyAmount           string `json:"legacyAmount"`
+	CumDepositAmount       string `json:"cumDepositAmount"`
+	CumWithdrawAmount      string `json:"cumWithdrawAmount"`
+	CumTransferInAmount    string `json:"cumTransferInAmount"`
+	CumTransferOutAmount   string `json:"cumTransferOutAmount"`
+	CumPositionBuyAmount   string `json:"cumPositionBuyAmount"`
+	CumPositionSellAmount  string `json:"cumPositionSellAmount"`
+	CumFillFeeAmount       string `json:"cumFillFeeAmount"`
+	CumFundingFeeAmount    string `json:"cumFundingFeeAmount"`
+	CumFillFeeIncomeAmount string `json:"cumFillFeeIncomeAmount"`
+	CreatedTime            string `json:"createdTime"`
+	UpdatedTime            string `json:"updatedTime"`
+}
+
+// CollateralAsset holds the equity and margin figures of a collateral coin
+type CollateralAsset struct {
+	UserID                   string `json:"userId"`
+	AccountID                string `json:"accountId"`
+	CoinID                   string `json:"coinId"`
+	TotalEquity              string `json:"totalEquity"`
+	TotalPositionValueAbs    string `json:"totalPositionValueAbs"`
+	InitialMarginRequirement string `json:"initialMarginRequirement"`
+	StarkExRiskValue         string `json:"starkExRiskValue"`
+	PendingWithdrawAmount    string `json:"pendingWithdrawAmount"`
+	PendingTransferOutAmount string `json:"pendingTransferOutAmount"`
+	OrderFrozenAmount        string `json:"orderFrozenAmount"`
+	AvailableAmount          string `json:"availableAmount"`
+}
+
+// OraclePrice represents an oracle price used to value positions
+type OraclePrice struct {
+	ContractID           string                 `json:"contractId"`
+	PriceType            string                 `json:"priceType"`
+	PriceValue           string                 `json:"priceValue"`
+	CreatedTime          string                 `json:"createdTime"`
+	OraclePriceSignature []OraclePriceSignature `json:"oraclePriceSignature,omitempty"`
+}
+
+// OraclePriceSignature represents a signed oracle price
+type OraclePriceSignature struct {
+	ContractID      string       `json:"contractId"`
+	Signer          string       `json:"signer"`
+	Price           string       `json:"price"`
+	ExternalAssetID string       `json:"externalAssetId"`
+	Signature       *L2Signature `json:"signature,omitempty"`
+	Timestamp       string       `json:"timestamp"`
+}
+
+// L2Signature represents a Stark signature
+type L2Signature struct {
+	R string `json:"r"`
+	S string `json:"s"`
+	V string `json:"v"`
 }
 
 // ListPositionResponse represents the response for GetAccountPositions
@@ -191,10 +482,36 @@ type AccountResponse struct {
 
 // Account represents account information
 type Account struct {
-	ID         string `json:"id"`
-	UserID     string `json:"userId"`
-	EthAddress string `json:"ethAddress"`
-	L2Key      string `json:"l2Key"`
+	ID                        string                  `json:"id"`
+	UserID                    string                  `json:"userId"`
+	EthAddress                string                  `json:"ethAddress"`
+	L2Key                     string                  `json:"l2Key"`
+	L2KeyYCoordinate          string                  `json:"l2KeyYCoordinate"`
+	ClientAccountID           string                  `json:"clientAccountId"`
+	IsSystemAccount           bool                    `json:"isSystemAccount"`
+	DefaultTradeSetting       TradeSetting            `json:"defaultTradeSetting"`
+	ContractIDToTradeSetting  map[string]TradeSetting `json:"contractIdToTradeSetting"`
+	MaxLeverageLimit          string                  `json:"maxLeverageLimit"`
+	CreateOrderPerMinuteLimit int32                   `json:"createOrderPerMinuteLimit"`
+	CreateOrderDelayMillis    int32                   `json:"createOrderDelayMillis"`
+	ExtraType                 string                  `json:"extraType"`
+	ExtraDataJson             string                  `json:"extraDataJson"`
+	Status                    string                  `json:"status"`
+	IsLiquidating             bool                    `json:"isLiquidating"`
+	CreatedTime               string                  `json:"createdTime"`
+	UpdatedTime               string                  `json:"updatedTime"`
+}
+
+// TradeSetting represents the fee and leverage settings of an account or contract
+type TradeSetting struct {
+	IsSetFeeRate     bool   `json:"isSetFeeRate"`
+	TakerFeeRate     string `json:"takerFeeRate"`
+	MakerFeeRate     string `json:"makerFeeRate"`
+	IsSetFeeDiscount bool   `json:"isSetFeeDiscount"`
+	TakerFeeDiscount string `json:"takerFeeDiscount"`
+	MakerFeeDiscount string `json:"makerFeeDiscount"`
+	IsSetMaxLeverage bool   `json:"isSetMaxLeverage"`
+	MaxLeverage      string `json:"maxLeverage"`
 }
 
 // GetAccountAssetSnapshotPageParams represents the parameters for GetAccountAssetSnapshotPage
@@ -217,18 +534,45 @@ type PageDataAccountAssetSnapshotResponse struct {
 
 // ListPositionTransactionResponse represents the response for GetPositionTransactionByID
 type ListPositionTransactionResponse struct {
-	Code       string        `json:"code"`
-	Data       []interface{} `json:"data"`
-	ErrorParam interface{}   `json:"errorParam"`
-	ErrorMsg   string        `json:"msg"`
+	Code       string                `json:"code"`
+	Data       []PositionTransaction `json:"data"`
+	ErrorParam interface{}           `json:"errorParam"`
+	ErrorMsg   string                `json:"msg"`
 }
 
 // ListCollateralTransactionResponse represents the response for GetCollateralTransactionByID
 type ListCollateralTransactionResponse struct {
-	Code       string        `json:"code"`
-	Data       []interface{} `json:"data"`
-	ErrorParam interface{}   `json:"errorParam"`
-	ErrorMsg   string        `json:"msg"`
+	Code       string                  `json:"code"`
+	Data       []CollateralTransaction `json:"data"`
+	ErrorParam interface{}             `json:"errorParam"`
+	ErrorMsg   string                  `json:"msg"`
+}
+
+// PageDataAccount represents paginated account data
+type PageDataAccount struct {
+	DataList           []Account `json:"dataList,omitempty"`
+	NextPageOffsetData *string   `json:"nextPageOffsetData,omitempty"`
+}
+
+// PageDataAccountResponse represents the response for GetAccountPage
+type PageDataAccountResponse struct {
+	Code       string           `json:"code"`
+	Data       *PageDataAccount `json:"data"`
+	ErrorParam interface{}      `json:"errorParam"`
+	ErrorMsg   string           `json:"msg"`
+}
+
+// RegisterAccount represents a newly registered account
+type RegisterAccount struct {
+	AccountID string `json:"accountId"`
+}
+
+// RegisterAccountResponse represents the response for RegisterAccount
+type RegisterAccountResponse struct {
+	Code       string           `json:"code"`
+	Data       *RegisterAccount `json:"data"`
+	ErrorParam interface{}      `json:"errorParam"`
+	ErrorMsg   string           `json:"msg"`
 }
 
 // GetAccountDeleverageLightResponse represents the response for GetAccountDeleverageLight
@@ -249,6 +593,22 @@ type UpdateLeverageSettingResponse struct {
 
 // Request parameter types
 
+// GetAccountPageParams represents the parameters for GetAccountPage
+type GetAccountPageParams struct {
+	Size       int32
+	OffsetData string
+}
+
+// RegisterAccountParams represents the parameters for RegisterAccount
+type RegisterAccountParams struct {
+	// L2Key is the stark public key of the new account
+	L2Key string
+	// L2KeyYCoordinate is the y coordinate of the stark public key
+	L2KeyYCoordinate string
+	// ClientAccountID makes the registration idempotent
+	ClientAccountID string
+}
+
 // GetPositionTransactionByIDParams represents the parameters for GetPositionTransactionByID
 type GetPositionTransactionByIDParams struct {
 	TransactionIDList []string
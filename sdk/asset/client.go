@@ -12,6 +12,7 @@ import (
 
 	"github.com/coin-quant/go-edgex/sdk/internal"
 	"github.com/coin-quant/go-edgex/sdk/metadata"
+	"github.com/coin-quant/go-edgex/sdk/policy"
 	"github.com/shopspring/decimal"
 )
 
@@ -317,6 +318,87 @@ func (c *Client) GetNormalWithdrawableAmount(ctx context.Context, params GetNorm
 	return &result, nil
 }
 
+// GetWithdrawAvailableAmount gets the amount of a coin available for normal withdrawal
+func (c *Client) GetWithdrawAvailableAmount(ctx context.Context, params GetWithdrawAvailableAmountParams) (*ResultGetWithdrawAvailableAmount, error) {
+	url := fmt.Sprintf("%s/api/v1/private/withdraw/getWithdrawAvailableAmount", c.Client.GetBaseURL())
+	queryParams := map[string]string{
+		"accountId": strconv.FormatInt(c.Client.GetAccountID(), 10),
+	}
+
+	if params.CoinId != "" {
+		queryParams["coinId"] = params.CoinId
+	}
+
+	resp, err := c.Client.HttpRequest(url, "GET", nil, queryParams)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get withdraw available amount: %w", err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read response body: %w", err)
+	}
+
+	var result ResultGetWithdrawAvailableAmount
+	if err := json.Unmarshal(body, &result); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal response: %w", err)
+	}
+
+	if result.Code != "SUCCESS" {
+		return nil, fmt.Errorf("request failed with code: %s", result.Code)
+	}
+
+	return &result, nil
+}
+
+// GetActiveWithdraw gets a page of normal withdrawal records
+func (c *Client) GetActiveWithdraw(ctx context.Context, params GetActiveWithdrawParams) (*ResultPageDataWithdraw, error) {
+	url := fmt.Sprintf("%s/api/v1/private/withdraw/getActiveWithdraw", c.Client.GetBaseURL())
+	queryParams := map[string]string{
+		"accountId": strconv.FormatInt(c.Client.GetAccountID(), 10),
+		"size":      strconv.FormatInt(int64(params.Size), 10),
+	}
+
+	if params.OffsetData != "" {
+		queryParams["offsetData"] = params.OffsetData
+	}
+	if len(params.FilterCoinIdList) > 0 {
+		queryParams["filterCoinIdList"] = internal.JoinStrings(params.FilterCoinIdList)
+	}
+	if len(params.FilterStatusList) > 0 {
+		queryParams["filterStatusList"] = internal.JoinStrings(params.FilterStatusList)
+	}
+	if params.FilterStartCreatedTime > 0 {
+		queryParams["filterStartCreatedTimeInclusive"] = strconv.FormatInt(params.FilterStartCreatedTime, 10)
+	}
+	if params.FilterEndCreatedTime > 0 {
+		queryParams["filterEndCreatedTimeExclusive"] = strconv.FormatInt(params.FilterEndCreatedTime, 10)
+	}
+
+	resp, err := c.Client.HttpRequest(url, "GET", nil, queryParams)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get active withdraw: %w", err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read response body: %w", err)
+	}
+
+	var result ResultPageDataWithdraw
+	if err := json.Unmarshal(body, &result); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal response: %w", err)
+	}
+
+	if result.Code != "SUCCESS" {
+		return nil, fmt.Errorf("request failed with code: %s", result.Code)
+	}
+
+	return &result, nil
+}
+
 func GetNonceFromClientId(clientId string) string {
 	hash := sha256.Sum256([]byte(clientId))
 	hashHex := hex.EncodeToString(hash[:])
@@ -330,18 +412,9 @@ func GetNonceFromClientId(clientId string) string {
 func (c *Client) CreateNormalWithdraw(ctx context.Context, params *CreateNormalWithdrawParams, md *metadata.MetaData) (*ResultCreateNormalWithdraw, error) {
 	url := fmt.Sprintf("%s/api/v1/private/assets/createNormalWithdraw", c.Client.GetBaseURL())
 
-	var coin *metadata.Coin
-	if md != nil && md.CoinList != nil {
-		for i := range md.CoinList {
-			if md.CoinList[i].CoinId == params.CoinId {
-				coin = &md.CoinList[i]
-				break
-			}
-		}
-	}
-
-	if coin == nil {
-		return nil, fmt.Errorf("coin not found: %s", params.CoinId)
+	coin, err := md.FindCoin(params.CoinId)
+	if err != nil {
+		return nil, err
 	}
 
 	accountID := strconv.FormatInt(c.Client.GetAccountID(), 10)
@@ -356,7 +429,24 @@ func (c *Client) CreateNormalWithdraw(ctx context.Context, params *CreateNormalW
 	if err != nil {
 		return nil, fmt.Errorf("failed to parse amount: %w", err)
 	}
-	normalizedAmount := ammount.Mul(decimal.NewFromInt(1000000)).Floor().String()
+	if err := internal.CheckStepSize(ammount, coin.StepSize); err != nil {
+		return nil, err
+	}
+	quantizedAmount, err := internal.QuantizeAmount(ammount, coin.StarkExResolution)
+	if err != nil {
+		return nil, err
+	}
+	normalizedAmount := strconv.FormatInt(quantizedAmount, 10)
+
+	if err := c.Client.CheckOutbound(ctx, policy.Request{
+		Kind:       policy.KindNormalWithdraw,
+		CoinID:     params.CoinId,
+		Amount:     ammount,
+		EthAddress: params.EthAddress,
+		ClientID:   clientRandomId,
+	}); err != nil {
+		return nil, err
+	}
 
 	// Calculate withdraw hash and sign it
 	msgHash := internal.CalcWithdrawalHash(
@@ -408,27 +498,90 @@ func (c *Client) CreateNormalWithdraw(ctx context.Context, params *CreateNormalW
 	return &result, nil
 }
 
-// CreateCrossWithdraw creates a cross-chain withdrawal order
-func (c *Client) CreateCrossWithdraw(ctx context.Context, params CreateCrossWithdrawParams) (*ResultCreateCrossWithdraw, error) {
-	url := fmt.Sprintf("%s/api/v1/private/assets/createCrossWithdraw", c.Client.GetBaseURL())
+// CreateCrossWithdraw creates a cross-chain withdrawal. It looks the destination chain up
+// in the metadata, gets the liquidity provider, fee and MPC data from the sign info,
+// signs a transfer of amount plus fee to the provider and submits it. The provider
+// pays out the amount on the destination chain.
+func (c *Client) CreateCrossWithdraw(ctx context.Context, params *CreateCrossWithdrawParams, md *metadata.MetaData) (*ResultCreateCrossWithdraw, error) {
+	coin, err := md.FindCoin(params.CoinId)
+	if err != nil {
+		return nil, err
+	}
+	chain, token, err := md.FindChainToken(params.ChainId, coin)
+	if err != nil {
+		return nil, err
+	}
+	if !chain.AllowWithdraw || !token.WithdrawEnable {
+		return nil, fmt.Errorf("withdrawal of %s disabled on chain: %s", coin.CoinName, chain.Chain)
+	}
+	amount, err := decimal.NewFromString(params.Amount)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse amount: %w", err)
+	}
+	if err := internal.CheckStepSize(amount, coin.StepSize); err != nil {
+		return nil, err
+	}
+	if err := checkWithdrawAmount(md, amount); err != nil {
+		return nil, err
+	}
+
+	signInfo, err := c.GetCrossWithdrawSignInfo(ctx, GetCrossWithdrawSignInfoParams{ChainId: params.ChainId, Amount: params.Amount})
+	if err != nil {
+		return nil, err
+	}
+	if signInfo.Data == nil {
+		return nil, fmt.Errorf("cross withdraw sign info is nil")
+	}
+	info := signInfo.Data
+	fee, err := decimal.NewFromString(internal.FirstNonEmpty(internal.StringValue(info.Fee), "0"))
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse fee: %w", err)
+	}
+	if info.CrossWithdrawMaxAmount != nil && *info.CrossWithdrawMaxAmount != "" {
+		limit, err := decimal.NewFromString(*info.CrossWithdrawMaxAmount)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse cross withdraw max amount: %w", err)
+		}
+		if amount.GreaterThan(limit) {
+			return nil, fmt.Errorf("amount %s exceeds cross withdraw max amount %s", amount, limit)
+		}
+	}
+
+	clientCrossWithdrawId := internal.FirstNonEmpty(params.ClientCrossWithdrawId, internal.GetRandomClientId())
+	if err := c.Client.CheckOutbound(ctx, policy.Request{
+		Kind:       policy.KindCrossWithdraw,
+		CoinID:     params.CoinId,
+		Amount:     amount.Add(fee),
+		EthAddress: params.EthAddress,
+		ChainID:    params.ChainId,
+		ClientID:   clientCrossWithdrawId,
+	}); err != nil {
+		return nil, err
+	}
+	lpAccountId := internal.StringValue(info.LpAccountId)
+	signed, err := c.signTransfer(coin, lpAccountId, internal.StringValue(info.CrossWithdrawL2Key), amount.Add(fee), clientCrossWithdrawId)
+	if err != nil {
+		return nil, err
+	}
 
 	body := map[string]interface{}{
 		"accountId":             strconv.FormatInt(c.Client.GetAccountID(), 10),
 		"coinId":                params.CoinId,
 		"amount":                params.Amount,
 		"ethAddress":            params.EthAddress,
-		"erc20Address":          params.Erc20Address,
-		"lpAccountId":           params.LpAccountId,
-		"clientCrossWithdrawId": params.ClientCrossWithdrawId,
-		"expireTime":            params.ExpireTime,
-		"l2Signature":           params.L2Signature,
-		"fee":                   params.Fee,
+		"erc20Address":          token.TokenAddress,
+		"lpAccountId":           lpAccountId,
+		"clientCrossWithdrawId": clientCrossWithdrawId,
+		"expireTime":            signed.expireTime,
+		"l2Signature":           signed.l2Signature,
+		"fee":                   fee.String(),
 		"chainId":               params.ChainId,
-		"mpcAddress":            params.MpcAddress,
-		"mpcSignature":          params.MpcSignature,
-		"mpcSignTime":           params.MpcSignTime,
+		"mpcAddress":            internal.StringValue(info.MpcAddress),
+		"mpcSignature":          internal.StringValue(info.MpcSignature),
+		"mpcSignTime":           internal.StringValue(info.MpcSignTime),
 	}
 
+	url := fmt.Sprintf("%s/api/v1/private/assets/createCrossWithdraw", c.Client.GetBaseURL())
 	resp, err := c.Client.HttpRequest(url, "POST", body, nil)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create cross withdraw: %w", err)
@@ -451,3 +604,123 @@ func (c *Client) CreateCrossWithdraw(ctx context.Context, params CreateCrossWith
 
 	return &result, nil
 }
+
+// CreateFastWithdraw creates a fast withdrawal. It gets the sign info of the chain,
+// signs a conditional transfer of amount plus fee to the fast withdraw liquidity
+// provider, conditioned on the L1 ERC20 payout to the ETH address, and submits it.
+func (c *Client) CreateFastWithdraw(ctx context.Context, params *CreateFastWithdrawParams, md *metadata.MetaData) (*ResultCreateFastWithdraw, error) {
+	if md == nil || md.Global == nil {
+		return nil, fmt.Errorf("metadata global is nil")
+	}
+	coin, err := md.FindCoin(params.CoinId)
+	if err != nil {
+		return nil, err
+	}
+	chainId := internal.FirstNonEmpty(params.ChainId, md.Global.StarkExChainId)
+	chain, token, err := md.FindChainToken(chainId, coin)
+	if err != nil {
+		return nil, err
+	}
+	if !chain.AllowWithdraw || !token.WithdrawEnable {
+		return nil, fmt.Errorf("withdrawal of %s disabled on chain: %s", coin.CoinName, chain.Chain)
+	}
+	amount, err := decimal.NewFromString(params.Amount)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse amount: %w", err)
+	}
+	if err := internal.CheckStepSize(amount, coin.StepSize); err != nil {
+		return nil, err
+	}
+	if err := checkWithdrawAmount(md, amount); err != nil {
+		return nil, err
+	}
+
+	signInfo, err := c.GetFastWithdrawSignInfo(ctx, GetFastWithdrawSignInfoParams{ChainId: chainId, Amount: params.Amount})
+	if err != nil {
+		return nil, err
+	}
+	if signInfo.Data == nil {
+		return nil, fmt.Errorf("fast withdraw sign info is nil")
+	}
+	info := signInfo.Data
+	fee, err := decimal.NewFromString(internal.FirstNonEmpty(internal.StringValue(info.Fee), "0"))
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse fee: %w", err)
+	}
+	maxAmount := internal.FirstNonEmpty(internal.StringValue(info.FastWithdrawMaxAmount), md.Global.FastWithdrawMaxAmount)
+	if maxAmount != "" {
+		limit, err := decimal.NewFromString(maxAmount)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse fast withdraw max amount: %w", err)
+		}
+		if amount.GreaterThan(limit) {
+			return nil, fmt.Errorf("amount %s exceeds fast withdraw max amount %s", amount, limit)
+		}
+	}
+
+	clientWithdrawId := internal.FirstNonEmpty(params.ClientWithdrawId, internal.GetRandomClientId())
+	if err := c.Client.CheckOutbound(ctx, policy.Request{
+		Kind:       policy.KindFastWithdraw,
+		CoinID:     params.CoinId,
+		Amount:     amount.Add(fee),
+		EthAddress: params.EthAddress,
+		ChainID:    chainId,
+		ClientID:   clientWithdrawId,
+	}); err != nil {
+		return nil, err
+	}
+	transfer := conditionalTransfer{
+		coin:                coin,
+		token:               token,
+		ethAddress:          params.EthAddress,
+		amount:              amount,
+		fee:                 fee,
+		lpAccountId:         internal.FirstNonEmpty(md.Global.FastWithdrawAccountId, internal.StringValue(info.LpAccountId)),
+		lpL2Key:             internal.FirstNonEmpty(md.Global.FastWithdrawAccountL2Key, internal.StringValue(info.FastWithdrawL2Key)),
+		factRegistryAddress: internal.FirstNonEmpty(md.Global.FastWithdrawRegistryAddress, internal.StringValue(info.FastWithdrawFactRegisterAddress)),
+		clientId:            clientWithdrawId,
+	}
+	signed, err := c.signConditionalTransfer(transfer)
+	if err != nil {
+		return nil, err
+	}
+
+	body := map[string]interface{}{
+		"accountId":            strconv.FormatInt(c.Client.GetAccountID(), 10),
+		"coinId":               params.CoinId,
+		"amount":               params.Amount,
+		"ethAddress":           params.EthAddress,
+		"erc20Address":         token.TokenAddress,
+		"lpAccountId":          transfer.lpAccountId,
+		"clientFastWithdrawId": clientWithdrawId,
+		"expireTime":           signed.expireTime,
+		"l2Signature":          signed.l2Signature,
+		"fee":                  fee.String(),
+		"factRegistryAddress":  transfer.factRegistryAddress,
+		"fact":                 signed.fact,
+		"chainId":              chainId,
+	}
+
+	url := fmt.Sprintf("%s/api/v1/private/assets/createFastWithdraw", c.Client.GetBaseURL())
+	resp, err := c.Client.HttpRequest(url, "POST", body, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create fast withdraw: %w", err)
+	}
+	defer resp.Body.Close()
+
+	respBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read response body: %w", err)
+	}
+
+	var result ResultCreateFastWithdraw
+	if err := json.Unmarshal(respBody, &result); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal response: %w", err)
+	}
+
+	if result.Code != "SUCCESS" {
+		return nil, fmt.Errorf("request failed with code: %s", result.Code)
+	}
+
+	return &result, nil
+}
@@ -0,0 +1,168 @@
+package asset
+
+import (
+	"fmt"
+	"math/big"
+	"strconv"
+	"time"
+
+	"github.com/coin-quant/go-edgex/sdk/internal"
+	"github.com/coin-quant/go-edgex/sdk/metadata"
+	"github.com/shopspring/decimal"
+)
+
+// l2ExpireDuration is the validity of signed withdrawals and transfers
+const l2ExpireDuration = 14 * 24 * time.Hour
+
+// conditionalTransfer is an L2 transfer to a liquidity provider that only settles once
+// the provider has paid out the matching ERC20 transfer on L1
+type conditionalTransfer struct {
+	coin                *metadata.Coin
+	token               *metadata.MultiChainToken
+	ethAddress          string
+	amount              decimal.Decimal
+	fee                 decimal.Decimal
+	lpAccountId         string
+	lpL2Key             string
+	factRegistryAddress string
+	clientId            string
+}
+
+// signedConditionalTransfer holds the signed fields of a conditional transfer
+type signedConditionalTransfer struct {
+	fact        string
+	expireTime  string
+	l2Signature string
+}
+
+// signConditionalTransfer computes the fact of the L1 payout and its condition, and
+// signs the L2 transfer of amount plus fee to the liquidity provider. The fact salt
+// is the nonce derived from the client ID.
+func (c *Client) signConditionalTransfer(t conditionalTransfer) (*signedConditionalTransfer, error) {
+	assetId, err := internal.HexToBigInteger(t.coin.StarkExAssetId)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse asset ID: %w", err)
+	}
+	lpPublicKey, err := internal.HexToBigInteger(t.lpL2Key)
+	if err != nil {
+		return nil, fmt.Errorf("invalid lp l2 key format: %s", t.lpL2Key)
+	}
+	lpPositionId, err := strconv.ParseInt(t.lpAccountId, 10, 64)
+	if err != nil {
+		return nil, fmt.Errorf("invalid lp account ID: %w", err)
+	}
+	decimals, err := strconv.ParseInt(t.token.Decimals, 10, 32)
+	if err != nil {
+		return nil, fmt.Errorf("invalid token decimals: %w", err)
+	}
+
+	nonce := internal.CalcNonce(t.clientId)
+	tokenAmount := t.amount.Shift(int32(decimals))
+	if !tokenAmount.IsInteger() {
+		return nil, fmt.Errorf("amount %s has more decimals than token %s: %d", t.amount, t.token.Token, decimals)
+	}
+	fact, err := internal.CalcErc20TransferFact(t.ethAddress, tokenAmount.BigInt(), t.token.TokenAddress, big.NewInt(nonce))
+	if err != nil {
+		return nil, err
+	}
+	condition, err := internal.FactToCondition(t.factRegistryAddress, fact)
+	if err != nil {
+		return nil, err
+	}
+
+	l2ExpireTime := time.Now().Add(l2ExpireDuration).UnixMilli()
+	l2ExpireHour := l2ExpireTime / (60 * 60 * 1000)
+	amount, err := internal.QuantizeAmount(t.amount.Add(t.fee), t.coin.StarkExResolution)
+	if err != nil {
+		return nil, err
+	}
+
+	msgHash := internal.CalcConditionalTransferHash(
+		assetId,
+		big.NewInt(0),
+		lpPublicKey,
+		condition,
+		c.Client.GetAccountID(),
+		lpPositionId,
+		c.Client.GetAccountID(),
+		nonce,
+		amount,
+		0,
+		l2ExpireHour,
+	)
+	signature, err := c.Client.Sign(msgHash)
+	if err != nil {
+		return nil, fmt.Errorf("failed to sign conditional transfer hash: %w", err)
+	}
+
+	return &signedConditionalTransfer{
+		fact:        fmt.Sprintf("0x%x", fact),
+		expireTime:  strconv.FormatInt(l2ExpireTime, 10),
+		l2Signature: fmt.Sprintf("%s%s", signature.R, signature.S),
+	}, nil
+}
+
+// signedTransfer holds the signed fields of a transfer
+type signedTransfer struct {
+	expireTime  string
+	l2Signature string
+}
+
+// signTransfer signs an L2 transfer of amount to a liquidity provider
+func (c *Client) signTransfer(coin *metadata.Coin, lpAccountId, lpL2Key string, amount decimal.Decimal, clientId string) (*signedTransfer, error) {
+	assetId, err := internal.HexToBigInteger(coin.StarkExAssetId)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse asset ID: %w", err)
+	}
+	lpPublicKey, err := internal.HexToBigInteger(lpL2Key)
+	if err != nil {
+		return nil, fmt.Errorf("invalid lp l2 key format: %s", lpL2Key)
+	}
+	lpPositionId, err := strconv.ParseInt(lpAccountId, 10, 64)
+	if err != nil {
+		return nil, fmt.Errorf("invalid lp account ID: %w", err)
+	}
+
+	quantizedAmount, err := internal.QuantizeAmount(amount, coin.StarkExResolution)
+	if err != nil {
+		return nil, err
+	}
+	l2ExpireTime := time.Now().Add(l2ExpireDuration).UnixMilli()
+	l2ExpireHour := l2ExpireTime / (60 * 60 * 1000)
+
+	msgHash := internal.CalcTransferHash(
+		assetId,
+		big.NewInt(0),
+		lpPublicKey,
+		c.Client.GetAccountID(),
+		lpPositionId,
+		c.Client.GetAccountID(),
+		internal.CalcNonce(clientId),
+		quantizedAmount,
+		0,
+		l2ExpireHour,
+	)
+	signature, err := c.Client.Sign(msgHash)
+	if err != nil {
+		return nil, fmt.Errorf("failed to sign transfer hash: %w", err)
+	}
+
+	return &signedTransfer{
+		expireTime:  strconv.FormatInt(l2ExpireTime, 10),
+		l2Signature: fmt.Sprintf("%s%s", signature.R, signature.S),
+	}, nil
+}
+
+// checkWithdrawAmount checks an amount against the multi chain withdrawal bounds
+func checkWithdrawAmount(md *metadata.MetaData, amount decimal.Decimal) error {
+	if md.MultiChain == nil {
+		return nil
+	}
+	if lower, err := decimal.NewFromString(md.MultiChain.MinWithdraw); err == nil && amount.LessThan(lower) {
+		return fmt.Errorf("amount %s below min withdraw %s", amount, lower)
+	}
+	if upper, err := decimal.NewFromString(md.MultiChain.MaxWithdraw); err == nil && upper.IsPositive() && amount.GreaterThan(upper) {
+		return fmt.Errorf("amount %s above max withdraw %s", amount, upper)
+	}
+	return nil
+}
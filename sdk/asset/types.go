@@ -1,11 +1,41 @@
 package asset
 
+import (
+	"encoding/json"
+
+	"github.com/coin-quant/go-edgex/openapi"
+)
+
+// Asset order types of GetAllOrdersPageParams.TypeList
+const (
+	OrderTypeNormalDeposit  = "ORDER_TYPE_NORMAL_DEPOSIT"
+	OrderTypeCrossDeposit   = "ORDER_TYPE_CROSS_DEPOSIT"
+	OrderTypeNormalWithdraw = "ORDER_TYPE_NORMAL_WITHDRAW"
+	OrderTypeCrossWithdraw  = "ORDER_TYPE_CROSS_WITHDRAW"
+	OrderTypeFastWithdraw   = "ORDER_TYPE_FAST_WITHDRAW"
+	OrderTypeTransferIn     = "ORDER_TYPE_TRANSFER_IN"
+	OrderTypeTransferOut    = "ORDER_TYPE_TRANSFER_OUT"
+)
+
 // PageDataAssetOrder represents paginated asset order data
 type PageDataAssetOrder struct {
 	DataList           []interface{} `json:"dataList,omitempty"`
 	NextPageOffsetData *string       `json:"nextPageOffsetData,omitempty"`
 }
 
+// Orders decodes DataList into asset orders
+func (p PageDataAssetOrder) Orders() ([]openapi.AssetOrder, error) {
+	data, err := json.Marshal(p.DataList)
+	if err != nil {
+		return nil, err
+	}
+	var orders []openapi.AssetOrder
+	if err := json.Unmarshal(data, &orders); err != nil {
+		return nil, err
+	}
+	return orders, nil
+}
+
 // GetCoinRate represents coin rate information
 type GetCoinRate struct {
 	Rate *string `json:"rate,omitempty"`
@@ -13,17 +43,33 @@ type GetCoinRate struct {
 
 // CreateCrossWithdraw represents cross withdrawal information
 type CreateCrossWithdraw struct {
-	Id               *string `json:"id,omitempty"`
-	UserId           *string `json:"userId,omitempty"`
-	AccountId        *string `json:"accountId,omitempty"`
-	CoinId           *string `json:"coinId,omitempty"`
-	Amount           *string `json:"amount,omitempty"`
-	ReceiverAddress  *string `json:"receiverAddress,omitempty"`
-	ReceiverChainId  *string `json:"receiverChainId,omitempty"`
-	ClientWithdrawId *string `json:"clientWithdrawId,omitempty"`
-	Status           *string `json:"status,omitempty"`
-	CreatedTime      *string `json:"createdTime,omitempty"`
-	UpdatedTime      *string `json:"updatedTime,omitempty"`
+	CrossWithdrawId       *string `json:"crossWithdrawId,omitempty"`
+	Id                    *string `json:"id,omitempty"`
+	UserId                *string `json:"userId,omitempty"`
+	AccountId             *string `json:"accountId,omitempty"`
+	CoinId                *string `json:"coinId,omitempty"`
+	Amount                *string `json:"amount,omitempty"`
+	ReceiverAddress       *string `json:"receiverAddress,omitempty"`
+	ReceiverChainId       *string `json:"receiverChainId,omitempty"`
+	ClientWithdrawId      *string `json:"clientWithdrawId,omitempty"`
+	Status                *string `json:"status,omitempty"`
+	EthAddress            *string `json:"ethAddress,omitempty"`
+	Erc20Address          *string `json:"erc20Address,omitempty"`
+	LpAccountId           *string `json:"lpAccountId,omitempty"`
+	ClientCrossWithdrawId *string `json:"clientCrossWithdrawId,omitempty"`
+	Fee                   *string `json:"fee,omitempty"`
+	ChainId               *string `json:"chainId,omitempty"`
+	CensorFailCode        *string `json:"censorFailCode,omitempty"`
+	CensorFailReason      *string `json:"censorFailReason,omitempty"`
+	L2RejectCode          *string `json:"l2RejectCode,omitempty"`
+	L2RejectReason        *string `json:"l2RejectReason,omitempty"`
+	L1ConfirmedTime       *string `json:"l1ConfirmedTime,omitempty"`
+	L1CompletedTime       *string `json:"l1CompletedTime,omitempty"`
+	L1RejectedReasonCode  *string `json:"l1RejectedReasonCode,omitempty"`
+	L1RejectedReasonMsg   *string `json:"l1RejectedReasonMsg,omitempty"`
+	TransferOutId         *string `json:"transferOutId,omitempty"`
+	CreatedTime           *string `json:"createdTime,omitempty"`
+	UpdatedTime           *string `json:"updatedTime,omitempty"`
 }
 
 // GetCrossWithdrawSignInfo represents cross withdraw sign info
@@ -32,20 +78,39 @@ type GetCrossWithdrawSignInfo struct {
 	CrossWithdrawL2Key     *string `json:"crossWithdrawL2Key,omitempty"`
 	CrossWithdrawMaxAmount *string `json:"crossWithdrawMaxAmount,omitempty"`
 	Fee                    *string `json:"fee,omitempty"`
+	MpcAddress             *string `json:"mpcAddress,omitempty"`
+	MpcSignature           *string `json:"mpcSignature,omitempty"`
+	MpcSignTime            *string `json:"mpcSignTime,omitempty"`
 }
 
 // CreateFastWithdraw represents fast withdrawal information
 type CreateFastWithdraw struct {
-	Id               *string `json:"id,omitempty"`
-	UserId           *string `json:"userId,omitempty"`
-	AccountId        *string `json:"accountId,omitempty"`
-	CoinId           *string `json:"coinId,omitempty"`
-	Amount           *string `json:"amount,omitempty"`
-	ReceiverAddress  *string `json:"receiverAddress,omitempty"`
-	ClientWithdrawId *string `json:"clientWithdrawId,omitempty"`
-	Status           *string `json:"status,omitempty"`
-	CreatedTime      *string `json:"createdTime,omitempty"`
-	UpdatedTime      *string `json:"updatedTime,omitempty"`
+	FastWithdrawId       *string `json:"fastWithdrawId,omitempty"`
+	Id                   *string `json:"id,omitempty"`
+	UserId               *string `json:"userId,omitempty"`
+	AccountId            *string `json:"accountId,omitempty"`
+	CoinId               *string `json:"coinId,omitempty"`
+	Amount               *string `json:"amount,omitempty"`
+	ReceiverAddress      *string `json:"receiverAddress,omitempty"`
+	ClientWithdrawId     *string `json:"clientWithdrawId,omitempty"`
+	Status               *string `json:"status,omitempty"`
+	EthAddress           *string `json:"ethAddress,omitempty"`
+	Erc20Address         *string `json:"erc20Address,omitempty"`
+	LpAccountId          *string `json:"lpAccountId,omitempty"`
+	ClientFastWithdrawId *string `json:"clientFastWithdrawId,omitempty"`
+	Fee                  *string `json:"fee,omitempty"`
+	ChainId              *string `json:"chainId,omitempty"`
+	CensorFailCode       *string `json:"censorFailCode,omitempty"`
+	CensorFailReason     *string `json:"censorFailReason,omitempty"`
+	L2RejectCode         *string `json:"l2RejectCode,omitempty"`
+	L2RejectReason       *string `json:"l2RejectReason,omitempty"`
+	L1ConfirmedTime      *string `json:"l1ConfirmedTime,omitempty"`
+	L1CompletedTime      *string `json:"l1CompletedTime,omitempty"`
+	L1RejectedReasonCode *string `json:"l1RejectedReasonCode,omitempty"`
+	L1RejectedReasonMsg  *string `json:"l1RejectedReasonMsg,omitempty"`
+	TransferOutId        *string `json:"transferOutId,omitempty"`
+	CreatedTime          *string `json:"createdTime,omitempty"`
+	UpdatedTime          *string `json:"updatedTime,omitempty"`
 }
 
 // GetFastWithdrawSignInfo represents fast withdraw sign info
@@ -60,6 +125,7 @@ type GetFastWithdrawSignInfo struct {
 // CreateNormalWithdraw represents normal withdrawal information
 type CreateNormalWithdraw struct {
 	Id               *string `json:"id,omitempty"`
+	WithdrawId       *string `json:"withdrawId,omitempty"`
 	UserId           *string `json:"userId,omitempty"`
 	AccountId        *string `json:"accountId,omitempty"`
 	CoinId           *string `json:"coinId,omitempty"`
@@ -67,6 +133,12 @@ type CreateNormalWithdraw struct {
 	ReceiverAddress  *string `json:"receiverAddress,omitempty"`
 	ClientWithdrawId *string `json:"clientWithdrawId,omitempty"`
 	Status           *string `json:"status,omitempty"`
+	EthAddress       *string `json:"ethAddress,omitempty"`
+	CensorFailCode   *string `json:"censorFailCode,omitempty"`
+	CensorFailReason *string `json:"censorFailReason,omitempty"`
+	L2RejectCode     *string `json:"l2RejectCode,omitempty"`
+	L2RejectReason   *string `json:"l2RejectReason,omitempty"`
+	L2ApprovedTime   *string `json:"l2ApprovedTime,omitempty"`
 	CreatedTime      *string `json:"createdTime,omitempty"`
 	UpdatedTime      *string `json:"updatedTime,omitempty"`
 }
@@ -76,6 +148,62 @@ type GetNormalWithdrawableAmount struct {
 	Amount *string `json:"amount,omitempty"`
 }
 
+// GetWithdrawAvailableAmount represents the amount available for normal withdrawal
+type GetWithdrawAvailableAmount struct {
+	AvailableAmount *string `json:"availableAmount,omitempty"`
+}
+
+// Withdraw represents a withdrawal record
+type Withdraw struct {
+	Id                      *string `json:"id,omitempty"`
+	UserId                  *string `json:"userId,omitempty"`
+	AccountId               *string `json:"accountId,omitempty"`
+	CoinId                  *string `json:"coinId,omitempty"`
+	Amount                  *string `json:"amount,omitempty"`
+	EthAddress              *string `json:"ethAddress,omitempty"`
+	Erc20Address            *string `json:"erc20Address,omitempty"`
+	ClientWithdrawId        *string `json:"clientWithdrawId,omitempty"`
+	L2Nonce                 *string `json:"l2Nonce,omitempty"`
+	L2ExpireTime            *string `json:"l2ExpireTime,omitempty"`
+	ExtraType               *string `json:"extraType,omitempty"`
+	ExtraDataJson           *string `json:"extraDataJson,omitempty"`
+	Status                  *string `json:"status,omitempty"`
+	CollateralTransactionId *string `json:"collateralTransactionId,omitempty"`
+	CensorTxId              *string `json:"censorTxId,omitempty"`
+	CensorTime              *string `json:"censorTime,omitempty"`
+	CensorFailCode          *string `json:"censorFailCode,omitempty"`
+	CensorFailReason        *string `json:"censorFailReason,omitempty"`
+	L2TxId                  *string `json:"l2TxId,omitempty"`
+	L2RejectTime            *string `json:"l2RejectTime,omitempty"`
+	L2RejectCode            *string `json:"l2RejectCode,omitempty"`
+	L2RejectReason          *string `json:"l2RejectReason,omitempty"`
+	L2ApprovedTime          *string `json:"l2ApprovedTime,omitempty"`
+	CreatedTime             *string `json:"createdTime,omitempty"`
+	UpdatedTime             *string `json:"updatedTime,omitempty"`
+}
+
+// PageDataWithdraw represents paginated withdrawal data
+type PageDataWithdraw struct {
+	DataList           []Withdraw `json:"dataList"`
+	NextPageOffsetData *string    `json:"nextPageOffsetData,omitempty"`
+}
+
+// ResultPageDataWithdraw represents paginated withdrawals
+type ResultPageDataWithdraw struct {
+	Code       string            `json:"code"`
+	Data       *PageDataWithdraw `json:"data"`
+	ErrorParam interface{}       `json:"errorParam"`
+	ErrorMsg   string            `json:"msg"`
+}
+
+// ResultListWithdraw represents list of withdrawals
+type ResultListWithdraw struct {
+	Code       string      `json:"code"`
+	Data       []Withdraw  `json:"data"`
+	ErrorParam interface{} `json:"errorParam"`
+	ErrorMsg   string      `json:"msg"`
+}
+
 // ResultPageDataAssetOrder represents paginated asset orders
 type ResultPageDataAssetOrder struct {
 	Code       string              `json:"code"`
@@ -140,6 +268,14 @@ type ResultGetNormalWithdrawableAmount struct {
 	ErrorMsg   string                       `json:"msg"`
 }
 
+// ResultGetWithdrawAvailableAmount represents the amount available for normal withdrawal
+type ResultGetWithdrawAvailableAmount struct {
+	Code       string                      `json:"code"`
+	Data       *GetWithdrawAvailableAmount `json:"data"`
+	ErrorParam interface{}                 `json:"errorParam"`
+	ErrorMsg   string                      `json:"msg"`
+}
+
 // ResultCreateNormalWithdraw represents result of creating normal withdrawal
 type ResultCreateNormalWithdraw struct {
 	Code       string                `json:"code"`
@@ -204,6 +340,16 @@ type GetFastWithdrawSignInfoParams struct {
 	Amount  string
 }
 
+// GetActiveWithdrawParams represents parameters for GetActiveWithdraw
+type GetActiveWithdrawParams struct {
+	Size                   int32
+	OffsetData             string
+	FilterCoinIdList       []string
+	FilterStatusList       []string
+	FilterStartCreatedTime int64
+	FilterEndCreatedTime   int64
+}
+
 // GetNormalWithdrawByIdParams represents parameters for GetNormalWithdrawById
 type GetNormalWithdrawByIdParams struct {
 	NormalWithdrawIdList string
@@ -220,6 +366,11 @@ type GetNormalWithdrawableAmountParams struct {
 	Address string
 }
 
+// GetWithdrawAvailableAmountParams represents parameters for GetWithdrawAvailableAmount
+type GetWithdrawAvailableAmountParams struct {
+	CoinId string
+}
+
 // CreateNormalWithdrawParams represents parameters for CreateNormalWithdraw
 type CreateNormalWithdrawParams struct {
 	CoinId     string
@@ -229,27 +380,22 @@ type CreateNormalWithdrawParams struct {
 
 // CreateCrossWithdrawParams represents parameters for CreateCrossWithdraw
 type CreateCrossWithdrawParams struct {
-	CoinId                string
-	Amount                string
-	EthAddress            string
-	Erc20Address          string
-	LpAccountId           string
+	CoinId     string
+	Amount     string
+	EthAddress string
+	// ChainId is the destination chain, as listed in the metadata multi chain list
+	ChainId string
+	// ClientCrossWithdrawId makes the withdrawal idempotent, a random ID is used if empty
 	ClientCrossWithdrawId string
-	ExpireTime            string
-	L2Signature           string
-	Fee                   string
-	ChainId               string
-	MpcAddress            string
-	MpcSignature          string
-	MpcSignTime           string
 }
 
 // CreateFastWithdrawParams represents parameters for CreateFastWithdraw
 type CreateFastWithdrawParams struct {
-	CoinId           string
-	Amount           string
-	EthAddress       string
+	CoinId     string
+	Amount     string
+	EthAddress string
+	// ChainId defaults to the StarkEx chain
+	ChainId string
+	// ClientWithdrawId makes the withdrawal idempotent, a random ID is used if empty
 	ClientWithdrawId string
-	ExpireTime       string
-	L2Signature      string
 }
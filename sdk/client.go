@@ -9,16 +9,23 @@ import (
 	"net/http"
 	"sort"
 	"strings"
+	"sync"
 	"time"
 
+	"github.com/coin-quant/go-edgex/keystore"
 	"github.com/coin-quant/go-edgex/sdk/account"
 	"github.com/coin-quant/go-edgex/sdk/asset"
+	"github.com/coin-quant/go-edgex/sdk/deposit"
 	"github.com/coin-quant/go-edgex/sdk/funding"
 	"github.com/coin-quant/go-edgex/sdk/internal"
 	"github.com/coin-quant/go-edgex/sdk/metadata"
 	"github.com/coin-quant/go-edgex/sdk/order"
 	"github.com/coin-quant/go-edgex/sdk/quote"
+	"github.com/coin-quant/go-edgex/sdk/reports"
+	"github.com/coin-quant/go-edgex/sdk/risk"
 	"github.com/coin-quant/go-edgex/sdk/transfer"
+	"github.com/coin-quant/go-edgex/sdk/withdrawal"
+	"github.com/coin-quant/go-edgex/starkkey"
 	"github.com/shopspring/decimal"
 	"golang.org/x/crypto/sha3"
 )
@@ -26,16 +33,17 @@ import (
 // Client represents an EdgeX SDK client
 type Client struct {
 	*internal.Client
-	metadataCache     *metadata.ResultMetaData
-	metadataCacheTime time.Time
-	metadataCacheTTL  *time.Duration
-	Order             *order.Client
-	Metadata          *metadata.Client
-	Account           *account.Client
-	Quote             *quote.Client
-	Funding           *funding.Client
-	Transfer          *transfer.Client
-	Asset             *asset.Client
+	metadataCache *metadataCache
+	Order         *order.Client
+	Metadata      *metadata.Client
+	Account       *account.Client
+	Quote         *quote.Client
+	Funding       *funding.Client
+	Transfer      *transfer.Client
+	Asset         *asset.Client
+	Deposit       *deposit.Client
+	// Withdrawals tracks the withdrawals created through this client
+	Withdrawals *withdrawal.Tracker
 }
 
 // ClientConfig holds the configuration for creating a new Client
@@ -44,29 +52,69 @@ type ClientConfig struct {
 	AccountID        int64
 	StarkPriKey      string
 	MetaDataCacheTTL *time.Duration
+	// HTTPClient is used for all requests when set, so that clients can share a connection pool
+	HTTPClient *http.Client
+}
+
+// metadataCache caches exchange metadata. It may be shared by several clients.
+type metadataCache struct {
+	mu   sync.Mutex
+	data *metadata.ResultMetaData
+	time time.Time
+	ttl  *time.Duration
 }
 
 // NewClient creates a new EdgeX SDK client
 func NewClient(cfg *ClientConfig) (*Client, error) {
+	return newClient(cfg, &metadataCache{ttl: cfg.MetaDataCacheTTL})
+}
+
+// NewClientFromKeystore creates a new client signing with the Stark key of an
+// encrypted keystore. cfg.StarkPriKey must be empty; cfg.AccountID defaults to the
+// account ID of the keystore.
+func NewClientFromKeystore(cfg *ClientConfig, path, passphrase string) (*Client, error) {
+	if cfg.StarkPriKey != "" {
+		return nil, fmt.Errorf("stark private key and keystore are mutually exclusive")
+	}
+	key, err := keystore.Load(path, passphrase)
+	if err != nil {
+		return nil, err
+	}
+	keyCfg := *cfg
+	keyCfg.StarkPriKey = key.KeyPair.PrivateKeyHex()
+	if keyCfg.AccountID == 0 {
+		keyCfg.AccountID = key.AccountID
+	} else if key.AccountID != 0 && key.AccountID != keyCfg.AccountID {
+		return nil, fmt.Errorf("keystore is for account %d, not %d", key.AccountID, keyCfg.AccountID)
+	}
+	return NewClient(&keyCfg)
+}
+
+// newClient creates a new client using the given metadata cache
+func newClient(cfg *ClientConfig, cache *metadataCache) (*Client, error) {
 	internalClient, err := internal.NewClient(&internal.ClientConfig{
 		BaseURL:     cfg.BaseURL,
 		AccountID:   cfg.AccountID,
 		StarkPriKey: cfg.StarkPriKey,
+		HTTPClient:  cfg.HTTPClient,
 	})
 	if err != nil {
 		return nil, err
 	}
 
+	assetClient := asset.NewClient(internalClient)
 	return &Client{
-		Client:           internalClient,
-		metadataCacheTTL: cfg.MetaDataCacheTTL,
-		Order:            order.NewClient(internalClient),
-		Metadata:         metadata.NewClient(internalClient),
-		Account:          account.NewClient(internalClient),
-		Quote:            quote.NewClient(internalClient),
-		Funding:          funding.NewClient(internalClient),
-		Transfer:         transfer.NewClient(internalClient),
-		Asset:            asset.NewClient(internalClient),
+		Client:        internalClient,
+		metadataCache: cache,
+		Order:         order.NewClient(internalClient),
+		Metadata:      metadata.NewClient(internalClient),
+		Account:       account.NewClient(internalClient),
+		Quote:         quote.NewClient(internalClient),
+		Funding:       funding.NewClient(internalClient),
+		Transfer:      transfer.NewClient(internalClient),
+		Asset:         assetClient,
+		Deposit:       deposit.NewClient(internalClient),
+		Withdrawals:   withdrawal.New(assetClient),
 	}, nil
 }
 
@@ -134,19 +182,23 @@ func (i *requestInterceptor) RoundTrip(req *http.Request) (*http.Response, error
 
 // GetMetaData gets the exchange metadata
 func (c *Client) GetMetaData(ctx context.Context) (*metadata.ResultMetaData, error) {
-	if c.metadataCacheTTL != nil {
+	cache := c.metadataCache
+	cache.mu.Lock()
+	defer cache.mu.Unlock()
+
+	if cache.ttl != nil {
 		// Check if metadata is cached and not expired
-		if c.metadataCache != nil && time.Since(c.metadataCacheTime) < *c.metadataCacheTTL {
-			return c.metadataCache, nil
+		if cache.data != nil && time.Since(cache.time) < *cache.ttl {
+			return cache.data, nil
 		}
-		c.metadataCacheTime = time.Now()
+		cache.time = time.Now()
 	}
-	metadataCache, err := c.Metadata.GetMetaData(ctx)
+	data, err := c.Metadata.GetMetaData(ctx)
 	if err != nil {
 		return nil, err
 	}
-	c.metadataCache = metadataCache
-	return c.metadataCache, nil
+	cache.data = data
+	return cache.data, nil
 }
 
 // GetServerTime gets the current server time
@@ -186,12 +238,55 @@ func (c *Client) CreateNormalWithdraw(ctx context.Context, params *asset.CreateN
 		return nil, fmt.Errorf("failed to get metadata: %w", err)
 	}
 
-	return c.Asset.CreateNormalWithdraw(ctx, params, metadataResp.Data)
+	resp, err := c.Asset.CreateNormalWithdraw(ctx, params, metadataResp.Data)
+	if err == nil && resp.Data != nil && resp.Data.WithdrawId != nil {
+		c.Withdrawals.Track(withdrawal.KindNormal, *resp.Data.WithdrawId)
+	}
+	return resp, err
+}
+
+// CreateFastWithdraw creates a fast withdrawal paid out on L1 by a liquidity provider
+func (c *Client) CreateFastWithdraw(ctx context.Context, params *asset.CreateFastWithdrawParams) (*asset.ResultCreateFastWithdraw, error) {
+	metadataResp, err := c.GetMetaData(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get metadata: %w", err)
+	}
+
+	resp, err := c.Asset.CreateFastWithdraw(ctx, params, metadataResp.Data)
+	if err == nil && resp.Data != nil && resp.Data.FastWithdrawId != nil {
+		c.Withdrawals.Track(withdrawal.KindFast, *resp.Data.FastWithdrawId)
+	}
+	return resp, err
+}
+
+// PlanWithdrawal compares the normal, fast and cross routes of a withdrawal
+func (c *Client) PlanWithdrawal(ctx context.Context, params withdrawal.PlanParams) (*withdrawal.Plan, error) {
+	metadataResp, err := c.GetMetaData(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get metadata: %w", err)
+	}
+
+	return withdrawal.NewPlanner(c.Asset, c.Transfer).Plan(ctx, params, metadataResp.Data)
 }
 
-// func (c *Client) GetCrossWithdrawSignInfo(ctx context.Context, params *asset.CreateCrossWithdrawParams) (*asset.ResultCreateCrossWithdraw, error) {
-// 	// Get metadata first
-// }
+// GetCrossWithdrawSignInfo gets the liquidity provider, fee and MPC data of a cross-chain withdrawal
+func (c *Client) GetCrossWithdrawSignInfo(ctx context.Context, params asset.GetCrossWithdrawSignInfoParams) (*asset.ResultGetCrossWithdrawSignInfo, error) {
+	return c.Asset.GetCrossWithdrawSignInfo(ctx, params)
+}
+
+// CreateCrossWithdraw creates a withdrawal paid out on another chain by a liquidity provider
+func (c *Client) CreateCrossWithdraw(ctx context.Context, params *asset.CreateCrossWithdrawParams) (*asset.ResultCreateCrossWithdraw, error) {
+	metadataResp, err := c.GetMetaData(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get metadata: %w", err)
+	}
+
+	resp, err := c.Asset.CreateCrossWithdraw(ctx, params, metadataResp.Data)
+	if err == nil && resp.Data != nil && resp.Data.CrossWithdrawId != nil {
+		c.Withdrawals.Track(withdrawal.KindCross, *resp.Data.CrossWithdrawId)
+	}
+	return resp, err
+}
 
 // GetMaxOrderSize gets the maximum order size for a given contract and price
 func (c *Client) GetMaxOrderSize(ctx context.Context, contractID string, price decimal.Decimal) (*order.ResultGetMaxCreateOrderSize, error) {
@@ -203,6 +298,11 @@ func (c *Client) CancelOrder(ctx context.Context, params *order.CancelOrderParam
 	return c.Order.CancelOrder(ctx, params)
 }
 
+// CancelAllOrders cancels all active orders, or only those of contractIDs when given
+func (c *Client) CancelAllOrders(ctx context.Context, contractIDs []string) (interface{}, error) {
+	return c.Order.CancelAllOrders(ctx, contractIDs)
+}
+
 // GetActiveOrders gets active orders with pagination and filters
 func (c *Client) GetActiveOrders(ctx context.Context, params *order.GetActiveOrderParams) (*order.ResultPageDataOrder, error) {
 	return c.Order.GetActiveOrders(ctx, params)
@@ -228,6 +328,11 @@ func (c *Client) GetAccountAsset(ctx context.Context) (*account.GetAccountAssetR
 	return c.Account.GetAccountAsset(ctx)
 }
 
+// GetAccountAssetData gets the account asset information as full account, position and collateral models
+func (c *Client) GetAccountAssetData(ctx context.Context) (*account.GetAccountAssetDataResponse, error) {
+	return c.Account.GetAccountAssetData(ctx)
+}
+
 // GetAccountPositions gets the account positions
 func (c *Client) GetAccountPositions(ctx context.Context) (*account.ListPositionResponse, error) {
 	return c.Account.GetAccountPositions(ctx)
@@ -253,6 +358,22 @@ func (c *Client) GetAccountByID(ctx context.Context) (*account.AccountResponse,
 	return c.Account.GetAccountByID(ctx)
 }
 
+// ValidateStarkKey checks that the configured Stark private key is the l2Key of the account
+func (c *Client) ValidateStarkKey(ctx context.Context) error {
+	keyPair, err := starkkey.FromPrivateKey(c.GetStarkPriKey())
+	if err != nil {
+		return err
+	}
+	resp, err := c.GetAccountByID(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to get account: %w", err)
+	}
+	if resp.Data == nil {
+		return fmt.Errorf("account %d not found", c.GetAccountID())
+	}
+	return keyPair.Validate(resp.Data.L2Key, resp.Data.L2KeyYCoordinate)
+}
+
 // GetAccountDeleverageLight gets account deleverage light information
 func (c *Client) GetAccountDeleverageLight(ctx context.Context) (*account.GetAccountDeleverageLightResponse, error) {
 	return c.Account.GetAccountDeleverageLight(ctx)
@@ -263,6 +384,21 @@ func (c *Client) GetAccountAssetSnapshotPage(ctx context.Context, params account
 	return c.Account.GetAccountAssetSnapshotPage(ctx, params)
 }
 
+// GetAccountPage gets the accounts of the user with pagination
+func (c *Client) GetAccountPage(ctx context.Context, params account.GetAccountPageParams) (*account.PageDataAccountResponse, error) {
+	return c.Account.GetAccountPage(ctx, params)
+}
+
+// RegisterAccount registers a new account for a stark key under the current user
+func (c *Client) RegisterAccount(ctx context.Context, params account.RegisterAccountParams) (*account.RegisterAccountResponse, error) {
+	return c.Account.RegisterAccount(ctx, params)
+}
+
+// GetEquityCurve builds an equity curve with drawdown, return and volatility stats from asset snapshots
+func (c *Client) GetEquityCurve(ctx context.Context, params account.GetEquityCurveParams) (*account.EquityCurve, error) {
+	return c.Account.GetEquityCurve(ctx, params)
+}
+
 // GetPositionTransactionByID gets position transactions by IDs
 func (c *Client) GetPositionTransactionByID(ctx context.Context, transactionIDs []string) (*account.ListPositionTransactionResponse, error) {
 	return c.Account.GetPositionTransactionByID(ctx, transactionIDs)
@@ -324,11 +460,109 @@ func (c *Client) CreateTransferOut(ctx context.Context, params *transfer.CreateT
 	return c.Transfer.CreateTransferOut(ctx, params, metadataResp.Data)
 }
 
+// GetDepositById gets deposit records by ID
+func (c *Client) GetDepositById(ctx context.Context, depositIds []string) (*deposit.ResultListDeposit, error) {
+	return c.Deposit.GetDepositById(ctx, depositIds)
+}
+
+// WaitForDeposit polls a deposit until it reaches a terminal status
+func (c *Client) WaitForDeposit(ctx context.Context, depositId string, params deposit.WaitParams) (*deposit.Deposit, error) {
+	return c.Deposit.WaitForDeposit(ctx, depositId, params)
+}
+
+// BuildDepositCalldata builds the L1 calls depositing amount of a coin into this account
+func (c *Client) BuildDepositCalldata(ctx context.Context, coinID string, amount decimal.Decimal) (*deposit.DepositCalldata, error) {
+	metadataResp, err := c.GetMetaData(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get metadata: %w", err)
+	}
+	accountResp, err := c.GetAccountByID(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get account: %w", err)
+	}
+	if accountResp.Data == nil {
+		return nil, fmt.Errorf("account data is nil")
+	}
+
+	return deposit.BuildDepositCalldata(metadataResp.Data, coinID, accountResp.Data.L2Key, c.GetAccountID(), amount)
+}
+
 // UpdateLeverageSetting updates the account leverage settings
 func (c *Client) UpdateLeverageSetting(ctx context.Context, contractID string, leverage string) error {
 	return c.Account.UpdateLeverageSetting(ctx, contractID, leverage)
 }
 
+// GetFundingHistory gets and summarizes the funding payments of a coin created in [from, to)
+func (c *Client) GetFundingHistory(ctx context.Context, coinID string, from, to time.Time) (*funding.Summary, error) {
+	return funding.History(ctx, c.Account, coinID, from, to)
+}
+
+// EstimateNextFundingPayments projects the next funding payment of every open position
+func (c *Client) EstimateNextFundingPayments(ctx context.Context) ([]funding.Estimate, error) {
+	assetResp, err := c.GetAccountAssetData(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get account asset: %w", err)
+	}
+	return c.Funding.EstimateNextPayments(ctx, assetResp.Data.PositionList)
+}
+
+// GetReport fetches the account history of a period and builds its trade, position
+// term and cash ledgers
+func (c *Client) GetReport(ctx context.Context, params reports.Params) (*reports.Report, error) {
+	return reports.Fetch(ctx, c.Order, c.Account, params)
+}
+
+// GetLeverageSettings gets the leverage settings of the account
+func (c *Client) GetLeverageSettings(ctx context.Context) (*account.LeverageSettings, error) {
+	return c.Account.GetLeverageSettings(ctx)
+}
+
+// SetLeverage validates a leverage setting against the contract's side limits and the
+// risk tier of the current position, then applies it. It returns the resulting change
+// in margin requirement, and refuses settings that leave a negative available balance.
+func (c *Client) SetLeverage(ctx context.Context, contractID string, leverage decimal.Decimal) (*risk.LeverageChange, error) {
+	metadataResp, err := c.GetMetaData(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get metadata: %w", err)
+	}
+	var coinID string
+	for _, contract := range metadataResp.Data.ContractList {
+		if contract.ContractId == contractID {
+			coinID = contract.QuoteCoinId
+			break
+		}
+	}
+	if coinID == "" {
+		return nil, fmt.Errorf("contract not found: %s", contractID)
+	}
+
+	calc, err := risk.NewCalculator(metadataResp.Data)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create risk calculator: %w", err)
+	}
+	assetResp, err := c.GetAccountAssetData(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get account asset: %w", err)
+	}
+
+	acc, err := risk.AccountFromAsset(assetResp.Data, coinID)
+	if err != nil {
+		return nil, err
+	}
+	change, err := calc.CheckLeverage(acc, contractID, leverage)
+	if err != nil {
+		return nil, err
+	}
+	if !change.Allowed {
+		return change, fmt.Errorf("leverage %s would leave a negative available balance of %s", leverage, change.After.AvailableBalance)
+	}
+
+	if err := c.UpdateLeverageSetting(ctx, contractID, leverage.String()); err != nil {
+		return nil, err
+	}
+	return change, nil
+}
+
 // CreateMarketOrder creates a new market order with the given parameters
 func (c *Client) getMarketOrderPrice(ctx context.Context, contractId, side string) (*string, error) {
 	// Get metadata for contract info
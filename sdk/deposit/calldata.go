@@ -0,0 +1,136 @@
+package deposit
+
+import (
+	"encoding/hex"
+	"fmt"
+	"math/big"
+	"strconv"
+	"strings"
+
+	"github.com/coin-quant/go-edgex/sdk/internal"
+	"github.com/coin-quant/go-edgex/sdk/metadata"
+	"github.com/shopspring/decimal"
+	"golang.org/x/crypto/sha3"
+)
+
+// L1Call is an unsigned call to an L1 contract, to be signed and broadcast by the caller
+type L1Call struct {
+	To   string
+	Data []byte
+}
+
+// DataHex returns the calldata as a 0x prefixed hex string
+func (c L1Call) DataHex() string {
+	return "0x" + hex.EncodeToString(c.Data)
+}
+
+// DepositCalldata holds the L1 calls of a StarkEx deposit
+type DepositCalldata struct {
+	// Approve lets the StarkEx contract pull the tokens and must be mined before Deposit
+	Approve L1Call
+	Deposit L1Call
+	// TokenAmount is the amount in token units, QuantizedAmount in StarkEx quanta
+	TokenAmount     *big.Int
+	QuantizedAmount *big.Int
+}
+
+// BuildDepositCalldata builds the L1 calls depositing amount of a coin into a position.
+// The deposit calls deposit(starkKey, assetType, vaultId, quantizedAmount) on
+// Global.StarkExContractAddress, with the position as the vault.
+func BuildDepositCalldata(md *metadata.MetaData, coinId, starkKey string, positionId int64, amount decimal.Decimal) (*DepositCalldata, error) {
+	if md == nil || md.Global == nil {
+		return nil, fmt.Errorf("metadata global is nil")
+	}
+	if md.Global.StarkExContractAddress == "" {
+		return nil, fmt.Errorf("starkex contract address is empty")
+	}
+	if !amount.IsPositive() {
+		return nil, fmt.Errorf("deposit amount must be positive: %s", amount)
+	}
+
+	coin, err := md.FindCoin(coinId)
+	if err != nil {
+		return nil, err
+	}
+	_, token, err := md.FindChainToken(md.Global.StarkExChainId, coin)
+	if err != nil {
+		return nil, err
+	}
+	decimals, err := strconv.ParseInt(token.Decimals, 10, 32)
+	if err != nil {
+		return nil, fmt.Errorf("invalid token decimals: %w", err)
+	}
+
+	quantized, err := internal.QuantizeAmount(amount, coin.StarkExResolution)
+	if err != nil {
+		return nil, err
+	}
+	assetType, ok := new(big.Int).SetString(coin.StarkExAssetId, 0)
+	if !ok || !isFieldElement(assetType) {
+		return nil, fmt.Errorf("invalid starkex asset id: %s", coin.StarkExAssetId)
+	}
+	key, ok := new(big.Int).SetString(starkKey, 0)
+	if !ok || !isFieldElement(key) {
+		return nil, fmt.Errorf("invalid stark key: %s", starkKey)
+	}
+
+	shifted := amount.Shift(int32(decimals))
+	if !shifted.IsInteger() {
+		return nil, fmt.Errorf("amount %s has more decimals than token %s: %d", amount, token.Token, decimals)
+	}
+	tokenAmount := shifted.BigInt()
+	spender, err := encodeAddress(md.Global.StarkExContractAddress)
+	if err != nil {
+		return nil, fmt.Errorf("invalid starkex contract address: %w", err)
+	}
+
+	return &DepositCalldata{
+		Approve: L1Call{
+			To:   token.TokenAddress,
+			Data: encodeCall("approve(address,uint256)", spender, encodeUint(tokenAmount)),
+		},
+		Deposit: L1Call{
+			To: md.Global.StarkExContractAddress,
+			Data: encodeCall("deposit(uint256,uint256,uint256,uint256)",
+				encodeUint(key), encodeUint(assetType), encodeUint(big.NewInt(positionId)), encodeUint(big.NewInt(quantized))),
+		},
+		TokenAmount:     tokenAmount,
+		QuantizedAmount: big.NewInt(quantized),
+	}, nil
+}
+
+// fieldBits bounds stark keys and asset IDs, which are StarkEx field elements
+const fieldBits = 251
+
+// isFieldElement reports whether v is in [0, 2^251)
+func isFieldElement(v *big.Int) bool {
+	return v.Sign() >= 0 && v.BitLen() <= fieldBits
+}
+
+// encodeCall ABI encodes a call from its signature and its 32 byte encoded arguments
+func encodeCall(signature string, args ...[]byte) []byte {
+	hash := sha3.NewLegacyKeccak256()
+	hash.Write([]byte(signature))
+	data := hash.Sum(nil)[:4]
+	for _, arg := range args {
+		data = append(data, arg...)
+	}
+	return data
+}
+
+// encodeUint ABI encodes a uint256
+func encodeUint(v *big.Int) []byte {
+	return v.FillBytes(make([]byte, 32))
+}
+
+// encodeAddress ABI encodes a hex address
+func encodeAddress(address string) ([]byte, error) {
+	b, err := hex.DecodeString(strings.TrimPrefix(address, "0x"))
+	if err != nil {
+		return nil, err
+	}
+	if len(b) != 20 {
+		return nil, fmt.Errorf("address must be 20 bytes: %s", address)
+	}
+	return append(make([]byte, 12), b...), nil
+}
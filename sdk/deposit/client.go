@@ -0,0 +1,208 @@
+package deposit
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+
+	"github.com/coin-quant/go-edgex/sdk/internal"
+)
+
+// Client represents the deposit client
+type Client struct {
+	*internal.Client
+}
+
+// NewClient creates a new deposit client
+func NewClient(client *internal.Client) *Client {
+	return &Client{
+		Client: client,
+	}
+}
+
+// CreateDeposit registers a deposit made on L1
+func (c *Client) CreateDeposit(ctx context.Context, params *CreateDepositParams) (*ResultCreateDeposit, error) {
+	url := fmt.Sprintf("%s/api/v1/private/deposit/createDeposit", c.Client.GetBaseURL())
+	body := map[string]interface{}{
+		"accountId":       strconv.FormatInt(c.Client.GetAccountID(), 10),
+		"coinId":          params.CoinId,
+		"amount":          params.Amount,
+		"ethAddress":      params.EthAddress,
+		"erc20Address":    params.Erc20Address,
+		"clientDepositId": params.ClientDepositId,
+		"riskSignature":   params.RiskSignature,
+		"l2Key":           params.L2Key,
+	}
+	if params.L1Tx != nil {
+		body["l1Tx"] = params.L1Tx
+	}
+	if params.ExtraType != "" {
+		body["extraType"] = params.ExtraType
+	}
+	if params.ExtraDataJson != "" {
+		body["extraDataJson"] = params.ExtraDataJson
+	}
+
+	resp, err := c.Client.HttpRequest(url, "POST", body, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create deposit: %w", err)
+	}
+	defer resp.Body.Close()
+
+	respBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read response body: %w", err)
+	}
+
+	var result ResultCreateDeposit
+	if err := json.Unmarshal(respBody, &result); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal response: %w", err)
+	}
+
+	if result.Code != "SUCCESS" {
+		return nil, fmt.Errorf("request failed with code: %s", result.Code)
+	}
+
+	return &result, nil
+}
+
+// GetActiveDeposit gets a page of deposit records
+func (c *Client) GetActiveDeposit(ctx context.Context, params GetActiveDepositParams) (*ResultPageDataDeposit, error) {
+	url := fmt.Sprintf("%s/api/v1/private/deposit/getActiveDeposit", c.Client.GetBaseURL())
+	queryParams := map[string]string{
+		"accountId": strconv.FormatInt(c.Client.GetAccountID(), 10),
+	}
+
+	if params.Size > 0 {
+		queryParams["size"] = strconv.FormatInt(int64(params.Size), 10)
+	}
+	if params.OffsetData != "" {
+		queryParams["offsetData"] = params.OffsetData
+	}
+
+	resp, err := c.Client.HttpRequest(url, "GET", nil, queryParams)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get active deposit: %w", err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read response body: %w", err)
+	}
+
+	var result ResultPageDataDeposit
+	if err := json.Unmarshal(body, &result); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal response: %w", err)
+	}
+
+	if result.Code != "SUCCESS" {
+		return nil, fmt.Errorf("request failed with code: %s", result.Code)
+	}
+
+	return &result, nil
+}
+
+// GetDepositById gets deposit records by ID
+func (c *Client) GetDepositById(ctx context.Context, depositIds []string) (*ResultListDeposit, error) {
+	url := fmt.Sprintf("%s/api/v1/private/deposit/getDepositById", c.Client.GetBaseURL())
+	queryParams := map[string]string{
+		"accountId":     strconv.FormatInt(c.Client.GetAccountID(), 10),
+		"depositIdList": internal.JoinStrings(depositIds),
+	}
+
+	resp, err := c.Client.HttpRequest(url, "GET", nil, queryParams)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get deposit by id: %w", err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read response body: %w", err)
+	}
+
+	var result ResultListDeposit
+	if err := json.Unmarshal(body, &result); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal response: %w", err)
+	}
+
+	if result.Code != "SUCCESS" {
+		return nil, fmt.Errorf("request failed with code: %s", result.Code)
+	}
+
+	return &result, nil
+}
+
+// GetDepositByClientDepositId gets deposit records by client deposit ID
+func (c *Client) GetDepositByClientDepositId(ctx context.Context, clientDepositIds []string) (*ResultListDeposit, error) {
+	url := fmt.Sprintf("%s/api/v1/private/deposit/getDepositByClientDepositId", c.Client.GetBaseURL())
+	queryParams := map[string]string{
+		"accountId":           strconv.FormatInt(c.Client.GetAccountID(), 10),
+		"clientDepositIdList": internal.JoinStrings(clientDepositIds),
+	}
+
+	resp, err := c.Client.HttpRequest(url, "GET", nil, queryParams)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get deposit by client deposit id: %w", err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read response body: %w", err)
+	}
+
+	var result ResultListDeposit
+	if err := json.Unmarshal(body, &result); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal response: %w", err)
+	}
+
+	if result.Code != "SUCCESS" {
+		return nil, fmt.Errorf("request failed with code: %s", result.Code)
+	}
+
+	return &result, nil
+}
+
+// RequestRelayerSignAndBroadcast asks the relayer to sign and broadcast a gasless deposit
+func (c *Client) RequestRelayerSignAndBroadcast(ctx context.Context, params *RequestRelayerSignAndBroadcastParams) (*ResultRequestRelayerSignAndBroadcast, error) {
+	url := fmt.Sprintf("%s/api/v1/private/deposit/requestRelayerSignAndBroadcast", c.Client.GetBaseURL())
+	body := map[string]interface{}{
+		"deadline":     params.Deadline,
+		"r":            params.R,
+		"s":            params.S,
+		"v":            params.V,
+		"type":         params.Type,
+		"amount":       params.Amount,
+		"owner":        params.Owner,
+		"starkKey":     params.StarkKey,
+		"positionId":   params.PositionId,
+		"chainId":      params.ChainId,
+		"mpcSignature": params.MpcSignature,
+	}
+
+	resp, err := c.Client.HttpRequest(url, "POST", body, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to request relayer sign and broadcast: %w", err)
+	}
+	defer resp.Body.Close()
+
+	respBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read response body: %w", err)
+	}
+
+	var result ResultRequestRelayerSignAndBroadcast
+	if err := json.Unmarshal(respBody, &result); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal response: %w", err)
+	}
+
+	if result.Code != "SUCCESS" {
+		return nil, fmt.Errorf("request failed with code: %s", result.Code)
+	}
+
+	return &result, nil
+}
@@ -0,0 +1,178 @@
+package deposit
+
+import "strings"
+
+// Status is the status of a deposit
+type Status string
+
+const (
+	// StatusPendingCensoring is a deposit seen on L1 and waiting for censoring
+	StatusPendingCensoring Status = "PENDING_CENSORING"
+	// StatusSuccessCensorSuccess is a deposit credited to the account
+	StatusSuccessCensorSuccess Status = "SUCCESS_CENSOR_SUCCESS"
+	// StatusSuccessL2Approved is a credited deposit whose L2 batch was verified
+	StatusSuccessL2Approved Status = "SUCCESS_L2_APPROVED"
+	// StatusFailedCensorFailure is a deposit rejected by censoring
+	StatusFailedCensorFailure Status = "FAILED_CENSOR_FAILURE"
+	// StatusFailedL2Reject is a deposit rejected on L2
+	StatusFailedL2Reject Status = "FAILED_L2_REJECT"
+	// StatusFailedL2RejectApproved is a deposit rejected on L2 whose batch was verified
+	StatusFailedL2RejectApproved Status = "FAILED_L2_REJECT_APPROVED"
+)
+
+// IsSuccess reports whether the deposit was credited
+func (s Status) IsSuccess() bool {
+	return strings.HasPrefix(string(s), "SUCCESS_")
+}
+
+// IsFailed reports whether the deposit was rejected
+func (s Status) IsFailed() bool {
+	return strings.HasPrefix(string(s), "FAILED_")
+}
+
+// IsTerminal reports whether the status is final. With waitForL2 a deposit is only
+// final once its L2 batch was verified or it failed censoring.
+func (s Status) IsTerminal(waitForL2 bool) bool {
+	if !waitForL2 {
+		return s.IsSuccess() || s.IsFailed()
+	}
+	return s == StatusSuccessL2Approved || s == StatusFailedL2RejectApproved || s == StatusFailedCensorFailure
+}
+
+// L1Tx represents the L1 transaction of a deposit
+type L1Tx struct {
+	Hash        *string `json:"hash,omitempty"`
+	Index       *int32  `json:"index,omitempty"`
+	Time        *string `json:"time,omitempty"`
+	BlockHeight *string `json:"blockHeight,omitempty"`
+}
+
+// L2Signature represents a Layer 2 signature
+type L2Signature struct {
+	R *string `json:"r,omitempty"`
+	S *string `json:"s,omitempty"`
+	V *string `json:"v,omitempty"`
+}
+
+// Deposit represents a deposit record
+type Deposit struct {
+	Id                      *string      `json:"id,omitempty"`
+	UserId                  *string      `json:"userId,omitempty"`
+	AccountId               *string      `json:"accountId,omitempty"`
+	CoinId                  *string      `json:"coinId,omitempty"`
+	Amount                  *string      `json:"amount,omitempty"`
+	EthAddress              *string      `json:"ethAddress,omitempty"`
+	Erc20Address            *string      `json:"erc20Address,omitempty"`
+	ClientDepositId         *string      `json:"clientDepositId,omitempty"`
+	L1Tx                    *L1Tx        `json:"l1Tx,omitempty"`
+	RiskSignature           *L2Signature `json:"riskSignature,omitempty"`
+	L2Key                   *string      `json:"l2Key,omitempty"`
+	ExtraType               *string      `json:"extraType,omitempty"`
+	ExtraDataJson           *string      `json:"extraDataJson,omitempty"`
+	Status                  *string      `json:"status,omitempty"`
+	CollateralTransactionId *string      `json:"collateralTransactionId,omitempty"`
+	CensorTxId              *string      `json:"censorTxId,omitempty"`
+	CensorTime              *string      `json:"censorTime,omitempty"`
+	CensorFailCode          *string      `json:"censorFailCode,omitempty"`
+	CensorFailReason        *string      `json:"censorFailReason,omitempty"`
+	L2TxId                  *string      `json:"l2TxId,omitempty"`
+	L2RejectTime            *string      `json:"l2RejectTime,omitempty"`
+	L2RejectCode            *string      `json:"l2RejectCode,omitempty"`
+	L2RejectReason          *string      `json:"l2RejectReason,omitempty"`
+	L2ApprovedTime          *string      `json:"l2ApprovedTime,omitempty"`
+	CreatedTime             *string      `json:"createdTime,omitempty"`
+	UpdatedTime             *string      `json:"updatedTime,omitempty"`
+}
+
+// GetStatus returns the typed status of the deposit
+func (d *Deposit) GetStatus() Status {
+	if d.Status == nil {
+		return ""
+	}
+	return Status(*d.Status)
+}
+
+// CreateDeposit represents the result of creating a deposit
+type CreateDeposit struct {
+	DepositId *string `json:"depositId,omitempty"`
+}
+
+// PageDataDeposit represents a page of deposit records
+type PageDataDeposit struct {
+	DataList           []Deposit `json:"dataList"`
+	NextPageOffsetData *string   `json:"nextPageOffsetData,omitempty"`
+}
+
+// RequestRelayerSignAndBroadcast represents the result of a relayer broadcast request
+type RequestRelayerSignAndBroadcast struct {
+	Success *bool `json:"success,omitempty"`
+}
+
+// ResultCreateDeposit represents the result of creating a deposit
+type ResultCreateDeposit struct {
+	Code       string         `json:"code"`
+	Data       *CreateDeposit `json:"data"`
+	ErrorParam interface{}    `json:"errorParam"`
+	ErrorMsg   string         `json:"msg"`
+}
+
+// ResultListDeposit represents list of deposit records
+type ResultListDeposit struct {
+	Code       string      `json:"code"`
+	Data       []Deposit   `json:"data"`
+	ErrorParam interface{} `json:"errorParam"`
+	ErrorMsg   string      `json:"msg"`
+}
+
+// ResultPageDataDeposit represents paginated deposit records
+type ResultPageDataDeposit struct {
+	Code       string           `json:"code"`
+	Data       *PageDataDeposit `json:"data"`
+	ErrorParam interface{}      `json:"errorParam"`
+	ErrorMsg   string           `json:"msg"`
+}
+
+// ResultRequestRelayerSignAndBroadcast represents the result of a relayer broadcast request
+type ResultRequestRelayerSignAndBroadcast struct {
+	Code       string                          `json:"code"`
+	Data       *RequestRelayerSignAndBroadcast `json:"data"`
+	ErrorParam interface{}                     `json:"errorParam"`
+	ErrorMsg   string                          `json:"msg"`
+}
+
+// Request parameter types
+
+// CreateDepositParams represents parameters for CreateDeposit
+type CreateDepositParams struct {
+	CoinId          string
+	Amount          string
+	EthAddress      string
+	Erc20Address    string
+	ClientDepositId string
+	L1Tx            *L1Tx
+	RiskSignature   string
+	L2Key           string
+	ExtraType       string
+	ExtraDataJson   string
+}
+
+// GetActiveDepositParams represents parameters for GetActiveDeposit
+type GetActiveDepositParams struct {
+	Size       int32
+	OffsetData string
+}
+
+// RequestRelayerSignAndBroadcastParams represents parameters for RequestRelayerSignAndBroadcast
+type RequestRelayerSignAndBroadcastParams struct {
+	Deadline     string
+	R            string
+	S            string
+	V            string
+	Type         string
+	Amount       string
+	Owner        string
+	StarkKey     string
+	PositionId   string
+	ChainId      string
+	MpcSignature string
+}
@@ -0,0 +1,77 @@
+package deposit
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/coin-quant/go-edgex/sdk/internal"
+)
+
+// defaultPollInterval is the interval used by WaitForDeposit when none is given
+const defaultPollInterval = 5 * time.Second
+
+// WaitParams represents parameters for WaitForDeposit
+type WaitParams struct {
+	// PollInterval defaults to 5 seconds
+	PollInterval time.Duration
+	// WaitForL2 waits for the L2 batch verification instead of returning once the
+	// deposit is credited
+	WaitForL2 bool
+	// OnStatus is called whenever the status of the deposit changes
+	OnStatus func(deposit *Deposit)
+}
+
+// WaitForDeposit polls a deposit until it reaches a terminal status or ctx is done.
+// A rejected deposit is returned together with an error carrying the censor or L2
+// reject reason.
+func (c *Client) WaitForDeposit(ctx context.Context, depositId string, params WaitParams) (*Deposit, error) {
+	interval := params.PollInterval
+	if interval <= 0 {
+		interval = defaultPollInterval
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	var last Status
+	for {
+		resp, err := c.GetDepositById(ctx, []string{depositId})
+		if err != nil {
+			return nil, err
+		}
+		if len(resp.Data) > 0 {
+			deposit := &resp.Data[0]
+			status := deposit.GetStatus()
+			if status != last {
+				last = status
+				if params.OnStatus != nil {
+					params.OnStatus(deposit)
+				}
+			}
+			if status.IsTerminal(params.WaitForL2) {
+				if status.IsFailed() {
+					return deposit, fmt.Errorf("deposit %s failed with status %s: %s", depositId, status, deposit.FailReason())
+				}
+				return deposit, nil
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil, fmt.Errorf("deposit %s not final: %w", depositId, ctx.Err())
+		case <-ticker.C:
+		}
+	}
+}
+
+// FailReason returns the censor or L2 reject reason of a failed deposit
+func (d *Deposit) FailReason() string {
+	switch {
+	case d.CensorFailCode != nil && *d.CensorFailCode != "":
+		return fmt.Sprintf("censor %s: %s", *d.CensorFailCode, internal.StringValue(d.CensorFailReason))
+	case d.L2RejectCode != nil && *d.L2RejectCode != "":
+		return fmt.Sprintf("l2 reject %s: %s", *d.L2RejectCode, internal.StringValue(d.L2RejectReason))
+	default:
+		return ""
+	}
+}
@@ -0,0 +1,271 @@
+package funding
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/coin-quant/go-edgex/sdk/account"
+	"github.com/coin-quant/go-edgex/sdk/internal"
+	"github.com/shopspring/decimal"
+)
+
+// CollateralTransactionTypeSettleFunding is the collateral transaction type of funding settlements
+const CollateralTransactionTypeSettleFunding = "SETTLE_FUNDING_FEE"
+
+// historyPageSize is the page size used when paging funding transactions
+const historyPageSize = 100
+
+// Payment is a settled funding payment. Amount is positive when funding was received
+// and negative when it was paid.
+type Payment struct {
+	TransactionID string
+	CoinID        string
+	ContractID    string
+	Time          time.Time
+	Rate          decimal.Decimal
+	PositionSize  decimal.Decimal
+	OraclePrice   decimal.Decimal
+	Amount        decimal.Decimal
+}
+
+// PaymentFromTransaction converts a funding collateral transaction into a Payment.
+// It reports false for transactions of other types, and an error for a funding
+// transaction with a malformed amount.
+func PaymentFromTransaction(tx account.CollateralTransaction) (Payment, bool, error) {
+	if tx.Type == nil || *tx.Type != CollateralTransactionTypeSettleFunding {
+		return Payment{}, false, nil
+	}
+
+	var payment Payment
+	var err error
+	if payment.Rate, err = tx.FundingRateDecimal(); err != nil {
+		return Payment{}, true, err
+	}
+	if payment.PositionSize, err = tx.FundingPositionSizeDecimal(); err != nil {
+		return Payment{}, true, err
+	}
+	if payment.OraclePrice, err = tx.FundingOraclePriceDecimal(); err != nil {
+		return Payment{}, true, err
+	}
+	if payment.Amount, err = tx.DeltaAmountDecimal(); err != nil {
+		return Payment{}, true, err
+	}
+	if tx.Id != nil {
+		payment.TransactionID = *tx.Id
+	}
+	if tx.CoinId != nil {
+		payment.CoinID = *tx.CoinId
+	}
+	if tx.PositionContractId != nil {
+		payment.ContractID = *tx.PositionContractId
+	}
+	timestamp := tx.FundingTime
+	if timestamp == nil || *timestamp == "" || *timestamp == "0" {
+		timestamp = tx.CreatedTime
+	}
+	if timestamp != nil {
+		if ms, err := strconv.ParseInt(*timestamp, 10, 64); err == nil {
+			payment.Time = time.UnixMilli(ms).UTC()
+		}
+	}
+	return payment, true, nil
+}
+
+// DailyFunding aggregates the funding payments of one contract over one UTC day
+type DailyFunding struct {
+	Day        time.Time
+	ContractID string
+	Amount     decimal.Decimal
+	Payments   int
+}
+
+// Summary aggregates funding payments
+type Summary struct {
+	Payments []Payment
+	// Total is the net funding over all payments
+	Total decimal.Decimal
+	// Paid and Received split Total into its negative and positive payments
+	Paid       decimal.Decimal
+	Received   decimal.Decimal
+	ByContract map[string]decimal.Decimal
+	// Daily holds per contract, per day totals ordered by day then contract
+	Daily []DailyFunding
+}
+
+// Summarize aggregates payments per contract and per day
+func Summarize(payments []Payment) *Summary {
+	s := &Summary{
+		Payments:   payments,
+		ByContract: make(map[string]decimal.Decimal),
+	}
+
+	type dayKey struct {
+		day        time.Time
+		contractID string
+	}
+	daily := make(map[dayKey]*DailyFunding)
+	for _, payment := range payments {
+		s.Total = s.Total.Add(payment.Amount)
+		if payment.Amount.IsNegative() {
+			s.Paid = s.Paid.Add(payment.Amount)
+		} else {
+			s.Received = s.Received.Add(payment.Amount)
+		}
+		s.ByContract[payment.ContractID] = s.ByContract[payment.ContractID].Add(payment.Amount)
+
+		key := dayKey{payment.Time.UTC().Truncate(24 * time.Hour), payment.ContractID}
+		day, ok := daily[key]
+		if !ok {
+			day = &DailyFunding{Day: key.day, ContractID: key.contractID}
+			daily[key] = day
+		}
+		day.Amount = day.Amount.Add(payment.Amount)
+		day.Payments++
+	}
+
+	for _, day := range daily {
+		s.Daily = append(s.Daily, *day)
+	}
+	sort.Slice(s.Daily, func(i, j int) bool {
+		if !s.Daily[i].Day.Equal(s.Daily[j].Day) {
+			return s.Daily[i].Day.Before(s.Daily[j].Day)
+		}
+		return s.Daily[i].ContractID < s.Daily[j].ContractID
+	})
+	return s
+}
+
+// History pages all funding collateral transactions of a coin created in [from, to)
+// and summarizes them. An empty coinID includes all coins, a zero to means up to now.
+func History(ctx context.Context, accounts *account.Client, coinID string, from, to time.Time) (*Summary, error) {
+	params := account.GetCollateralTransactionPageParams{
+		Size:                   historyPageSize,
+		FilterTypeList:         []string{CollateralTransactionTypeSettleFunding},
+		FilterStartCreatedTime: from.UnixMilli(),
+	}
+	if coinID != "" {
+		params.FilterCoinIDList = []string{coinID}
+	}
+	if !to.IsZero() {
+		params.FilterEndCreatedTime = to.UnixMilli()
+	}
+
+	var payments []Payment
+	for {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		resp, err := accounts.GetCollateralTransactionPage(ctx, params)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get funding history: %w", err)
+		}
+		if resp.Data == nil {
+			break
+		}
+		for _, tx := range resp.Data.DataList {
+			payment, ok, err := PaymentFromTransaction(tx)
+			if err != nil {
+				return nil, fmt.Errorf("failed to get funding history: transaction %s: %w", internal.StringValue(tx.Id), err)
+			}
+			if ok {
+				payments = append(payments, payment)
+			}
+		}
+		next := resp.Data.NextPageOffsetData
+		if next == nil || *next == "" || len(resp.Data.DataList) == 0 {
+			break
+		}
+		params.OffsetData = *next
+	}
+
+	sort.SliceStable(payments, func(i, j int) bool {
+		return payments[i].Time.Before(payments[j].Time)
+	})
+	return Summarize(payments), nil
+}
+
+// Estimate is a projected funding payment for a position
+type Estimate struct {
+	ContractID   string
+	PositionSize decimal.Decimal
+	OraclePrice  decimal.Decimal
+	// Rate is the predicted funding rate
+	Rate decimal.Decimal
+	// Amount uses the Payment sign convention: longs pay a positive rate
+	Amount decimal.Decimal
+	// FundingTime is the estimated settlement time, zero if unknown
+	FundingTime time.Time
+}
+
+// EstimatePayment projects the next funding payment of a position of size from a
+// funding rate record. It uses PredictedFundingRate, falling back to ForecastFundingRate.
+func EstimatePayment(rate FundingRate, size decimal.Decimal) (Estimate, error) {
+	estimate := Estimate{PositionSize: size}
+	if rate.ContractId != nil {
+		estimate.ContractID = *rate.ContractId
+	}
+
+	predicted := rate.PredictedFundingRate
+	if predicted == nil || *predicted == "" {
+		predicted = rate.ForecastFundingRate
+	}
+	if predicted == nil || *predicted == "" {
+		return estimate, fmt.Errorf("no predicted funding rate for contract: %s", estimate.ContractID)
+	}
+	value, err := decimal.NewFromString(*predicted)
+	if err != nil {
+		return estimate, fmt.Errorf("invalid predicted funding rate: %w", err)
+	}
+	estimate.Rate = value
+
+	if rate.OraclePrice == nil {
+		return estimate, fmt.Errorf("no oracle price for contract: %s", estimate.ContractID)
+	}
+	price, err := decimal.NewFromString(*rate.OraclePrice)
+	if err != nil {
+		return estimate, fmt.Errorf("invalid oracle price: %w", err)
+	}
+	estimate.OraclePrice = price
+	estimate.Amount = size.Mul(price).Mul(value).Neg()
+
+	if rate.FundingTimestamp != nil && rate.FundingRateIntervalMin != nil {
+		ts, tsErr := strconv.ParseInt(*rate.FundingTimestamp, 10, 64)
+		interval, intervalErr := strconv.ParseInt(*rate.FundingRateIntervalMin, 10, 64)
+		if tsErr == nil && intervalErr == nil && ts > 0 {
+			estimate.FundingTime = time.UnixMilli(ts).Add(time.Duration(interval) * time.Minute).UTC()
+		}
+	}
+	return estimate, nil
+}
+
+// EstimateNextPayments projects the next funding payment of every open position
+// from the latest funding rates
+func (c *Client) EstimateNextPayments(ctx context.Context, positions []account.Position) ([]Estimate, error) {
+	var estimates []Estimate
+	for _, position := range positions {
+		size, err := position.OpenSizeDecimal()
+		if err != nil {
+			return nil, fmt.Errorf("position %s: %w", position.ContractID, err)
+		}
+		if size.IsZero() {
+			continue
+		}
+		resp, err := c.GetLatestFundingRate(ctx, GetLatestFundingRateParams{ContractID: position.ContractID})
+		if err != nil {
+			return nil, err
+		}
+		if len(resp.Data) == 0 {
+			return nil, fmt.Errorf("no funding rate for contract: %s", position.ContractID)
+		}
+		estimate, err := EstimatePayment(resp.Data[0], size)
+		if err != nil {
+			return nil, err
+		}
+		estimate.ContractID = position.ContractID
+		estimates = append(estimates, estimate)
+	}
+	return estimates, nil
+}
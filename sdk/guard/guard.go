@@ -0,0 +1,458 @@
+// Package guard enforces account-level pre-trade risk limits in front of order
+// creation and provides a kill switch that cancels all orders and blocks new ones.
+package guard
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/coin-quant/go-edgex/sdk/order"
+	"github.com/coin-quant/go-edgex/sdk/portfolio"
+	"github.com/shopspring/decimal"
+)
+
+// Limit identifies a pre-trade limit
+type Limit string
+
+const (
+	// LimitOrderNotional caps the notional value of a single order
+	LimitOrderNotional Limit = "ORDER_NOTIONAL"
+	// LimitPositionSize caps the absolute position size of a contract after the order
+	LimitPositionSize Limit = "POSITION_SIZE"
+	// LimitGrossExposure caps the total gross exposure after the order
+	LimitGrossExposure Limit = "GROSS_EXPOSURE"
+	// LimitOpenOrders caps the number of open orders
+	LimitOpenOrders Limit = "OPEN_ORDERS"
+	// LimitOrderRate caps the number of orders sent per second
+	LimitOrderRate Limit = "ORDER_RATE"
+	// LimitPriceCollar caps the relative distance between the order price and the reference price
+	LimitPriceCollar Limit = "PRICE_COLLAR"
+	// LimitDailyLoss caps the equity drop since the start of the UTC day
+	LimitDailyLoss Limit = "DAILY_LOSS"
+	// LimitMissingPrice rejects orders on contracts without a reference price
+	LimitMissingPrice Limit = "MISSING_PRICE"
+)
+
+// ErrKillSwitch is returned for every order while the kill switch is engaged
+var ErrKillSwitch = errors.New("kill switch engaged")
+
+// LimitError is returned when an order breaches a limit
+type LimitError struct {
+	Limit      Limit
+	ContractID string
+	Value      decimal.Decimal
+	Threshold  decimal.Decimal
+}
+
+func (e *LimitError) Error() string {
+	if e.ContractID == "" {
+		return fmt.Sprintf("risk limit %s breached: %s exceeds %s", e.Limit, e.Value, e.Threshold)
+	}
+	return fmt.Sprintf("risk limit %s breached on contract %s: %s exceeds %s", e.Limit, e.ContractID, e.Value, e.Threshold)
+}
+
+// Limits holds the pre-trade limits. Zero values disable a limit.
+type Limits struct {
+	// MaxOrderNotional caps size times price of a single order
+	MaxOrderNotional decimal.Decimal
+	// MaxPositionSize caps the absolute position size per contract
+	MaxPositionSize map[string]decimal.Decimal
+	// DefaultMaxPositionSize applies to contracts missing from MaxPositionSize
+	DefaultMaxPositionSize decimal.Decimal
+	// MaxGrossExposure caps the sum of absolute position values
+	MaxGrossExposure decimal.Decimal
+	// MaxOpenOrders caps the number of open orders
+	MaxOpenOrders int
+	// MaxOrdersPerSecond caps the order rate over a sliding one second window
+	MaxOrdersPerSecond int
+	// PriceCollar caps |price - reference| / reference for limit orders, e.g. 0.05 for 5%
+	PriceCollar decimal.Decimal
+	// MaxDailyLoss caps the equity drop since the first check of the UTC day
+	MaxDailyLoss decimal.Decimal
+	// KillOn lists the limits whose breach also trips the kill switch
+	KillOn []Limit
+}
+
+// OrderCreator creates orders, as sdk.Client does
+type OrderCreator interface {
+	CreateOrder(ctx context.Context, params *order.CreateOrderParams) (*order.ResultCreateOrder, error)
+}
+
+// OrderCanceller cancels all active orders, as sdk.Client and order.Client do
+type OrderCanceller interface {
+	CancelAllOrders(ctx context.Context, contractIDs []string) (interface{}, error)
+}
+
+// State provides the positions, prices and equity limits are checked against.
+// *portfolio.Portfolio implements it; its price source is the collar reference.
+type State interface {
+	Snapshot() portfolio.Snapshot
+}
+
+// Guard checks orders against Limits before passing them to an OrderCreator.
+// It is safe for concurrent use.
+type Guard struct {
+	creator   OrderCreator
+	canceller OrderCanceller
+	state     State
+	limits    Limits
+	now       func() time.Time
+
+	mu         sync.Mutex
+	sent       []time.Time
+	openOrders map[string]struct{}
+	pending    int
+	day        time.Time
+	dayEquity  decimal.Decimal
+	killed     error
+	onTrip     []func(reason error)
+}
+
+// New creates a new Guard. state may be nil, which disables the limits needing
+// positions, prices or equity.
+func New(creator OrderCreator, canceller OrderCanceller, state State, limits Limits) *Guard {
+	return &Guard{
+		creator:    creator,
+		canceller:  canceller,
+		state:      state,
+		limits:     limits,
+		now:        time.Now,
+		openOrders: make(map[string]struct{}),
+	}
+}
+
+// SetClock replaces the time source, for tests
+func (g *Guard) SetClock(now func() time.Time) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	g.now = now
+}
+
+// OnTrip registers a callback run after the kill switch trips
+func (g *Guard) OnTrip(callback func(reason error)) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	g.onTrip = append(g.onTrip, callback)
+}
+
+// CreateOrder checks params against the limits and creates the order if none is breached
+func (g *Guard) CreateOrder(ctx context.Context, params *order.CreateOrderParams) (*order.ResultCreateOrder, error) {
+	g.mu.Lock()
+	err := g.check(params)
+	if err == nil {
+		// Reserve the rate and open order slots before releasing the lock. Send times
+		// are only kept while the rate is limited, as check trims them.
+		if g.limits.MaxOrdersPerSecond > 0 {
+			g.sent = append(g.sent, g.now())
+		}
+		g.pending++
+	}
+	g.mu.Unlock()
+
+	if err != nil {
+		g.maybeTrip(ctx, err)
+		return nil, err
+	}
+
+	result, err := g.creator.CreateOrder(ctx, params)
+
+	g.mu.Lock()
+	g.pending--
+	if err == nil && result != nil && result.Data != nil && result.Data.OrderId != nil {
+		g.openOrders[*result.Data.OrderId] = struct{}{}
+	}
+	g.mu.Unlock()
+	return result, err
+}
+
+// Check reports whether params would pass the limits without creating the order
+func (g *Guard) Check(params *order.CreateOrderParams) error {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	return g.check(params)
+}
+
+// check runs every limit. The caller must hold mu.
+func (g *Guard) check(params *order.CreateOrderParams) error {
+	if g.killed != nil {
+		return fmt.Errorf("%w: %v", ErrKillSwitch, g.killed)
+	}
+	now := g.now()
+
+	if g.limits.MaxOrdersPerSecond > 0 {
+		cutoff := now.Add(-time.Second)
+		i := 0
+		for i < len(g.sent) && !g.sent[i].After(cutoff) {
+			i++
+		}
+		g.sent = g.sent[i:]
+		if len(g.sent) >= g.limits.MaxOrdersPerSecond {
+			return &LimitError{
+				Limit:     LimitOrderRate,
+				Value:     decimal.NewFromInt(int64(len(g.sent) + 1)),
+				Threshold: decimal.NewFromInt(int64(g.limits.MaxOrdersPerSecond)),
+			}
+		}
+	}
+
+	if g.limits.MaxOpenOrders > 0 && !params.ReduceOnly {
+		open := len(g.openOrders) + g.pending
+		if open >= g.limits.MaxOpenOrders {
+			return &LimitError{
+				Limit:     LimitOpenOrders,
+				Value:     decimal.NewFromInt(int64(open + 1)),
+				Threshold: decimal.NewFromInt(int64(g.limits.MaxOpenOrders)),
+			}
+		}
+	}
+
+	size, err := decimal.NewFromString(params.Size)
+	if err != nil {
+		return fmt.Errorf("invalid order size: %w", err)
+	}
+	var price decimal.Decimal
+	if params.Price != "" {
+		if price, err = decimal.NewFromString(params.Price); err != nil {
+			return fmt.Errorf("invalid order price: %w", err)
+		}
+	}
+	if params.Side == order.OrderSideSell {
+		size = size.Neg()
+	} else if params.Side != order.OrderSideBuy {
+		return fmt.Errorf("invalid order side: %s", params.Side)
+	}
+
+	if g.state == nil {
+		return g.checkNotional(params.ContractId, size, price)
+	}
+	snapshot := g.state.Snapshot()
+	contract := snapshot.Contracts[params.ContractId]
+	reference := contract.Price
+
+	if !g.limits.MaxDailyLoss.IsZero() {
+		loss, ok := g.dailyLoss(snapshot)
+		if ok && loss.GreaterThan(g.limits.MaxDailyLoss) {
+			return &LimitError{Limit: LimitDailyLoss, Value: loss, Threshold: g.limits.MaxDailyLoss}
+		}
+	}
+
+	if !g.limits.PriceCollar.IsZero() && params.Type != order.OrderTypeMarket && price.IsPositive() {
+		if !reference.IsPositive() {
+			return &LimitError{Limit: LimitMissingPrice, ContractID: params.ContractId}
+		}
+		distance := price.Sub(reference).Abs().Div(reference)
+		if distance.GreaterThan(g.limits.PriceCollar) {
+			return &LimitError{Limit: LimitPriceCollar, ContractID: params.ContractId, Value: distance, Threshold: g.limits.PriceCollar}
+		}
+	}
+
+	// Market orders are valued at the reference price
+	if params.Type == order.OrderTypeMarket || !price.IsPositive() {
+		price = reference
+	}
+	if err := g.checkNotional(params.ContractId, size, price); err != nil {
+		return err
+	}
+
+	current := contract.Size
+	next := current.Add(size)
+	// Orders that only shrink a position are always allowed through the size limits
+	reducing := next.Abs().LessThanOrEqual(current.Abs()) && next.Sign()*current.Sign() >= 0
+
+	if maxSize := g.maxPositionSize(params.ContractId); !maxSize.IsZero() && !reducing && next.Abs().GreaterThan(maxSize) {
+		return &LimitError{Limit: LimitPositionSize, ContractID: params.ContractId, Value: next.Abs(), Threshold: maxSize}
+	}
+
+	if !g.limits.MaxGrossExposure.IsZero() && !reducing {
+		if !reference.IsPositive() {
+			return &LimitError{Limit: LimitMissingPrice, ContractID: params.ContractId}
+		}
+		gross := snapshot.GrossExposure.Sub(contract.Exposure.Abs()).Add(next.Mul(reference).Abs())
+		if gross.GreaterThan(g.limits.MaxGrossExposure) {
+			return &LimitError{Limit: LimitGrossExposure, ContractID: params.ContractId, Value: gross, Threshold: g.limits.MaxGrossExposure}
+		}
+	}
+	return nil
+}
+
+// checkNotional checks the order notional limit
+func (g *Guard) checkNotional(contractID string, size, price decimal.Decimal) error {
+	if g.limits.MaxOrderNotional.IsZero() {
+		return nil
+	}
+	if !price.IsPositive() {
+		return &LimitError{Limit: LimitMissingPrice, ContractID: contractID}
+	}
+	notional := size.Abs().Mul(price)
+	if notional.GreaterThan(g.limits.MaxOrderNotional) {
+		return &LimitError{Limit: LimitOrderNotional, ContractID: contractID, Value: notional, Threshold: g.limits.MaxOrderNotional}
+	}
+	return nil
+}
+
+// maxPositionSize returns the position size limit of a contract
+func (g *Guard) maxPositionSize(contractID string) decimal.Decimal {
+	if maxSize, ok := g.limits.MaxPositionSize[contractID]; ok {
+		return maxSize
+	}
+	return g.limits.DefaultMaxPositionSize
+}
+
+// dailyLoss returns the equity drop since the day baseline, rolling the baseline
+// over at UTC midnight. While a position has no price its value is missing from
+// equity, so ok is false and no baseline is taken. The caller must hold mu.
+func (g *Guard) dailyLoss(snapshot portfolio.Snapshot) (loss decimal.Decimal, ok bool) {
+	if len(snapshot.MissingPrices) > 0 {
+		return decimal.Zero, false
+	}
+	day := g.now().UTC().Truncate(24 * time.Hour)
+	if !day.Equal(g.day) {
+		g.day = day
+		g.dayEquity = snapshot.Equity
+	}
+	return g.dayEquity.Sub(snapshot.Equity), true
+}
+
+// ResetDailyBaseline makes the current equity the baseline of the daily loss limit.
+// If a position has no price yet, the baseline is taken once all prices are known.
+func (g *Guard) ResetDailyBaseline() {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	g.day = time.Time{}
+	if g.state != nil {
+		_, _ = g.dailyLoss(g.state.Snapshot())
+	}
+}
+
+// maybeTrip trips the kill switch if err is a breach of a limit listed in KillOn
+func (g *Guard) maybeTrip(ctx context.Context, err error) {
+	var limitErr *LimitError
+	if !errors.As(err, &limitErr) {
+		return
+	}
+	for _, limit := range g.limits.KillOn {
+		if limit == limitErr.Limit {
+			// The breach is already reported to the caller; cancellation errors are not
+			_ = g.Trip(ctx, err)
+			return
+		}
+	}
+}
+
+// Trip engages the kill switch: new orders are rejected with ErrKillSwitch and all
+// active orders are cancelled. The switch stays engaged even if cancellation fails.
+func (g *Guard) Trip(ctx context.Context, reason error) error {
+	g.mu.Lock()
+	if g.killed != nil {
+		g.mu.Unlock()
+		return nil
+	}
+	if reason == nil {
+		reason = errors.New("tripped manually")
+	}
+	g.killed = reason
+	callbacks := g.onTrip
+	g.mu.Unlock()
+
+	var err error
+	if g.canceller != nil {
+		if _, cancelErr := g.canceller.CancelAllOrders(ctx, nil); cancelErr != nil {
+			err = fmt.Errorf("failed to cancel orders on kill switch: %w", cancelErr)
+		} else {
+			g.mu.Lock()
+			g.openOrders = make(map[string]struct{})
+			g.mu.Unlock()
+		}
+	}
+
+	for _, callback := range callbacks {
+		callback(reason)
+	}
+	return err
+}
+
+// Reset disengages the kill switch
+func (g *Guard) Reset() {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	g.killed = nil
+}
+
+// Engaged returns the reason the kill switch tripped, nil if it is not engaged
+func (g *Guard) Engaged() error {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	return g.killed
+}
+
+// OpenOrders returns the number of orders the guard counts as open
+func (g *Guard) OpenOrders() int {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	return len(g.openOrders)
+}
+
+// SetOpenOrders replaces the open orders, e.g. with the IDs from GetActiveOrders at startup
+func (g *Guard) SetOpenOrders(orderIDs []string) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	g.openOrders = make(map[string]struct{}, len(orderIDs))
+	for _, id := range orderIDs {
+		g.openOrders[id] = struct{}{}
+	}
+}
+
+// HandlePrivateMessage is a ws.MessageHandler for private account events. It keeps
+// the open order count in step with order updates.
+func (g *Guard) HandlePrivateMessage(message []byte) {
+	var event struct {
+		Content struct {
+			Data struct {
+				Order []struct {
+					ID     string `json:"id"`
+					Status string `json:"status"`
+				} `json:"order"`
+			} `json:"data"`
+		} `json:"content"`
+	}
+	if err := json.Unmarshal(message, &event); err != nil {
+		return
+	}
+
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	for _, o := range event.Content.Data.Order {
+		if o.ID == "" {
+			continue
+		}
+		if order.IsTerminalOrderStatus(o.Status) {
+			delete(g.openOrders, o.ID)
+		} else {
+			g.openOrders[o.ID] = struct{}{}
+		}
+	}
+}
+
+// HandlePortfolioEvent is a portfolio listener that trips the kill switch as soon as
+// the daily loss limit is breached, when LimitDailyLoss is listed in KillOn.
+func (g *Guard) HandlePortfolioEvent(event portfolio.Event) {
+	if g.limits.MaxDailyLoss.IsZero() {
+		return
+	}
+	g.mu.Lock()
+	if g.killed != nil {
+		g.mu.Unlock()
+		return
+	}
+	loss, ok := g.dailyLoss(event.Snapshot)
+	g.mu.Unlock()
+
+	if ok && loss.GreaterThan(g.limits.MaxDailyLoss) {
+		// Listeners must not block, so cancellation runs in the background
+		go g.maybeTrip(context.Background(), &LimitError{Limit: LimitDailyLoss, Value: loss, Threshold: g.limits.MaxDailyLoss})
+	}
+}
@@ -2,6 +2,7 @@ package internal
 
 import (
 	"bytes"
+	"context"
 	"encoding/hex"
 	"encoding/json"
 	"fmt"
@@ -11,10 +12,12 @@ import (
 	"net/url"
 	"sort"
 	"strings"
+	"sync"
 	"time"
 
 	"golang.org/x/crypto/sha3"
 
+	"github.com/coin-quant/go-edgex/sdk/policy"
 	"github.com/coin-quant/go-edgex/starkcurve"
 )
 
@@ -24,6 +27,9 @@ type Client struct {
 	baseURL     string
 	accountID   int64
 	starkPriKey string
+
+	policyMu       sync.RWMutex
+	outboundPolicy policy.Checker
 }
 
 // ClientConfig holds the configuration for creating a new Client
@@ -31,12 +37,18 @@ type ClientConfig struct {
 	BaseURL     string
 	AccountID   int64
 	StarkPriKey string
+	// HTTPClient is used for all requests when set, so that clients can share a connection pool
+	HTTPClient *http.Client
 }
 
 // NewClient creates a new base client
 func NewClient(cfg *ClientConfig) (*Client, error) {
+	httpClient := cfg.HTTPClient
+	if httpClient == nil {
+		httpClient = &http.Client{Timeout: 30 * time.Second}
+	}
 	return &Client{
-		httpClient:  &http.Client{Timeout: 30 * time.Second},
+		httpClient:  httpClient,
 		baseURL:     cfg.BaseURL,
 		accountID:   cfg.AccountID,
 		starkPriKey: cfg.StarkPriKey,
@@ -58,6 +70,31 @@ func (c *Client) GetBaseURL() string {
 	return c.baseURL
 }
 
+// SetOutboundPolicy installs the checker consulted before withdrawals and transfers
+// are signed. It can only be installed once, so that code holding the client cannot
+// remove or replace it.
+func (c *Client) SetOutboundPolicy(checker policy.Checker) error {
+	c.policyMu.Lock()
+	defer c.policyMu.Unlock()
+	if c.outboundPolicy != nil {
+		return fmt.Errorf("outbound policy already set")
+	}
+	c.outboundPolicy = checker
+	return nil
+}
+
+// CheckOutbound runs the outbound policy, if any, on a request about to be signed
+func (c *Client) CheckOutbound(ctx context.Context, req policy.Request) error {
+	c.policyMu.RLock()
+	checker := c.outboundPolicy
+	c.policyMu.RUnlock()
+	if checker == nil {
+		return nil
+	}
+	req.AccountID = c.accountID
+	return checker.CheckOutbound(ctx, req)
+}
+
 // HttpRequest makes an authenticated HTTP request
 func (c *Client) HttpRequest(urlStr string, method string, data map[string]interface{}, params map[string]string) (*http.Response, error) {
 	// Generate timestamp
@@ -2,6 +2,7 @@ package internal
 
 import (
 	"crypto/sha256"
+	"encoding/hex"
 	"fmt"
 	"math/big"
 	"sort"
@@ -12,6 +13,8 @@ import (
 	"github.com/coin-quant/go-edgex/starkcurve"
 
 	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
+	"golang.org/x/crypto/sha3"
 )
 
 func GenerateUUID() string {
@@ -140,6 +143,87 @@ func CalcTransferHash(assetID, assetIdFee, receiverPublicKey *big.Int, senderPos
 	return msg
 }
 
+// CalcConditionalTransferHash calculates the hash for a conditional transfer
+func CalcConditionalTransferHash(assetID, assetIdFee, receiverPublicKey, condition *big.Int, senderPositionId, receiverPositionId, feePositionId, nonce, amount, maxAmountFee, expirationTimestamp int64) []byte {
+	msg := starkcurve.CalcHash([]*big.Int{assetID, assetIdFee})
+	msgInt := big.NewInt(0).SetBytes(msg)
+	msg = starkcurve.CalcHash([]*big.Int{msgInt, receiverPublicKey})
+	msgInt = big.NewInt(0).SetBytes(msg)
+	msg = starkcurve.CalcHash([]*big.Int{msgInt, condition})
+
+	packedMsg0 := big.NewInt(senderPositionId)
+	packedMsg0 = packedMsg0.Lsh(packedMsg0, 64)
+	packedMsg0 = packedMsg0.Add(packedMsg0, big.NewInt(receiverPositionId))
+	packedMsg0 = packedMsg0.Lsh(packedMsg0, 64)
+	packedMsg0 = packedMsg0.Add(packedMsg0, big.NewInt(feePositionId))
+	packedMsg0 = packedMsg0.Lsh(packedMsg0, 32)
+	packedMsg0 = packedMsg0.Add(packedMsg0, big.NewInt(nonce))
+	msgInt = big.NewInt(0).SetBytes(msg)
+	msg = starkcurve.CalcHash([]*big.Int{msgInt, packedMsg0})
+
+	packedMsg1 := big.NewInt(CondTransferType)
+	packedMsg1 = packedMsg1.Lsh(packedMsg1, 64)
+	packedMsg1 = packedMsg1.Add(packedMsg1, big.NewInt(amount))
+	packedMsg1 = packedMsg1.Lsh(packedMsg1, 64)
+	packedMsg1 = packedMsg1.Add(packedMsg1, big.NewInt(maxAmountFee))
+	packedMsg1 = packedMsg1.Lsh(packedMsg1, 32)
+	packedMsg1 = packedMsg1.Add(packedMsg1, big.NewInt(expirationTimestamp))
+	packedMsg1 = packedMsg1.Lsh(packedMsg1, 81) // CONDITIONAL_TRANSFER_PADDING_BITS = 81
+	msgInt = big.NewInt(0).SetBytes(msg)
+	msg = starkcurve.CalcHash([]*big.Int{msgInt, packedMsg1})
+
+	return msg
+}
+
+// CalcErc20TransferFact calculates the fact of an L1 ERC20 transfer of amount token units
+// to recipient, as registered by the fact registry: keccak256(recipient, amount, token, salt)
+func CalcErc20TransferFact(recipient string, amount *big.Int, tokenAddress string, salt *big.Int) ([]byte, error) {
+	recipientBytes, err := hexAddress(recipient)
+	if err != nil {
+		return nil, fmt.Errorf("invalid recipient address: %w", err)
+	}
+	tokenBytes, err := hexAddress(tokenAddress)
+	if err != nil {
+		return nil, fmt.Errorf("invalid token address: %w", err)
+	}
+
+	hash := sha3.NewLegacyKeccak256()
+	hash.Write(recipientBytes)
+	hash.Write(amount.FillBytes(make([]byte, 32)))
+	hash.Write(tokenBytes)
+	hash.Write(salt.FillBytes(make([]byte, 32)))
+	return hash.Sum(nil), nil
+}
+
+// FactToCondition calculates the condition of a conditional transfer from the fact
+// registry address and the fact: keccak256(registry, fact) masked to 250 bits
+func FactToCondition(factRegistryAddress string, fact []byte) (*big.Int, error) {
+	registryBytes, err := hexAddress(factRegistryAddress)
+	if err != nil {
+		return nil, fmt.Errorf("invalid fact registry address: %w", err)
+	}
+
+	hash := sha3.NewLegacyKeccak256()
+	hash.Write(registryBytes)
+	hash.Write(fact)
+	condition := big.NewInt(0).SetBytes(hash.Sum(nil))
+	mask := big.NewInt(0).Sub(big.NewInt(0).Lsh(big.NewInt(1), 250), big.NewInt(1))
+	return condition.And(condition, mask), nil
+}
+
+// hexAddress decodes a 20 byte hex address with an optional 0x prefix
+func hexAddress(address string) ([]byte, error) {
+	address = strings.TrimPrefix(address, "0x")
+	b, err := hex.DecodeString(address)
+	if err != nil {
+		return nil, err
+	}
+	if len(b) != 20 {
+		return nil, fmt.Errorf("address must be 20 bytes: %s", address)
+	}
+	return b, nil
+}
+
 // CalcWithdrawalHash calculates the hash for a withdrawal
 func CalcWithdrawalHash(assetID, ethAddress, positionId, nonce, amount, expirationTimestamp string) []byte {
 	// Remove ethAddress 0x prefix if exists
@@ -255,3 +339,58 @@ func HexToBigInteger(hex string) (*big.Int, error) {
 	}
 	return result, nil
 }
+
+// CheckStepSize checks that amount is positive and a multiple of stepSize. An empty
+// or zero stepSize is not checked.
+func CheckStepSize(amount decimal.Decimal, stepSize string) error {
+	if !amount.IsPositive() {
+		return fmt.Errorf("amount must be positive: %s", amount)
+	}
+	if stepSize == "" {
+		return nil
+	}
+	step, err := decimal.NewFromString(stepSize)
+	if err != nil {
+		return fmt.Errorf("invalid step size: %s", stepSize)
+	}
+	if step.IsPositive() && !amount.Mod(step).IsZero() {
+		return fmt.Errorf("amount %s is not a multiple of the step size %s", amount, step)
+	}
+	return nil
+}
+
+// QuantizeAmount converts an amount of a coin to StarkEx quantums with the coin's hex
+// resolution. Amounts finer than one quantum are rejected rather than truncated.
+func QuantizeAmount(amount decimal.Decimal, resolution string) (int64, error) {
+	factor, err := HexToBigInteger(resolution)
+	if err != nil || factor.Sign() <= 0 {
+		return 0, fmt.Errorf("invalid starkex resolution: %s", resolution)
+	}
+	quantized := amount.Mul(decimal.NewFromBigInt(factor, 0))
+	if !quantized.IsInteger() {
+		return 0, fmt.Errorf("amount %s is finer than the starkex resolution %s", amount, resolution)
+	}
+	value := quantized.BigInt()
+	if !value.IsInt64() || value.Sign() < 0 {
+		return 0, fmt.Errorf("amount %s out of range", amount)
+	}
+	return value.Int64(), nil
+}
+
+// FirstNonEmpty returns the first non empty value
+func FirstNonEmpty(values ...string) string {
+	for _, v := range values {
+		if v != "" {
+			return v
+		}
+	}
+	return ""
+}
+
+// StringValue dereferences an optional string
+func StringValue(s *string) string {
+	if s == nil {
+		return ""
+	}
+	return *s
+}
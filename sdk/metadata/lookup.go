@@ -0,0 +1,38 @@
+package metadata
+
+import (
+	"fmt"
+	"strings"
+)
+
+// FindCoin returns the coin with the given ID
+func (m *MetaData) FindCoin(coinId string) (*Coin, error) {
+	if m != nil {
+		for i := range m.CoinList {
+			if m.CoinList[i].CoinId == coinId {
+				return &m.CoinList[i], nil
+			}
+		}
+	}
+	return nil, fmt.Errorf("coin not found: %s", coinId)
+}
+
+// FindChainToken returns a chain of the multi chain list and the token of a coin on it
+func (m *MetaData) FindChainToken(chainId string, coin *Coin) (*Chain, *MultiChainToken, error) {
+	if m == nil || m.MultiChain == nil {
+		return nil, nil, fmt.Errorf("metadata multi chain is nil")
+	}
+	for i := range m.MultiChain.ChainList {
+		chain := &m.MultiChain.ChainList[i]
+		if chain.ChainId != chainId {
+			continue
+		}
+		for j := range chain.TokenList {
+			if strings.EqualFold(chain.TokenList[j].Token, coin.CoinName) {
+				return chain, &chain.TokenList[j], nil
+			}
+		}
+		return nil, nil, fmt.Errorf("coin %s not supported on chain: %s", coin.CoinName, chainId)
+	}
+	return nil, nil, fmt.Errorf("chain not found: %s", chainId)
+}
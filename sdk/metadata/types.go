@@ -72,42 +72,60 @@ type Coin struct {
 
 // Contract represents contract metadata
 type Contract struct {
-	ContractId                   string `json:"contractId"`
-	ContractName                 string `json:"contractName"`
-	BaseCoinId                   string `json:"baseCoinId"`
-	QuoteCoinId                  string `json:"quoteCoinId"`
-	TickSize                     string `json:"tickSize"`
-	StepSize                     string `json:"stepSize"`
-	MinOrderSize                 string `json:"minOrderSize"`
-	MaxOrderSize                 string `json:"maxOrderSize"`
-	MaxOrderBuyPriceRatio        string `json:"maxOrderBuyPriceRatio"`
-	MaxOrderSellPriceRatio       string `json:"maxOrderSellPriceRatio"`
-	MaxLongLeverage              string `json:"maxLongLeverage"`
-	MaxShortLeverage             string `json:"maxShortLeverage"`
-	InitialMarginRate            string `json:"initialMarginRate"`
-	MaintenanceMarginRate        string `json:"maintenanceMarginRate"`
-	FundingRateCoefficient       string `json:"fundingRateCoefficient"`
-	FundingRateInterval          int32  `json:"fundingRateInterval"`
-	IsOpenPosition               bool   `json:"isOpenPosition"`
-	IsOpenTpsl                   bool   `json:"isOpenTpsl"`
-	IsOpenConditionalTransfer    bool   `json:"isOpenConditionalTransfer"`
-	IsOpenDeleverage             bool   `json:"isOpenDeleverage"`
-	IsOpenLiquidate              bool   `json:"isOpenLiquidate"`
-	IsOpenAutoDeleverage         bool   `json:"isOpenAutoDeleverage"`
-	IsOpenAutoLiquidate          bool   `json:"isOpenAutoLiquidate"`
-	IsOpenAutoReducePosition     bool   `json:"isOpenAutoReducePosition"`
-	IsOpenAutoReduceMargin       bool   `json:"isOpenAutoReduceMargin"`
-	IsOpenAutoReduceCollateral   bool   `json:"isOpenAutoReduceCollateral"`
-	IsOpenAutoReduceDebt         bool   `json:"isOpenAutoReduceDebt"`
-	IsOpenAutoReduceRisk         bool   `json:"isOpenAutoReduceRisk"`
-	IsOpenAutoReduceExposure     bool   `json:"isOpenAutoReduceExposure"`
-	IsOpenAutoReduceMarginRate   bool   `json:"isOpenAutoReduceMarginRate"`
-	IsOpenAutoReduceDebtRate     bool   `json:"isOpenAutoReduceDebtRate"`
-	IsOpenAutoReduceRiskRate     bool   `json:"isOpenAutoReduceRiskRate"`
-	IsOpenAutoReduceExposureRate bool   `json:"isOpenAutoReduceExposureRate"`
-	StarkExResolution            string `json:"starkExResolution"`
-	StarkExSyntheticAssetId      string `json:"starkExSyntheticAssetId"`
-	DefaultTakerFeeRate          string `json:"defaultTakerFeeRate"`
+	ContractId                   string     `json:"contractId"`
+	ContractName                 string     `json:"contractName"`
+	BaseCoinId                   string     `json:"baseCoinId"`
+	QuoteCoinId                  string     `json:"quoteCoinId"`
+	TickSize                     string     `json:"tickSize"`
+	StepSize                     string     `json:"stepSize"`
+	MinOrderSize                 string     `json:"minOrderSize"`
+	MaxOrderSize                 string     `json:"maxOrderSize"`
+	MaxOrderBuyPriceRatio        string     `json:"maxOrderBuyPriceRatio"`
+	MaxOrderSellPriceRatio       string     `json:"maxOrderSellPriceRatio"`
+	MaxLongLeverage              string     `json:"maxLongLeverage"`
+	MaxShortLeverage             string     `json:"maxShortLeverage"`
+	InitialMarginRate            string     `json:"initialMarginRate"`
+	MaintenanceMarginRate        string     `json:"maintenanceMarginRate"`
+	FundingRateCoefficient       string     `json:"fundingRateCoefficient"`
+	FundingRateInterval          int32      `json:"fundingRateInterval"`
+	IsOpenPosition               bool       `json:"isOpenPosition"`
+	IsOpenTpsl                   bool       `json:"isOpenTpsl"`
+	IsOpenConditionalTransfer    bool       `json:"isOpenConditionalTransfer"`
+	IsOpenDeleverage             bool       `json:"isOpenDeleverage"`
+	IsOpenLiquidate              bool       `json:"isOpenLiquidate"`
+	IsOpenAutoDeleverage         bool       `json:"isOpenAutoDeleverage"`
+	IsOpenAutoLiquidate          bool       `json:"isOpenAutoLiquidate"`
+	IsOpenAutoReducePosition     bool       `json:"isOpenAutoReducePosition"`
+	IsOpenAutoReduceMargin       bool       `json:"isOpenAutoReduceMargin"`
+	IsOpenAutoReduceCollateral   bool       `json:"isOpenAutoReduceCollateral"`
+	IsOpenAutoReduceDebt         bool       `json:"isOpenAutoReduceDebt"`
+	IsOpenAutoReduceRisk         bool       `json:"isOpenAutoReduceRisk"`
+	IsOpenAutoReduceExposure     bool       `json:"isOpenAutoReduceExposure"`
+	IsOpenAutoReduceMarginRate   bool       `json:"isOpenAutoReduceMarginRate"`
+	IsOpenAutoReduceDebtRate     bool       `json:"isOpenAutoReduceDebtRate"`
+	IsOpenAutoReduceRiskRate     bool       `json:"isOpenAutoReduceRiskRate"`
+	IsOpenAutoReduceExposureRate bool       `json:"isOpenAutoReduceExposureRate"`
+	StarkExResolution            string     `json:"starkExResolution"`
+	StarkExSyntheticAssetId      string     `json:"starkExSyntheticAssetId"`
+	DefaultTakerFeeRate          string     `json:"defaultTakerFeeRate"`
+	DefaultMakerFeeRate          string     `json:"defaultMakerFeeRate"`
+	DefaultLeverage              string     `json:"defaultLeverage"`
+	LiquidateFeeRate             string     `json:"liquidateFeeRate"`
+	MaxPositionSize              string     `json:"maxPositionSize"`
+	EnableTrade                  bool       `json:"enableTrade"`
+	EnableOpenPosition           bool       `json:"enableOpenPosition"`
+	RiskTierList                 []RiskTier `json:"riskTierList"`
+}
+
+// RiskTier represents a contract risk tier. Tiers are ordered by PositionValueUpperBound.
+type RiskTier struct {
+	Tier                    int32  `json:"tier"`
+	PositionValueUpperBound string `json:"positionValueUpperBound"`
+	MaxLeverage             string `json:"maxLeverage"`
+	// MaintenanceMarginRate is for display, StarkExRisk / 2^32 is the exact rate
+	MaintenanceMarginRate string `json:"maintenanceMarginRate"`
+	StarkExRisk           string `json:"starkExRisk"`
+	StarkExUpperBound     string `json:"starkExUpperBound"`
 }
 
 // MultiChain represents multi-chain withdrawal information
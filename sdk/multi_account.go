@@ -0,0 +1,313 @@
+package sdk
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/coin-quant/go-edgex/sdk/account"
+	"github.com/coin-quant/go-edgex/sdk/order"
+	"github.com/shopspring/decimal"
+)
+
+// AccountConfig holds the configuration of one account of a MultiAccountClient
+type AccountConfig struct {
+	// Name identifies the account, e.g. the strategy it runs
+	Name        string
+	AccountID   int64
+	StarkPriKey string
+}
+
+// MultiAccountConfig holds the configuration for creating a new MultiAccountClient
+type MultiAccountConfig struct {
+	BaseURL          string
+	Accounts         []AccountConfig
+	MetaDataCacheTTL *time.Duration
+	// HTTPClient is shared by all accounts, a default client is created if nil
+	HTTPClient *http.Client
+}
+
+// MultiAccountClient manages a set of accounts, each with its own signer, sharing
+// one HTTP connection pool and one metadata cache. It is safe for concurrent use.
+type MultiAccountClient struct {
+	baseURL    string
+	httpClient *http.Client
+	cache      *metadataCache
+
+	mu      sync.RWMutex
+	clients map[string]*Client
+}
+
+// NewMultiAccountClient creates a new MultiAccountClient
+func NewMultiAccountClient(cfg *MultiAccountConfig) (*MultiAccountClient, error) {
+	httpClient := cfg.HTTPClient
+	if httpClient == nil {
+		httpClient = &http.Client{Timeout: 30 * time.Second}
+	}
+
+	m := &MultiAccountClient{
+		baseURL:    cfg.BaseURL,
+		httpClient: httpClient,
+		cache:      &metadataCache{ttl: cfg.MetaDataCacheTTL},
+		clients:    make(map[string]*Client, len(cfg.Accounts)),
+	}
+	for _, acc := range cfg.Accounts {
+		if _, err := m.Add(acc); err != nil {
+			return nil, err
+		}
+	}
+	return m, nil
+}
+
+// Add adds an account and returns its client
+func (m *MultiAccountClient) Add(acc AccountConfig) (*Client, error) {
+	if acc.Name == "" {
+		return nil, fmt.Errorf("account name is required")
+	}
+
+	client, err := newClient(&ClientConfig{
+		BaseURL:     m.baseURL,
+		AccountID:   acc.AccountID,
+		StarkPriKey: acc.StarkPriKey,
+		HTTPClient:  m.httpClient,
+	}, m.cache)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create client for account %s: %w", acc.Name, err)
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if _, ok := m.clients[acc.Name]; ok {
+		return nil, fmt.Errorf("account already exists: %s", acc.Name)
+	}
+	m.clients[acc.Name] = client
+	return client, nil
+}
+
+// Remove removes an account
+func (m *MultiAccountClient) Remove(name string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	delete(m.clients, name)
+}
+
+// Account returns the client of a named account
+func (m *MultiAccountClient) Account(name string) (*Client, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	client, ok := m.clients[name]
+	if !ok {
+		return nil, fmt.Errorf("account not found: %s", name)
+	}
+	return client, nil
+}
+
+// Names returns the account names in sorted order
+func (m *MultiAccountClient) Names() []string {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	names := make([]string, 0, len(m.clients))
+	for name := range m.clients {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// CreateOrder routes an order to a named account
+func (m *MultiAccountClient) CreateOrder(ctx context.Context, name string, params *order.CreateOrderParams) (*order.ResultCreateOrder, error) {
+	client, err := m.Account(name)
+	if err != nil {
+		return nil, err
+	}
+	return client.CreateOrder(ctx, params)
+}
+
+// ForEach runs fn concurrently for every account and waits for all of them. The
+// returned error joins the errors of all failed accounts.
+func (m *MultiAccountClient) ForEach(ctx context.Context, fn func(ctx context.Context, name string, client *Client) error) error {
+	m.mu.RLock()
+	clients := make(map[string]*Client, len(m.clients))
+	for name, client := range m.clients {
+		clients[name] = client
+	}
+	m.mu.RUnlock()
+
+	var (
+		wg   sync.WaitGroup
+		mu   sync.Mutex
+		errs []error
+	)
+	for name, client := range clients {
+		wg.Add(1)
+		go func(name string, client *Client) {
+			defer wg.Done()
+			if err := fn(ctx, name, client); err != nil {
+				mu.Lock()
+				errs = append(errs, fmt.Errorf("account %s: %w", name, err))
+				mu.Unlock()
+			}
+		}(name, client)
+	}
+	wg.Wait()
+
+	sort.Slice(errs, func(i, j int) bool { return errs[i].Error() < errs[j].Error() })
+	return errors.Join(errs...)
+}
+
+// GetAccountAssets gets the asset data of every account by name. Accounts that fail
+// are missing from the result and reported in the error.
+func (m *MultiAccountClient) GetAccountAssets(ctx context.Context) (map[string]account.AccountAssetData, error) {
+	var mu sync.Mutex
+	assets := make(map[string]account.AccountAssetData)
+	err := m.ForEach(ctx, func(ctx context.Context, name string, client *Client) error {
+		resp, err := client.GetAccountAssetData(ctx)
+		if err != nil {
+			return err
+		}
+		mu.Lock()
+		assets[name] = resp.Data
+		mu.Unlock()
+		return nil
+	})
+	return assets, err
+}
+
+// AggregatePosition is the combined position of all accounts in one contract
+type AggregatePosition struct {
+	ContractID string
+	Size       decimal.Decimal
+	OpenValue  decimal.Decimal
+	// ByAccount holds the position size of each account with a position
+	ByAccount map[string]decimal.Decimal
+}
+
+// AggregateBalance is the combined collateral of all accounts in one coin
+type AggregateBalance struct {
+	CoinID          string
+	Amount          decimal.Decimal
+	TotalEquity     decimal.Decimal
+	AvailableAmount decimal.Decimal
+	// ByAccount holds the total equity of each account
+	ByAccount map[string]decimal.Decimal
+}
+
+// Aggregate holds positions and balances summed across accounts
+type Aggregate struct {
+	Positions map[string]*AggregatePosition
+	Balances  map[string]*AggregateBalance
+}
+
+// AggregateAssets sums positions and balances across the accounts in assets. An
+// account with a malformed amount is left out of the aggregate and reported in the
+// error.
+func AggregateAssets(assets map[string]account.AccountAssetData) (*Aggregate, error) {
+	agg := &Aggregate{
+		Positions: make(map[string]*AggregatePosition),
+		Balances:  make(map[string]*AggregateBalance),
+	}
+
+	var errs []error
+	for name, data := range assets {
+		if err := agg.add(name, data); err != nil {
+			errs = append(errs, fmt.Errorf("account %s: %w", name, err))
+		}
+	}
+	sort.Slice(errs, func(i, j int) bool { return errs[i].Error() < errs[j].Error() })
+	return agg, errors.Join(errs...)
+}
+
+// add adds the positions and balances of one account. Every amount is parsed before
+// any of them is added, so a malformed account leaves the aggregate untouched.
+func (a *Aggregate) add(name string, data account.AccountAssetData) error {
+	type positionFigures struct {
+		contractID      string
+		size, openValue decimal.Decimal
+	}
+	type balanceFigures struct {
+		coinID                               string
+		amount, totalEquity, availableAmount decimal.Decimal
+		collateral                           bool
+	}
+
+	var firstErr error
+	value := func(d decimal.Decimal, err error) decimal.Decimal {
+		if err != nil && firstErr == nil {
+			firstErr = err
+		}
+		return d
+	}
+	var positions []positionFigures
+	for _, position := range data.PositionList {
+		positions = append(positions, positionFigures{
+			contractID: position.ContractID,
+			size:       value(position.OpenSizeDecimal()),
+			openValue:  value(position.OpenValueDecimal()),
+		})
+	}
+	var balances []balanceFigures
+	for _, collateral := range data.CollateralList {
+		balances = append(balances, balanceFigures{
+			coinID:     collateral.CoinID,
+			amount:     value(collateral.AmountDecimal()),
+			collateral: true,
+		})
+	}
+	for _, asset := range data.CollateralAssetList {
+		balances = append(balances, balanceFigures{
+			coinID:          asset.CoinID,
+			totalEquity:     value(asset.TotalEquityDecimal()),
+			availableAmount: value(asset.AvailableAmountDecimal()),
+		})
+	}
+	if firstErr != nil {
+		return firstErr
+	}
+
+	for _, position := range positions {
+		if position.size.IsZero() {
+			continue
+		}
+		p, ok := a.Positions[position.contractID]
+		if !ok {
+			p = &AggregatePosition{ContractID: position.contractID, ByAccount: make(map[string]decimal.Decimal)}
+			a.Positions[position.contractID] = p
+		}
+		p.Size = p.Size.Add(position.size)
+		p.OpenValue = p.OpenValue.Add(position.openValue)
+		p.ByAccount[name] = p.ByAccount[name].Add(position.size)
+	}
+	for _, balance := range balances {
+		b := a.balance(balance.coinID)
+		if balance.collateral {
+			b.Amount = b.Amount.Add(balance.amount)
+			continue
+		}
+		b.TotalEquity = b.TotalEquity.Add(balance.totalEquity)
+		b.AvailableAmount = b.AvailableAmount.Add(balance.availableAmount)
+		b.ByAccount[name] = b.ByAccount[name].Add(balance.totalEquity)
+	}
+	return nil
+}
+
+// balance returns the balance of a coin, creating it if needed
+func (a *Aggregate) balance(coinID string) *AggregateBalance {
+	b, ok := a.Balances[coinID]
+	if !ok {
+		b = &AggregateBalance{CoinID: coinID, ByAccount: make(map[string]decimal.Decimal)}
+		a.Balances[coinID] = b
+	}
+	return b
+}
+
+// GetAggregate gets the assets of every account and sums positions and balances.
+// On partial failure the aggregate covers the accounts that succeeded.
+func (m *MultiAccountClient) GetAggregate(ctx context.Context) (*Aggregate, error) {
+	assets, err := m.GetAccountAssets(ctx)
+	agg, aggErr := AggregateAssets(assets)
+	return agg, errors.Join(err, aggErr)
+}
@@ -0,0 +1,119 @@
+package onboard
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+
+	"github.com/coin-quant/go-edgex/sdk/internal"
+)
+
+// Client represents the onboarding client
+type Client struct {
+	*internal.Client
+}
+
+// NewClient creates a new onboarding client
+func NewClient(client *internal.Client) *Client {
+	return &Client{
+		Client: client,
+	}
+}
+
+// CheckUserExist checks whether a user is registered for an Ethereum address
+func (c *Client) CheckUserExist(ctx context.Context, ethAddress string) (*ResultCheckUserExist, error) {
+	url := fmt.Sprintf("%s/api/v1/public/user/checkUserExist", c.Client.GetBaseURL())
+	params := map[string]string{
+		"ethAddress": ethAddress,
+	}
+
+	resp, err := c.Client.HttpRequest(url, "GET", nil, params)
+	if err != nil {
+		return nil, fmt.Errorf("failed to check user exist: %w", err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read response body: %w", err)
+	}
+
+	var result ResultCheckUserExist
+	if err := json.Unmarshal(body, &result); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal response: %w", err)
+	}
+
+	if result.Code != "SUCCESS" {
+		return nil, fmt.Errorf("request failed with code: %s", result.Code)
+	}
+
+	return &result, nil
+}
+
+// OnboardSite logs a user in with a signed message, registering the user and its
+// default account on the first login
+func (c *Client) OnboardSite(ctx context.Context, params *OnboardSiteParams) (*ResultOnboardSite, error) {
+	url := fmt.Sprintf("%s/api/v1/public/user/onboardSite", c.Client.GetBaseURL())
+	data := map[string]interface{}{
+		"ethAddress":       params.EthAddress,
+		"onlySignOn":       params.OnlySignOn,
+		"param":            params.Param,
+		"signature":        params.Signature,
+		"l2Key":            params.L2Key,
+		"l2KeyYCoordinate": params.L2KeyYCoordinate,
+		"clientAccountId":  params.ClientAccountId,
+	}
+
+	resp, err := c.Client.HttpRequest(url, "POST", data, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to onboard site: %w", err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read response body: %w", err)
+	}
+
+	var result ResultOnboardSite
+	if err := json.Unmarshal(body, &result); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal response: %w", err)
+	}
+
+	if result.Code != "SUCCESS" {
+		return nil, fmt.Errorf("request failed with code: %s, errorParam: %v", result.Code, result.ErrorParam)
+	}
+
+	return &result, nil
+}
+
+// CreateApiCredential derives the API credentials of the user from a signature
+func (c *Client) CreateApiCredential(ctx context.Context, signature string) (*ResultApiCredential, error) {
+	url := fmt.Sprintf("%s/api/createApiCredential", c.Client.GetBaseURL())
+	params := map[string]string{
+		"signature": signature,
+	}
+
+	resp, err := c.Client.HttpRequest(url, "GET", nil, params)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create api credential: %w", err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read response body: %w", err)
+	}
+
+	var result ResultApiCredential
+	if err := json.Unmarshal(body, &result); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal response: %w", err)
+	}
+
+	if result.Code != "SUCCESS" {
+		return nil, fmt.Errorf("request failed with code: %s", result.Code)
+	}
+
+	return &result, nil
+}
@@ -0,0 +1,244 @@
+// Package onboard registers users and accounts. Given an Ethereum signer it derives or
+// takes the stark key of an account, logs the user in, registers the account, obtains
+// API credentials and returns a client for the account.
+package onboard
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/coin-quant/go-edgex/sdk"
+	"github.com/coin-quant/go-edgex/sdk/account"
+	"github.com/coin-quant/go-edgex/sdk/internal"
+	"github.com/coin-quant/go-edgex/starkkey"
+)
+
+const (
+	// DefaultOnlySignOn is the site the signed messages are bound to
+	DefaultOnlySignOn = "https://pro.edgex.exchange"
+	// DefaultClientAccountID identifies the default account of a user
+	DefaultClientAccountID = "main"
+	// accountPageSize is the page size used to look up accounts
+	accountPageSize = 100
+)
+
+// EthSigner signs messages with the key of an Ethereum address
+type EthSigner interface {
+	// Address returns the 0x prefixed Ethereum address
+	Address() string
+	// SignMessage signs a message as personal_sign (EIP-191) does and returns the
+	// hex r, s and v signature
+	SignMessage(ctx context.Context, message []byte) (string, error)
+}
+
+// Config holds the configuration for creating a new Onboarder
+type Config struct {
+	BaseURL string
+	Signer  EthSigner
+	// OnlySignOn defaults to DefaultOnlySignOn
+	OnlySignOn string
+	// HTTPClient and MetaDataCacheTTL are used by the returned clients
+	HTTPClient       *http.Client
+	MetaDataCacheTTL *time.Duration
+}
+
+// Params represents the account to onboard
+type Params struct {
+	// ClientAccountID defaults to DefaultClientAccountID
+	ClientAccountID string
+	// StarkKey is derived from a signature of L2KeyMessage when nil
+	StarkKey *starkkey.KeyPair
+	// Registrar is the account client of an existing account of the user. It is
+	// required to register accounts besides the default one of a new user.
+	Registrar *account.Client
+}
+
+// Result holds the onboarded account
+type Result struct {
+	Client    *sdk.Client
+	AccountID int64
+	StarkKey  *starkkey.KeyPair
+	// Credential holds the API credentials of the user
+	Credential *ApiCredential
+	// IsNewUser is true if the user was registered by this onboarding
+	IsNewUser bool
+}
+
+// Onboarder registers users and accounts
+type Onboarder struct {
+	cfg Config
+}
+
+// New creates a new Onboarder
+func New(cfg Config) (*Onboarder, error) {
+	if cfg.Signer == nil {
+		return nil, fmt.Errorf("eth signer is required")
+	}
+	if cfg.OnlySignOn == "" {
+		cfg.OnlySignOn = DefaultOnlySignOn
+	}
+	return &Onboarder{cfg: cfg}, nil
+}
+
+// L2KeyMessage returns the message signed to derive the stark key of an account
+func L2KeyMessage(onlySignOn, clientAccountID string) string {
+	return fmt.Sprintf("name: edgeX\naction: L2 Key\nonlySignOn: %s\nclientAccountId: %s", onlySignOn, clientAccountID)
+}
+
+// OnboardMessage returns the message signed to log in
+func OnboardMessage(onlySignOn string) string {
+	return fmt.Sprintf("action: edgeX Onboard\nonlySignOn: %s", onlySignOn)
+}
+
+// ApiCredentialMessage returns the message signed to derive the API credentials
+func ApiCredentialMessage(onlySignOn string) string {
+	return fmt.Sprintf("action: edgeX Generate API Key\nonlySignOn: %s", onlySignOn)
+}
+
+// DeriveStarkKey derives the stark key of an account from a signature of its
+// L2KeyMessage. The signature is deterministic, so the key can be derived again at
+// any time.
+func (o *Onboarder) DeriveStarkKey(ctx context.Context, clientAccountID string) (*starkkey.KeyPair, error) {
+	signature, err := o.cfg.Signer.SignMessage(ctx, []byte(L2KeyMessage(o.cfg.OnlySignOn, clientAccountID)))
+	if err != nil {
+		return nil, fmt.Errorf("failed to sign l2 key message: %w", err)
+	}
+	return starkkey.FromEthSignature(signature)
+}
+
+// Onboard registers the user on its first login with the account as its default
+// account, or registers the account with the Registrar for an existing user. It is
+// idempotent: an account already registered with the stark key is reused.
+func (o *Onboarder) Onboard(ctx context.Context, params Params) (*Result, error) {
+	clientAccountID := params.ClientAccountID
+	if clientAccountID == "" {
+		clientAccountID = DefaultClientAccountID
+	}
+	keyPair := params.StarkKey
+	if keyPair == nil {
+		var err error
+		if keyPair, err = o.DeriveStarkKey(ctx, clientAccountID); err != nil {
+			return nil, err
+		}
+	}
+
+	internalClient, err := internal.NewClient(&internal.ClientConfig{
+		BaseURL:     o.cfg.BaseURL,
+		StarkPriKey: keyPair.PrivateKeyHex(),
+		HTTPClient:  o.cfg.HTTPClient,
+	})
+	if err != nil {
+		return nil, err
+	}
+	client := NewClient(internalClient)
+	ethAddress := o.cfg.Signer.Address()
+
+	exist, err := client.CheckUserExist(ctx, ethAddress)
+	if err != nil {
+		return nil, err
+	}
+	result := &Result{StarkKey: keyPair}
+	if exist.Data == nil || !exist.Data.IsUserExist {
+		if err := o.onboardSite(ctx, client, keyPair, clientAccountID); err != nil {
+			return nil, err
+		}
+		result.IsNewUser = true
+	}
+
+	accounts := account.NewClient(internalClient)
+	if params.Registrar != nil {
+		accounts = params.Registrar
+	}
+	accountID, err := findAccount(ctx, accounts, keyPair)
+	if err != nil {
+		return nil, err
+	}
+	if accountID == "" {
+		if params.Registrar == nil {
+			return nil, fmt.Errorf("no account with l2 key %s, a registrar is required to register it", keyPair.PublicKeyHex())
+		}
+		resp, err := params.Registrar.RegisterAccount(ctx, account.RegisterAccountParams{
+			L2Key:            keyPair.PublicKeyHex(),
+			L2KeyYCoordinate: keyPair.PublicKeyYHex(),
+			ClientAccountID:  clientAccountID,
+		})
+		if err != nil {
+			return nil, err
+		}
+		if resp.Data == nil || resp.Data.AccountID == "" {
+			return nil, fmt.Errorf("registered account id is empty")
+		}
+		accountID = resp.Data.AccountID
+	}
+	if result.AccountID, err = strconv.ParseInt(accountID, 10, 64); err != nil {
+		return nil, fmt.Errorf("invalid account id %s: %w", accountID, err)
+	}
+
+	signature, err := o.cfg.Signer.SignMessage(ctx, []byte(ApiCredentialMessage(o.cfg.OnlySignOn)))
+	if err != nil {
+		return nil, fmt.Errorf("failed to sign api credential message: %w", err)
+	}
+	credential, err := client.CreateApiCredential(ctx, signature)
+	if err != nil {
+		return nil, err
+	}
+	result.Credential = credential.Data
+
+	result.Client, err = sdk.NewClient(&sdk.ClientConfig{
+		BaseURL:          o.cfg.BaseURL,
+		AccountID:        result.AccountID,
+		StarkPriKey:      keyPair.PrivateKeyHex(),
+		MetaDataCacheTTL: o.cfg.MetaDataCacheTTL,
+		HTTPClient:       o.cfg.HTTPClient,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// onboardSite logs a new user in, creating its default account with the stark key
+func (o *Onboarder) onboardSite(ctx context.Context, client *Client, keyPair *starkkey.KeyPair, clientAccountID string) error {
+	message := OnboardMessage(o.cfg.OnlySignOn)
+	signature, err := o.cfg.Signer.SignMessage(ctx, []byte(message))
+	if err != nil {
+		return fmt.Errorf("failed to sign onboard message: %w", err)
+	}
+	_, err = client.OnboardSite(ctx, &OnboardSiteParams{
+		EthAddress:       o.cfg.Signer.Address(),
+		OnlySignOn:       o.cfg.OnlySignOn,
+		Param:            message,
+		Signature:        signature,
+		L2Key:            keyPair.PublicKeyHex(),
+		L2KeyYCoordinate: keyPair.PublicKeyYHex(),
+		ClientAccountId:  clientAccountID,
+	})
+	return err
+}
+
+// findAccount returns the id of the account of the user with the stark key, or an
+// empty string if there is none
+func findAccount(ctx context.Context, client *account.Client, keyPair *starkkey.KeyPair) (string, error) {
+	var offsetData string
+	for {
+		resp, err := client.GetAccountPage(ctx, account.GetAccountPageParams{Size: accountPageSize, OffsetData: offsetData})
+		if err != nil {
+			return "", err
+		}
+		if resp.Data == nil {
+			return "", nil
+		}
+		for _, acc := range resp.Data.DataList {
+			if keyPair.Validate(acc.L2Key, "") == nil {
+				return acc.ID, nil
+			}
+		}
+		if resp.Data.NextPageOffsetData == nil || *resp.Data.NextPageOffsetData == "" {
+			return "", nil
+		}
+		offsetData = *resp.Data.NextPageOffsetData
+	}
+}
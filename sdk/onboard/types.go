@@ -0,0 +1,73 @@
+package onboard
+
+// User represents the information of a user
+type User struct {
+	Id              string `json:"id"`
+	EthAddress      string `json:"ethAddress"`
+	Nickname        string `json:"nickname"`
+	Email           string `json:"email"`
+	IsEmailVerified bool   `json:"isEmailVerified"`
+	Country         string `json:"country"`
+	Language        string `json:"language"`
+	AvatarUrl       string `json:"avatarUrl"`
+	AvatarBorderUrl string `json:"avatarBorderUrl"`
+	CreatedTime     string `json:"createdTime"`
+	UpdatedTime     string `json:"updatedTime"`
+}
+
+// OnboardSite represents the result of a user login
+type OnboardSite struct {
+	User *User `json:"user"`
+	// IsNewUser is true if the user registered with this login
+	IsNewUser bool `json:"isNewUser"`
+}
+
+// CheckUserExist represents whether a user exists
+type CheckUserExist struct {
+	IsUserExist bool `json:"isUserExist"`
+}
+
+// ApiCredential represents the credentials for calling the API
+type ApiCredential struct {
+	ApiKey     string `json:"apiKey"`
+	Secret     string `json:"secret"`
+	Passphrase string `json:"passphrase"`
+}
+
+// ResultOnboardSite represents the response for OnboardSite
+type ResultOnboardSite struct {
+	Code       string       `json:"code"`
+	Data       *OnboardSite `json:"data"`
+	ErrorParam interface{}  `json:"errorParam"`
+	ErrorMsg   string       `json:"msg"`
+}
+
+// ResultCheckUserExist represents the response for CheckUserExist
+type ResultCheckUserExist struct {
+	Code       string          `json:"code"`
+	Data       *CheckUserExist `json:"data"`
+	ErrorParam interface{}     `json:"errorParam"`
+	ErrorMsg   string          `json:"msg"`
+}
+
+// ResultApiCredential represents the response for CreateApiCredential
+type ResultApiCredential struct {
+	Code       string         `json:"code"`
+	Data       *ApiCredential `json:"data"`
+	ErrorParam interface{}    `json:"errorParam"`
+	ErrorMsg   string         `json:"msg"`
+}
+
+// OnboardSiteParams represents the parameters for OnboardSite
+type OnboardSiteParams struct {
+	EthAddress string
+	OnlySignOn string
+	// Param is the signed message
+	Param     string
+	Signature string
+	// L2Key, L2KeyYCoordinate and ClientAccountId create the default account of a
+	// new user
+	L2Key            string
+	L2KeyYCoordinate string
+	ClientAccountId  string
+}
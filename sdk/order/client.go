@@ -53,18 +53,9 @@ func (c *Client) CreateOrder(ctx context.Context, params *CreateOrderParams, met
 		return nil, fmt.Errorf("contract not found: %s", params.ContractId)
 	}
 
-	var quoteCoin *metadatapkg.Coin
-	if metadata != nil && metadata.CoinList != nil {
-		for i := range metadata.CoinList {
-			if metadata.CoinList[i].CoinId == contract.QuoteCoinId {
-				quoteCoin = &metadata.CoinList[i]
-				break
-			}
-		}
-	}
-
-	if quoteCoin == nil {
-		return nil, fmt.Errorf("coin not found: %s", contract.QuoteCoinId)
+	quoteCoin, err := metadata.FindCoin(contract.QuoteCoinId)
+	if err != nil {
+		return nil, err
 	}
 
 	syntheticFactorBig, err := internal.HexToBigInteger(contract.StarkExResolution)
@@ -194,7 +185,7 @@ func (c *Client) CancelOrder(ctx context.Context, params *CancelOrderParams) (in
 			"clientOrderIdList": []string{params.ClientId},
 		}
 	} else if params.ContractId != "" {
-		url = "/api/v1/private/order/cancelAllOrder"
+		url = fmt.Sprintf("%s/api/v1/private/order/cancelAllOrder", c.Client.GetBaseURL())
 		body = map[string]interface{}{
 			"accountId":            accountID,
 			"filterContractIdList": []string{params.ContractId},
@@ -226,6 +217,39 @@ func (c *Client) CancelOrder(ctx context.Context, params *CancelOrderParams) (in
 	return result, nil
 }
 
+// CancelAllOrders cancels all active orders, or only those of contractIDs when given
+func (c *Client) CancelAllOrders(ctx context.Context, contractIDs []string) (interface{}, error) {
+	url := fmt.Sprintf("%s/api/v1/private/order/cancelAllOrder", c.Client.GetBaseURL())
+	body := map[string]interface{}{
+		"accountId": strconv.FormatInt(c.Client.GetAccountID(), 10),
+	}
+	if len(contractIDs) > 0 {
+		body["filterContractIdList"] = contractIDs
+	}
+
+	resp, err := c.Client.HttpRequest(url, "POST", body, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to cancel all orders: %w", err)
+	}
+	defer resp.Body.Close()
+
+	respBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read response body: %w", err)
+	}
+
+	var result map[string]interface{}
+	if err := json.Unmarshal(respBody, &result); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal response: %w", err)
+	}
+
+	if code, ok := result["code"].(string); ok && code != "SUCCESS" {
+		return nil, fmt.Errorf("request failed with code: %s", code)
+	}
+
+	return result, nil
+}
+
 // GetActiveOrders gets active orders with pagination and filters
 func (c *Client) GetActiveOrders(ctx context.Context, params *GetActiveOrderParams) (*ResultPageDataOrder, error) {
 	url := fmt.Sprintf("%s/api/v1/private/order/getActiveOrderPage", c.Client.GetBaseURL())
@@ -19,6 +19,29 @@ const (
 	OrderSideSell = "SELL"
 )
 
+// Order status constants. Rejected and expired orders end as CANCELED with a cancel reason.
+const (
+	OrderStatusUnknown     = "UNKNOWN_ORDER_STATUS"
+	OrderStatusPending     = "PENDING"
+	OrderStatusOpen        = "OPEN"
+	OrderStatusFilled      = "FILLED"
+	OrderStatusCanceling   = "CANCELING"
+	OrderStatusCanceled    = "CANCELED"
+	OrderStatusUntriggered = "UNTRIGGERED"
+)
+
+// IsTerminalOrderStatus reports whether an order will not change anymore. Only
+// pending, open, canceling and untriggered conditional orders are still live;
+// unknown statuses are treated as terminal.
+func IsTerminalOrderStatus(status string) bool {
+	switch status {
+	case OrderStatusPending, OrderStatusOpen, OrderStatusCanceling, OrderStatusUntriggered:
+		return false
+	default:
+		return true
+	}
+}
+
 // Response code constants
 const (
 	ResponseCodeSuccess = "SUCCESS"
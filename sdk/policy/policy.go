@@ -0,0 +1,287 @@
+// Package policy approves outbound money movements before they are signed. It
+// enforces destination allowlists and per-coin daily limits, runs an optional
+// approval callback and writes an audit entry for every request.
+//
+// A policy installed with SetOutboundPolicy cannot be removed or replaced through the
+// client. It does not protect against code that reads the Stark private key itself;
+// keep the key out of processes that must not be able to move funds.
+package policy
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+// Kind identifies an outbound money movement
+type Kind string
+
+const (
+	// KindNormalWithdraw is a withdrawal through the StarkEx contract
+	KindNormalWithdraw Kind = "NORMAL_WITHDRAW"
+	// KindFastWithdraw is a withdrawal paid out on L1 by a liquidity provider
+	KindFastWithdraw Kind = "FAST_WITHDRAW"
+	// KindCrossWithdraw is a withdrawal paid out on another chain by a liquidity provider
+	KindCrossWithdraw Kind = "CROSS_WITHDRAW"
+	// KindTransferOut is an L2 transfer to another account
+	KindTransferOut Kind = "TRANSFER_OUT"
+)
+
+// Rule identifies the policy rule that denied a request
+type Rule string
+
+const (
+	// RuleAddress denies withdrawals to addresses missing from the allowlist
+	RuleAddress Rule = "ADDRESS_ALLOWLIST"
+	// RuleReceiver denies transfers to accounts missing from the allowlist
+	RuleReceiver Rule = "RECEIVER_ALLOWLIST"
+	// RuleDailyLimit denies requests that would exceed the daily limit of a coin
+	RuleDailyLimit Rule = "DAILY_LIMIT"
+	// RuleApproval denies requests rejected by the approval callback
+	RuleApproval Rule = "APPROVAL"
+)
+
+// ErrDenied is wrapped by every DeniedError
+var ErrDenied = errors.New("outbound request denied by policy")
+
+// DeniedError is returned when a request breaks a rule
+type DeniedError struct {
+	Rule   Rule
+	Reason string
+}
+
+func (e *DeniedError) Error() string {
+	return fmt.Sprintf("%v: %s: %s", ErrDenied, e.Rule, e.Reason)
+}
+
+// Unwrap returns ErrDenied
+func (e *DeniedError) Unwrap() error {
+	return ErrDenied
+}
+
+// Request describes an outbound money movement about to be signed
+type Request struct {
+	Kind      Kind
+	AccountID int64
+	CoinID    string
+	Amount    decimal.Decimal
+	// EthAddress is the destination of withdrawals
+	EthAddress string
+	// ChainID is the destination chain of fast and cross withdrawals
+	ChainID string
+	// ReceiverAccountID is the destination of transfers
+	ReceiverAccountID string
+	ClientID          string
+}
+
+// Checker approves or denies outbound requests. The signing code of the asset and
+// transfer clients consults it before producing a Stark signature.
+type Checker interface {
+	CheckOutbound(ctx context.Context, req Request) error
+}
+
+// Approver is called for requests that passed the allowlists and limits. It returns
+// nil to approve, for example once a second operator confirmed the request. It may
+// block until ctx is done.
+type Approver func(ctx context.Context, req Request) error
+
+// AuditEntry records the decision taken on a request
+type AuditEntry struct {
+	Time     time.Time `json:"time"`
+	Request  Request   `json:"request"`
+	Approved bool      `json:"approved"`
+	Rule     Rule      `json:"rule,omitempty"`
+	Reason   string    `json:"reason,omitempty"`
+}
+
+// AuditLog receives an entry for every request
+type AuditLog interface {
+	Write(entry AuditEntry) error
+}
+
+// jsonAuditLog writes entries as JSON lines
+type jsonAuditLog struct {
+	mu sync.Mutex
+	w  io.Writer
+}
+
+// NewJSONAuditLog returns an AuditLog writing one JSON object per line to w
+func NewJSONAuditLog(w io.Writer) AuditLog {
+	return &jsonAuditLog{w: w}
+}
+
+func (l *jsonAuditLog) Write(entry AuditEntry) error {
+	line, err := json.Marshal(entry)
+	if err != nil {
+		return fmt.Errorf("failed to marshal audit entry: %w", err)
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	_, err = l.w.Write(append(line, '\n'))
+	return err
+}
+
+// Config holds the rules of a Policy. The allowlists fail closed: with an empty
+// AllowedAddresses no withdrawal is allowed, with an empty AllowedReceivers no
+// transfer is.
+type Config struct {
+	// AllowedAddresses lists the ETH addresses withdrawals may go to, case insensitive
+	AllowedAddresses []string
+	// AllowedReceivers lists the account IDs transfers may go to
+	AllowedReceivers []string
+	// DailyLimits caps the amount leaving per coin ID and UTC day. Coins missing
+	// from the map are not limited.
+	DailyLimits map[string]decimal.Decimal
+	// Approver is optional and runs last
+	Approver Approver
+	// Audit is required
+	Audit AuditLog
+}
+
+// Policy checks outbound requests against a Config. It is safe for concurrent use.
+type Policy struct {
+	addresses map[string]struct{}
+	receivers map[string]struct{}
+	limits    map[string]decimal.Decimal
+	approver  Approver
+	audit     AuditLog
+	now       func() time.Time
+
+	mu   sync.Mutex
+	day  time.Time
+	used map[string]decimal.Decimal
+}
+
+// New creates a new Policy
+func New(cfg Config) (*Policy, error) {
+	if cfg.Audit == nil {
+		return nil, fmt.Errorf("policy audit log is required")
+	}
+	p := &Policy{
+		addresses: make(map[string]struct{}),
+		receivers: make(map[string]struct{}),
+		limits:    make(map[string]decimal.Decimal),
+		approver:  cfg.Approver,
+		audit:     cfg.Audit,
+		now:       time.Now,
+		used:      make(map[string]decimal.Decimal),
+	}
+	for _, address := range cfg.AllowedAddresses {
+		p.addresses[strings.ToLower(address)] = struct{}{}
+	}
+	for _, receiver := range cfg.AllowedReceivers {
+		p.receivers[receiver] = struct{}{}
+	}
+	for coinID, limit := range cfg.DailyLimits {
+		p.limits[coinID] = limit
+	}
+	return p, nil
+}
+
+// SetClock replaces the time source, for tests
+func (p *Policy) SetClock(now func() time.Time) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.now = now
+}
+
+// CheckOutbound checks a request against the allowlists and the daily limit of its
+// coin, then runs the approver. The amount of an approved request counts toward the
+// daily limit whether or not the exchange accepts it. Every decision is audited; if
+// the audit entry cannot be written the request is denied.
+func (p *Policy) CheckOutbound(ctx context.Context, req Request) error {
+	denied := p.reserve(req)
+	if denied == nil && p.approver != nil {
+		if err := p.approver(ctx, req); err != nil {
+			p.release(req)
+			denied = &DeniedError{Rule: RuleApproval, Reason: err.Error()}
+		}
+	}
+
+	entry := AuditEntry{Time: p.clock(), Request: req, Approved: denied == nil}
+	if denied != nil {
+		entry.Rule = denied.Rule
+		entry.Reason = denied.Reason
+	}
+	if err := p.audit.Write(entry); err != nil {
+		if denied == nil {
+			p.release(req)
+		}
+		return fmt.Errorf("failed to write audit entry: %w", err)
+	}
+	if denied != nil {
+		return denied
+	}
+	return nil
+}
+
+// Used returns the amount of a coin approved since the start of the UTC day
+func (p *Policy) Used(coinID string) decimal.Decimal {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.rollDay()
+	return p.used[coinID]
+}
+
+// reserve checks the allowlists and limit and counts the amount toward the limit
+func (p *Policy) reserve(req Request) *DeniedError {
+	switch req.Kind {
+	case KindTransferOut:
+		if _, ok := p.receivers[req.ReceiverAccountID]; !ok {
+			return &DeniedError{Rule: RuleReceiver, Reason: fmt.Sprintf("receiver account %s not allowed", req.ReceiverAccountID)}
+		}
+	default:
+		if _, ok := p.addresses[strings.ToLower(req.EthAddress)]; !ok {
+			return &DeniedError{Rule: RuleAddress, Reason: fmt.Sprintf("address %s not allowed", req.EthAddress)}
+		}
+	}
+	if !req.Amount.IsPositive() {
+		return &DeniedError{Rule: RuleDailyLimit, Reason: fmt.Sprintf("invalid amount %s", req.Amount)}
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.rollDay()
+	used := p.used[req.CoinID].Add(req.Amount)
+	if limit, ok := p.limits[req.CoinID]; ok && used.GreaterThan(limit) {
+		return &DeniedError{Rule: RuleDailyLimit, Reason: fmt.Sprintf("coin %s daily amount %s exceeds %s", req.CoinID, used, limit)}
+	}
+	p.used[req.CoinID] = used
+	return nil
+}
+
+// release takes the amount of a denied request off the daily usage
+func (p *Policy) release(req Request) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.rollDay()
+	used := p.used[req.CoinID].Sub(req.Amount)
+	if used.IsNegative() {
+		used = decimal.Zero
+	}
+	p.used[req.CoinID] = used
+}
+
+// rollDay resets the usage at the start of a UTC day. The caller must hold mu.
+func (p *Policy) rollDay() {
+	now := p.now().UTC()
+	day := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+	if !day.Equal(p.day) {
+		p.day = day
+		p.used = make(map[string]decimal.Decimal)
+	}
+}
+
+// clock returns the current time
+func (p *Policy) clock() time.Time {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.now()
+}
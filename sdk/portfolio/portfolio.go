@@ -0,0 +1,464 @@
+// Package portfolio maintains live PnL, funding, fees, equity and exposure from
+// account snapshots, the private account stream and public ticker prices.
+package portfolio
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/coin-quant/go-edgex/sdk/account"
+	"github.com/coin-quant/go-edgex/sdk/quote"
+	"github.com/coin-quant/go-edgex/sdk/ws"
+	"github.com/shopspring/decimal"
+)
+
+// DefaultPrivateMessageType is the private WebSocket message type carrying account events
+const DefaultPrivateMessageType = "trade-event"
+
+// PriceSource selects the ticker price positions are valued at
+type PriceSource int
+
+const (
+	// PriceOracle values positions at the oracle price, as the exchange does for margin
+	PriceOracle PriceSource = iota
+	// PriceLast values positions at the last traded price
+	PriceLast
+	// PriceIndex values positions at the index price
+	PriceIndex
+)
+
+// EventType identifies what changed in the portfolio
+type EventType string
+
+const (
+	// EventLoaded follows Load
+	EventLoaded EventType = "LOADED"
+	// EventPrice follows a valuation price update
+	EventPrice EventType = "PRICE"
+	// EventFill follows a new position transaction
+	EventFill EventType = "FILL"
+	// EventPosition follows a position update
+	EventPosition EventType = "POSITION"
+	// EventCollateral follows a collateral update
+	EventCollateral EventType = "COLLATERAL"
+)
+
+// snapshotEvent is the private event sent on connect with the current account state
+const snapshotEvent = "Snapshot"
+
+// Event is emitted after every change with a snapshot taken right after it
+type Event struct {
+	Type       EventType
+	ContractID string
+	Snapshot   Snapshot
+}
+
+// ContractPnL holds the figures of one contract. Fees and funding use the exchange
+// sign convention: negative values are paid, positive values are received.
+type ContractPnL struct {
+	ContractID    string
+	Size          decimal.Decimal
+	OpenValue     decimal.Decimal
+	Price         decimal.Decimal
+	Exposure      decimal.Decimal
+	UnrealizedPnl decimal.Decimal
+	RealizedPnl   decimal.Decimal
+	Funding       decimal.Decimal
+	Fees          decimal.Decimal
+}
+
+// Snapshot holds the portfolio figures at a point in time
+type Snapshot struct {
+	Time          time.Time
+	Collateral    decimal.Decimal
+	Equity        decimal.Decimal
+	UnrealizedPnl decimal.Decimal
+	RealizedPnl   decimal.Decimal
+	Funding       decimal.Decimal
+	Fees          decimal.Decimal
+	GrossExposure decimal.Decimal
+	NetExposure   decimal.Decimal
+	Contracts     map[string]ContractPnL
+	// MissingPrices lists contracts with an open position but no price yet. Their
+	// value is excluded from equity and exposure.
+	MissingPrices []string
+}
+
+// Config holds the configuration for creating a new Portfolio
+type Config struct {
+	// CoinID is the collateral coin to track
+	CoinID string
+	// PriceSource selects the ticker price used for valuation
+	PriceSource PriceSource
+	// PrivateMessageType is the private message type to handle, DefaultPrivateMessageType if empty
+	PrivateMessageType string
+}
+
+// contractState is the tracked state of one contract
+type contractState struct {
+	position positionFigures
+	realized decimal.Decimal
+}
+
+// positionFigures are the amounts of a position the portfolio is computed from
+type positionFigures struct {
+	size      decimal.Decimal
+	openValue decimal.Decimal
+	funding   decimal.Decimal
+	fees      decimal.Decimal
+}
+
+// figuresOf reads the amounts of a position
+func figuresOf(position account.Position) (positionFigures, error) {
+	var f positionFigures
+	var firstErr error
+	value := func(d decimal.Decimal, err error) decimal.Decimal {
+		if err != nil && firstErr == nil {
+			firstErr = fmt.Errorf("position %s: %w", position.ContractID, err)
+		}
+		return d
+	}
+	f.size = value(position.OpenSizeDecimal())
+	f.openValue = value(position.OpenValueDecimal())
+	f.funding = value(position.LongTotalStat.CumFundingFeeDecimal()).Add(value(position.ShortTotalStat.CumFundingFeeDecimal()))
+	for _, stat := range []account.PositionStat{position.LongTotalStat, position.ShortTotalStat} {
+		f.fees = f.fees.Add(value(stat.CumOpenFeeDecimal())).Add(value(stat.CumCloseFeeDecimal())).Add(value(stat.CumLiquidateFeeDecimal()))
+	}
+	return f, firstErr
+}
+
+// Portfolio tracks live account figures. It is safe for concurrent use.
+type Portfolio struct {
+	cfg        Config
+	mu         sync.RWMutex
+	collateral decimal.Decimal
+	contracts  map[string]*contractState
+	prices     map[string]decimal.Decimal
+	seenTx     map[string]struct{}
+	listeners  []func(Event)
+}
+
+// New creates a new empty Portfolio. Call Load to seed it from an account snapshot.
+func New(cfg Config) *Portfolio {
+	if cfg.PrivateMessageType == "" {
+		cfg.PrivateMessageType = DefaultPrivateMessageType
+	}
+	return &Portfolio{
+		cfg:       cfg,
+		contracts: make(map[string]*contractState),
+		prices:    make(map[string]decimal.Decimal),
+		seenTx:    make(map[string]struct{}),
+	}
+}
+
+// OnChange registers a listener called after every change. Listeners run on the
+// goroutine that delivered the update and must not block.
+func (p *Portfolio) OnChange(listener func(Event)) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.listeners = append(p.listeners, listener)
+}
+
+// Load replaces the portfolio state with a GetAccountAssetData snapshot. The state
+// is left unchanged if the snapshot holds a malformed amount.
+func (p *Portfolio) Load(data account.AccountAssetData) error {
+	collateral := decimal.Zero
+	if c, ok := data.Collateral(p.cfg.CoinID); ok {
+		amount, err := c.AmountDecimal()
+		if err != nil {
+			return fmt.Errorf("failed to load portfolio: collateral %s: %w", c.CoinID, err)
+		}
+		collateral = amount
+	}
+	contracts := make(map[string]*contractState)
+	for _, position := range data.PositionList {
+		if !p.tracks(position.CoinID) {
+			continue
+		}
+		figures, err := figuresOf(position)
+		if err != nil {
+			return fmt.Errorf("failed to load portfolio: %w", err)
+		}
+		state := &contractState{position: figures}
+		if asset, ok := data.PositionAsset(position.ContractID); ok {
+			if state.realized, err = asset.TotalRealizePnlDecimal(); err != nil {
+				return fmt.Errorf("failed to load portfolio: position asset %s: %w", position.ContractID, err)
+			}
+		}
+		contracts[position.ContractID] = state
+	}
+	prices := make(map[string]decimal.Decimal)
+	if p.cfg.PriceSource == PriceOracle {
+		for _, price := range data.OraclePriceList {
+			value, err := price.PriceValueDecimal()
+			if err != nil {
+				return fmt.Errorf("failed to load portfolio: oracle price %s: %w", price.ContractID, err)
+			}
+			prices[price.ContractID] = value
+		}
+	}
+
+	p.mu.Lock()
+	p.collateral = collateral
+	p.contracts = contracts
+	p.seenTx = make(map[string]struct{})
+	for contractID, price := range prices {
+		p.prices[contractID] = price
+	}
+	p.mu.Unlock()
+
+	p.emit(EventLoaded, "")
+	return nil
+}
+
+// Sync loads the current account asset over REST
+func (p *Portfolio) Sync(ctx context.Context, client *account.Client) error {
+	resp, err := client.GetAccountAssetData(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to sync portfolio: %w", err)
+	}
+	return p.Load(resp.Data)
+}
+
+// Attach feeds the portfolio from the manager's private connection and subscribes
+// to the tickers of contractIDs on its public connection. Both connections must be established.
+// The portfolio's handlers are added next to those already registered on the manager;
+// the returned function removes them again.
+func (p *Portfolio) Attach(manager *ws.Manager, contractIDs []string) (func(), error) {
+	removePrivate, err := manager.AddPrivateHandler(p.cfg.PrivateMessageType, p.HandlePrivateMessage)
+	if err != nil {
+		return nil, err
+	}
+	removers := []func(){removePrivate}
+	detach := func() {
+		for _, remove := range removers {
+			remove()
+		}
+	}
+	for _, contractID := range contractIDs {
+		remove, err := manager.SubscribeMarketTicker(contractID, p.HandleTicker)
+		if err != nil {
+			detach()
+			return nil, fmt.Errorf("failed to subscribe ticker %s: %w", contractID, err)
+		}
+		removers = append(removers, remove)
+	}
+	return detach, nil
+}
+
+// tracks reports whether a coin belongs to the tracked collateral coin
+func (p *Portfolio) tracks(coinID string) bool {
+	return coinID == "" || p.cfg.CoinID == "" || coinID == p.cfg.CoinID
+}
+
+// UpdatePrice sets the valuation price of a contract
+func (p *Portfolio) UpdatePrice(contractID string, price decimal.Decimal) {
+	p.mu.Lock()
+	p.prices[contractID] = price
+	p.mu.Unlock()
+
+	p.emit(EventPrice, contractID)
+}
+
+// HandleTicker is a ws.MessageHandler for public ticker quote events
+func (p *Portfolio) HandleTicker(message []byte) {
+	var event struct {
+		Content struct {
+			Data []quote.Ticker `json:"data"`
+		} `json:"content"`
+	}
+	if err := json.Unmarshal(message, &event); err != nil {
+		return
+	}
+
+	for _, ticker := range event.Content.Data {
+		if ticker.ContractId == nil {
+			continue
+		}
+		var value *string
+		switch p.cfg.PriceSource {
+		case PriceLast:
+			value = ticker.LastPrice
+		case PriceIndex:
+			value = ticker.IndexPrice
+		default:
+			value = ticker.OraclePrice
+		}
+		if value == nil {
+			continue
+		}
+		price, err := decimal.NewFromString(*value)
+		if err != nil || !price.IsPositive() {
+			continue
+		}
+		p.UpdatePrice(*ticker.ContractId, price)
+	}
+}
+
+// privateEvent is the part of a private account event the portfolio uses
+type privateEvent struct {
+	Content struct {
+		Event string `json:"event"`
+		Data  struct {
+			Collateral          []account.Collateral          `json:"collateral"`
+			Position            []account.Position            `json:"position"`
+			PositionTransaction []account.PositionTransaction `json:"positionTransaction"`
+		} `json:"data"`
+	} `json:"content"`
+}
+
+// HandlePrivateMessage is a ws.MessageHandler for private account events. Events
+// holding a malformed amount are ignored as a whole, like malformed JSON.
+func (p *Portfolio) HandlePrivateMessage(message []byte) {
+	var event privateEvent
+	if err := json.Unmarshal(message, &event); err != nil {
+		return
+	}
+	data := event.Content.Data
+
+	collaterals := make([]decimal.Decimal, len(data.Collateral))
+	for i, collateral := range data.Collateral {
+		amount, err := collateral.AmountDecimal()
+		if err != nil {
+			return
+		}
+		collaterals[i] = amount
+	}
+	realized := make([]decimal.Decimal, len(data.PositionTransaction))
+	for i, tx := range data.PositionTransaction {
+		pnl, err := tx.RealizePnlDecimal()
+		if err != nil {
+			return
+		}
+		realized[i] = pnl
+	}
+	positions := make([]positionFigures, len(data.Position))
+	for i, position := range data.Position {
+		figures, err := figuresOf(position)
+		if err != nil {
+			return
+		}
+		positions[i] = figures
+	}
+
+	type change struct {
+		eventType  EventType
+		contractID string
+	}
+	var changes []change
+
+	p.mu.Lock()
+	for i, collateral := range data.Collateral {
+		if collateral.CoinID != p.cfg.CoinID && p.cfg.CoinID != "" {
+			continue
+		}
+		p.collateral = collaterals[i]
+		changes = append(changes, change{EventCollateral, ""})
+	}
+	for i, tx := range data.PositionTransaction {
+		// Transactions replayed in a snapshot are already part of the loaded realized PnL
+		if event.Content.Event == snapshotEvent {
+			break
+		}
+		if tx.ContractId == nil || (tx.CoinId != nil && !p.tracks(*tx.CoinId)) {
+			continue
+		}
+		if tx.Id != nil {
+			if _, ok := p.seenTx[*tx.Id]; ok {
+				continue
+			}
+			p.seenTx[*tx.Id] = struct{}{}
+		}
+		state := p.state(*tx.ContractId)
+		state.realized = state.realized.Add(realized[i])
+		changes = append(changes, change{EventFill, *tx.ContractId})
+	}
+	for i, position := range data.Position {
+		if !p.tracks(position.CoinID) {
+			continue
+		}
+		p.state(position.ContractID).position = positions[i]
+		changes = append(changes, change{EventPosition, position.ContractID})
+	}
+	p.mu.Unlock()
+
+	for _, c := range changes {
+		p.emit(c.eventType, c.contractID)
+	}
+}
+
+// state returns the state of a contract, creating it if needed. The caller must hold mu.
+func (p *Portfolio) state(contractID string) *contractState {
+	state, ok := p.contracts[contractID]
+	if !ok {
+		state = &contractState{}
+		p.contracts[contractID] = state
+	}
+	return state
+}
+
+// Snapshot computes the current portfolio figures
+func (p *Portfolio) Snapshot() Snapshot {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+
+	s := Snapshot{
+		Time:       time.Now(),
+		Collateral: p.collateral,
+		Equity:     p.collateral,
+		Contracts:  make(map[string]ContractPnL, len(p.contracts)),
+	}
+	for contractID, state := range p.contracts {
+		position := state.position
+		c := ContractPnL{
+			ContractID:  contractID,
+			Size:        position.size,
+			OpenValue:   position.openValue,
+			RealizedPnl: state.realized,
+			Funding:     position.funding,
+			Fees:        position.fees,
+		}
+
+		price, ok := p.prices[contractID]
+		switch {
+		case ok:
+			c.Price = price
+			c.Exposure = c.Size.Mul(price)
+			c.UnrealizedPnl = c.Exposure.Sub(c.OpenValue)
+		case !c.Size.IsZero():
+			s.MissingPrices = append(s.MissingPrices, contractID)
+		}
+
+		s.Contracts[contractID] = c
+		s.Equity = s.Equity.Add(c.Exposure)
+		s.UnrealizedPnl = s.UnrealizedPnl.Add(c.UnrealizedPnl)
+		s.RealizedPnl = s.RealizedPnl.Add(c.RealizedPnl)
+		s.Funding = s.Funding.Add(c.Funding)
+		s.Fees = s.Fees.Add(c.Fees)
+		s.GrossExposure = s.GrossExposure.Add(c.Exposure.Abs())
+		s.NetExposure = s.NetExposure.Add(c.Exposure)
+	}
+	return s
+}
+
+// emit notifies the listeners of a change
+func (p *Portfolio) emit(eventType EventType, contractID string) {
+	p.mu.RLock()
+	listeners := p.listeners
+	p.mu.RUnlock()
+
+	if len(listeners) == 0 {
+		return
+	}
+	event := Event{
+		Type:       eventType,
+		ContractID: contractID,
+		Snapshot:   p.Snapshot(),
+	}
+	for _, listener := range listeners {
+		listener(event)
+	}
+}
@@ -0,0 +1,78 @@
+package reports
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"time"
+)
+
+// Ledger selects one of the ledgers of a Report
+type Ledger string
+
+const (
+	// LedgerTrades is the trade ledger
+	LedgerTrades Ledger = "trades"
+	// LedgerTerms is the position term ledger
+	LedgerTerms Ledger = "terms"
+	// LedgerCash is the cash ledger
+	LedgerCash Ledger = "cash"
+)
+
+// WriteJSON writes the whole report as indented JSON
+func (r *Report) WriteJSON(w io.Writer) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(r)
+}
+
+// WriteCSV writes one ledger as CSV with a header row. Times are RFC 3339 in UTC.
+func (r *Report) WriteCSV(w io.Writer, ledger Ledger) error {
+	var rows [][]string
+	switch ledger {
+	case LedgerTrades:
+		rows = append(rows, []string{"time", "fill_id", "order_id", "coin_id", "contract_id", "side", "price", "size", "value", "fee", "realized_pnl", "fill_type", "match_sequence"})
+		for _, t := range r.Trades {
+			rows = append(rows, []string{
+				formatTime(t.Time), t.FillID, t.OrderID, t.CoinID, t.ContractID, t.Side,
+				t.Price.String(), t.Size.String(), t.Value.String(), t.Fee.String(), t.RealizedPnl.String(),
+				t.FillType, t.MatchSequence,
+			})
+		}
+	case LedgerTerms:
+		rows = append(rows, []string{"contract_id", "coin_id", "term_count", "open_time", "update_time", "closed", "open_size", "open_value", "close_size", "close_value", "fees", "funding", "realized_pnl"})
+		for _, t := range r.Terms {
+			rows = append(rows, []string{
+				t.ContractID, t.CoinID, strconv.Itoa(int(t.TermCount)), formatTime(t.OpenTime), formatTime(t.UpdateTime),
+				strconv.FormatBool(t.Closed), t.OpenSize.String(), t.OpenValue.String(), t.CloseSize.String(),
+				t.CloseValue.String(), t.Fees.String(), t.Funding.String(), t.RealizedPnl.String(),
+			})
+		}
+	case LedgerCash:
+		rows = append(rows, []string{"time", "transaction_id", "category", "type", "coin_id", "contract_id", "reference", "amount", "balance_before", "balance_after"})
+		for _, e := range r.Cash {
+			rows = append(rows, []string{
+				formatTime(e.Time), e.TransactionID, string(e.Category), e.Type, e.CoinID, e.ContractID, e.Reference,
+				e.Amount.String(), e.BalanceBefore.String(), e.BalanceAfter.String(),
+			})
+		}
+	default:
+		return fmt.Errorf("unknown ledger: %s", ledger)
+	}
+
+	writer := csv.NewWriter(w)
+	if err := writer.WriteAll(rows); err != nil {
+		return fmt.Errorf("failed to write %s ledger: %w", ledger, err)
+	}
+	return nil
+}
+
+// formatTime formats a ledger time, leaving zero times empty
+func formatTime(t time.Time) string {
+	if t.IsZero() {
+		return ""
+	}
+	return t.UTC().Format(time.RFC3339Nano)
+}
@@ -0,0 +1,181 @@
+package reports
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/coin-quant/go-edgex/sdk/account"
+	"github.com/coin-quant/go-edgex/sdk/order"
+)
+
+// DefaultPageSize is the page size used when Params.PageSize is not set
+const DefaultPageSize = 100
+
+// Params selects the history of a report
+type Params struct {
+	// CoinID is the collateral coin to report on
+	CoinID string
+	// From is inclusive and To exclusive. A zero From means from the start and a zero
+	// To means up to now.
+	From time.Time
+	To   time.Time
+	// PageSize is the page size of every request, DefaultPageSize if zero
+	PageSize int32
+}
+
+// Fetch pages through the fills, position transactions, collateral transactions and
+// position terms of the period and builds a Report from them
+func Fetch(ctx context.Context, orders *order.Client, accounts *account.Client, params Params) (*Report, error) {
+	if params.CoinID == "" {
+		return nil, fmt.Errorf("coin ID is required")
+	}
+	if params.PageSize <= 0 {
+		params.PageSize = DefaultPageSize
+	}
+	to := params.To
+	if to.IsZero() {
+		to = time.Now()
+	}
+
+	history, err := FetchHistory(ctx, orders, accounts, params.CoinID, params.From, to, params.PageSize)
+	if err != nil {
+		return nil, err
+	}
+	report, err := Build(*history)
+	if err != nil {
+		return nil, err
+	}
+	report.From = params.From.UTC()
+	report.To = to.UTC()
+	return report, nil
+}
+
+// FetchHistory pages through the raw account history of a coin. Fills and collateral
+// transactions are those created in [from, to); a zero from means from the start.
+// Position terms are those open during the window, and position transactions start
+// at the open time of the earliest of them, so that the realized PnL of a term opened
+// before from is complete.
+func FetchHistory(ctx context.Context, orders *order.Client, accounts *account.Client, coinID string, from, to time.Time, pageSize int32) (*History, error) {
+	history := &History{}
+	var start int64
+	if !from.IsZero() {
+		start = from.UnixMilli()
+	}
+	end := to.UnixMilli()
+
+	termParams := account.GetPositionTermPageParams{
+		Size:                 pageSize,
+		FilterCoinIDList:     []string{coinID},
+		FilterEndCreatedTime: end,
+	}
+	for {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		resp, err := accounts.GetPositionTermPage(ctx, termParams)
+		if err != nil {
+			return nil, err
+		}
+		if resp.Data == nil {
+			break
+		}
+		for _, term := range resp.Data.DataList {
+			if start == 0 || !parseTime(term.UpdatedTime).Before(from) {
+				history.PositionTerms = append(history.PositionTerms, term)
+			}
+		}
+		if !hasNextPage(resp.Data.NextPageOffsetData, len(resp.Data.DataList)) {
+			break
+		}
+		termParams.OffsetData = *resp.Data.NextPageOffsetData
+	}
+
+	fillParams := &order.OrderFillTransactionParams{
+		FilterEndCreatedTimeExclusive: uint64(end),
+	}
+	if start > 0 {
+		fillParams.FilterStartCreatedTimeInclusive = uint64(start)
+	}
+	fillParams.Size = strconv.Itoa(int(pageSize))
+	fillParams.FilterCoinIdList = []string{coinID}
+	for {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		resp, err := orders.GetOrderFillTransactions(ctx, fillParams)
+		if err != nil {
+			return nil, err
+		}
+		if resp.Data == nil {
+			break
+		}
+		history.Fills = append(history.Fills, resp.Data.DataList...)
+		if !hasNextPage(resp.Data.NextPageOffsetData, len(resp.Data.DataList)) {
+			break
+		}
+		fillParams.OffsetData = *resp.Data.NextPageOffsetData
+	}
+
+	positionStart := start
+	for _, term := range history.PositionTerms {
+		if opened := parseTime(term.CreatedTime); !opened.IsZero() && positionStart > 0 && opened.UnixMilli() < positionStart {
+			positionStart = opened.UnixMilli()
+		}
+	}
+	positionParams := account.GetPositionTransactionPageParams{
+		Size:                   pageSize,
+		FilterCoinIDList:       []string{coinID},
+		FilterStartCreatedTime: positionStart,
+		FilterEndCreatedTime:   end,
+	}
+	for {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		resp, err := accounts.GetPositionTransactionPage(ctx, positionParams)
+		if err != nil {
+			return nil, err
+		}
+		if resp.Data == nil {
+			break
+		}
+		history.PositionTransactions = append(history.PositionTransactions, resp.Data.DataList...)
+		if !hasNextPage(resp.Data.NextPageOffsetData, len(resp.Data.DataList)) {
+			break
+		}
+		positionParams.OffsetData = *resp.Data.NextPageOffsetData
+	}
+
+	collateralParams := account.GetCollateralTransactionPageParams{
+		Size:                   pageSize,
+		FilterCoinIDList:       []string{coinID},
+		FilterStartCreatedTime: start,
+		FilterEndCreatedTime:   end,
+	}
+	for {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		resp, err := accounts.GetCollateralTransactionPage(ctx, collateralParams)
+		if err != nil {
+			return nil, err
+		}
+		if resp.Data == nil {
+			break
+		}
+		history.CollateralTransactions = append(history.CollateralTransactions, resp.Data.DataList...)
+		if !hasNextPage(resp.Data.NextPageOffsetData, len(resp.Data.DataList)) {
+			break
+		}
+		collateralParams.OffsetData = *resp.Data.NextPageOffsetData
+	}
+
+	return history, nil
+}
+
+// hasNextPage reports whether a page response points to a further page
+func hasNextPage(next *string, count int) bool {
+	return next != nil && *next != "" && count > 0
+}
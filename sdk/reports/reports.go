@@ -0,0 +1,351 @@
+// Package reports builds normalized trade, position term and cash ledgers from the
+// account history and exports them to CSV and JSON for accounting and tax reporting.
+package reports
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/coin-quant/go-edgex/sdk/account"
+	"github.com/coin-quant/go-edgex/sdk/funding"
+	"github.com/coin-quant/go-edgex/sdk/internal"
+	"github.com/coin-quant/go-edgex/sdk/order"
+	"github.com/shopspring/decimal"
+)
+
+// Category classifies a cash ledger entry
+type Category string
+
+const (
+	// CategoryTrade is a fill, including its fee and realized PnL
+	CategoryTrade Category = "TRADE"
+	// CategoryFunding is a funding settlement
+	CategoryFunding Category = "FUNDING"
+	// CategoryDeposit is a deposit
+	CategoryDeposit Category = "DEPOSIT"
+	// CategoryWithdrawal is a normal, fast, cross-chain or forced withdrawal
+	CategoryWithdrawal Category = "WITHDRAWAL"
+	// CategoryTransferIn is an incoming transfer from another account
+	CategoryTransferIn Category = "TRANSFER_IN"
+	// CategoryTransferOut is an outgoing transfer to another account
+	CategoryTransferOut Category = "TRANSFER_OUT"
+	// CategoryOther is any other collateral change, such as a forced trade
+	CategoryOther Category = "OTHER"
+)
+
+// Trade is a normalized order fill
+type Trade struct {
+	Time          time.Time       `json:"time"`
+	FillID        string          `json:"fillId"`
+	OrderID       string          `json:"orderId"`
+	CoinID        string          `json:"coinId"`
+	ContractID    string          `json:"contractId"`
+	Side          string          `json:"side"`
+	Price         decimal.Decimal `json:"price"`
+	Size          decimal.Decimal `json:"size"`
+	Value         decimal.Decimal `json:"value"`
+	Fee           decimal.Decimal `json:"fee"`
+	RealizedPnl   decimal.Decimal `json:"realizedPnl"`
+	FillType      string          `json:"fillType"`
+	MatchSequence string          `json:"matchSequence"`
+}
+
+// Term is a normalized position term: the life of a position from open to close
+type Term struct {
+	ContractID string    `json:"contractId"`
+	CoinID     string    `json:"coinId"`
+	TermCount  int32     `json:"termCount"`
+	OpenTime   time.Time `json:"openTime"`
+	// UpdateTime is the close time of a closed term
+	UpdateTime time.Time       `json:"updateTime"`
+	Closed     bool            `json:"closed"`
+	OpenSize   decimal.Decimal `json:"openSize"`
+	OpenValue  decimal.Decimal `json:"openValue"`
+	CloseSize  decimal.Decimal `json:"closeSize"`
+	CloseValue decimal.Decimal `json:"closeValue"`
+	Fees       decimal.Decimal `json:"fees"`
+	Funding    decimal.Decimal `json:"funding"`
+	// RealizedPnl sums the realized PnL of the contract's position transactions
+	// created during the term
+	RealizedPnl decimal.Decimal `json:"realizedPnl"`
+}
+
+// Entry is a normalized collateral change
+type Entry struct {
+	Time          time.Time       `json:"time"`
+	TransactionID string          `json:"transactionId"`
+	Category      Category        `json:"category"`
+	Type          string          `json:"type"`
+	CoinID        string          `json:"coinId"`
+	ContractID    string          `json:"contractId,omitempty"`
+	Reference     string          `json:"reference,omitempty"`
+	Amount        decimal.Decimal `json:"amount"`
+	BalanceBefore decimal.Decimal `json:"balanceBefore"`
+	BalanceAfter  decimal.Decimal `json:"balanceAfter"`
+}
+
+// Break is a cash ledger entry whose opening balance does not match the closing
+// balance of the entry before it, usually because of missing history
+type Break struct {
+	TransactionID string          `json:"transactionId"`
+	Expected      decimal.Decimal `json:"expected"`
+	Actual        decimal.Decimal `json:"actual"`
+}
+
+// Reconciliation ties the cash ledger to the collateral balance
+type Reconciliation struct {
+	OpeningBalance decimal.Decimal              `json:"openingBalance"`
+	ClosingBalance decimal.Decimal              `json:"closingBalance"`
+	NetChange      decimal.Decimal              `json:"netChange"`
+	ByCategory     map[Category]decimal.Decimal `json:"byCategory"`
+	Breaks         []Break                      `json:"breaks"`
+}
+
+// Reconciled reports whether every entry chains onto the one before it
+func (r Reconciliation) Reconciled() bool {
+	return len(r.Breaks) == 0
+}
+
+// History is the raw account history a Report is built from
+type History struct {
+	Fills                  []order.OrderFillTransaction
+	PositionTransactions   []account.PositionTransaction
+	CollateralTransactions []account.CollateralTransaction
+	PositionTerms          []account.PositionTerm
+}
+
+// Report holds the normalized ledgers of a period
+type Report struct {
+	From           time.Time      `json:"from"`
+	To             time.Time      `json:"to"`
+	Trades         []Trade        `json:"trades"`
+	Terms          []Term         `json:"terms"`
+	Cash           []Entry        `json:"cash"`
+	Reconciliation Reconciliation `json:"reconciliation"`
+}
+
+// Build normalizes and reconciles history. Every ledger is ordered by time, and cash
+// entries of the same millisecond follow the balance chain. The history should cover
+// a single collateral coin, as the opening and closing balances are those of the
+// first and last cash entries. It fails on the first amount that is not a valid
+// number.
+func Build(history History) (*Report, error) {
+	report := &Report{}
+	var firstErr error
+	value := func(d decimal.Decimal, err error) decimal.Decimal {
+		if err != nil && firstErr == nil {
+			firstErr = err
+		}
+		return d
+	}
+
+	realized := make(map[string]decimal.Decimal)
+	for _, tx := range history.PositionTransactions {
+		if tx.OrderFillTransactionId != nil {
+			realized[*tx.OrderFillTransactionId] = realized[*tx.OrderFillTransactionId].Add(value(tx.RealizePnlDecimal()))
+		}
+	}
+
+	for _, fill := range history.Fills {
+		trade := Trade{
+			Time:          parseTime(fill.CreatedTime),
+			FillID:        internal.StringValue(fill.Id),
+			OrderID:       internal.StringValue(fill.OrderId),
+			CoinID:        internal.StringValue(fill.CoinId),
+			ContractID:    internal.StringValue(fill.ContractId),
+			Side:          internal.StringValue(fill.Side),
+			Price:         value(parseDecimal("fillPrice", fill.FillPrice)),
+			Size:          value(parseDecimal("fillSize", fill.FillSize)),
+			Value:         value(parseDecimal("fillValue", fill.FillValue)),
+			Fee:           value(parseDecimal("fillFee", fill.FillFee)),
+			FillType:      internal.StringValue(fill.FillType),
+			MatchSequence: internal.StringValue(fill.MatchSequenceId),
+		}
+		trade.RealizedPnl = realized[trade.FillID]
+		report.Trades = append(report.Trades, trade)
+	}
+	sort.SliceStable(report.Trades, func(i, j int) bool {
+		a, b := report.Trades[i], report.Trades[j]
+		if !a.Time.Equal(b.Time) {
+			return a.Time.Before(b.Time)
+		}
+		return idLess(a.FillID, b.FillID)
+	})
+
+	for _, pt := range history.PositionTerms {
+		term := Term{
+			ContractID: internal.StringValue(pt.ContractId),
+			CoinID:     internal.StringValue(pt.CoinId),
+			OpenTime:   parseTime(pt.CreatedTime),
+			UpdateTime: parseTime(pt.UpdatedTime),
+			OpenSize:   value(pt.CumOpenSizeDecimal()),
+			OpenValue:  value(pt.CumOpenValueDecimal()),
+			CloseSize:  value(pt.CumCloseSizeDecimal()),
+			CloseValue: value(pt.CumCloseValueDecimal()),
+			Fees:       value(pt.CumOpenFeeDecimal()).Add(value(pt.CumCloseFeeDecimal())).Add(value(pt.CumLiquidateFeeDecimal())),
+			Funding:    value(pt.CumFundingFeeDecimal()),
+		}
+		if pt.TermCount != nil {
+			term.TermCount = *pt.TermCount
+		}
+		term.Closed = !term.OpenSize.IsZero() && term.CloseSize.Abs().Equal(term.OpenSize.Abs())
+		for _, tx := range history.PositionTransactions {
+			if internal.StringValue(tx.ContractId) != term.ContractID {
+				continue
+			}
+			created := parseTime(tx.CreatedTime)
+			if created.Before(term.OpenTime) || created.After(term.UpdateTime) {
+				continue
+			}
+			term.RealizedPnl = term.RealizedPnl.Add(value(tx.RealizePnlDecimal()))
+		}
+		report.Terms = append(report.Terms, term)
+	}
+	sort.SliceStable(report.Terms, func(i, j int) bool {
+		a, b := report.Terms[i], report.Terms[j]
+		if !a.OpenTime.Equal(b.OpenTime) {
+			return a.OpenTime.Before(b.OpenTime)
+		}
+		if a.ContractID != b.ContractID {
+			return idLess(a.ContractID, b.ContractID)
+		}
+		return a.TermCount < b.TermCount
+	})
+
+	for _, tx := range history.CollateralTransactions {
+		entry := Entry{
+			Time:          parseTime(tx.CreatedTime),
+			TransactionID: internal.StringValue(tx.Id),
+			Type:          internal.StringValue(tx.Type),
+			CoinID:        internal.StringValue(tx.CoinId),
+			ContractID:    internal.StringValue(tx.PositionContractId),
+			Amount:        value(tx.DeltaAmountDecimal()),
+			BalanceBefore: value(tx.BeforeAmountDecimal()),
+		}
+		entry.Category, entry.Reference = classify(tx)
+		entry.BalanceAfter = entry.BalanceBefore.Add(entry.Amount)
+		report.Cash = append(report.Cash, entry)
+	}
+	sort.SliceStable(report.Cash, func(i, j int) bool {
+		a, b := report.Cash[i], report.Cash[j]
+		if !a.Time.Equal(b.Time) {
+			return a.Time.Before(b.Time)
+		}
+		return idLess(a.TransactionID, b.TransactionID)
+	})
+	chainTies(report.Cash)
+	if firstErr != nil {
+		return nil, fmt.Errorf("invalid history: %w", firstErr)
+	}
+	report.Reconciliation = reconcile(report.Cash)
+	return report, nil
+}
+
+// chainTies reorders cash entries that share a timestamp so that each one starts from
+// the balance the entry before it left, as timestamps only have millisecond precision.
+// Entries that chain onto none of the others keep their transaction ID order.
+func chainTies(entries []Entry) {
+	balances := make(map[string]decimal.Decimal)
+	for start := 0; start < len(entries); {
+		end := start + 1
+		for end < len(entries) && entries[end].Time.Equal(entries[start].Time) {
+			end++
+		}
+		for i := start; i < end; i++ {
+			next := i
+			for j := i; j < end; j++ {
+				previous, ok := balances[entries[j].CoinID]
+				if ok && previous.Equal(entries[j].BalanceBefore) {
+					next = j
+					break
+				}
+			}
+			entry := entries[next]
+			copy(entries[i+1:next+1], entries[i:next])
+			entries[i] = entry
+			balances[entry.CoinID] = entry.BalanceAfter
+		}
+		start = end
+	}
+}
+
+// idLess orders numeric IDs by value without parsing them: a shorter ID is smaller
+// and IDs of the same length compare lexically
+func idLess(a, b string) bool {
+	if len(a) != len(b) {
+		return len(a) < len(b)
+	}
+	return a < b
+}
+
+// classify returns the category of a collateral transaction and the ID of the
+// operation behind it
+func classify(tx account.CollateralTransaction) (Category, string) {
+	switch {
+	case internal.StringValue(tx.Type) == funding.CollateralTransactionTypeSettleFunding:
+		return CategoryFunding, internal.StringValue(tx.PositionTransactionId)
+	case internal.StringValue(tx.DepositId) != "":
+		return CategoryDeposit, *tx.DepositId
+	case internal.StringValue(tx.WithdrawId) != "":
+		return CategoryWithdrawal, *tx.WithdrawId
+	case internal.StringValue(tx.ForceWithdrawId) != "":
+		return CategoryWithdrawal, *tx.ForceWithdrawId
+	case internal.StringValue(tx.TransferInId) != "":
+		return CategoryTransferIn, *tx.TransferInId
+	case internal.StringValue(tx.TransferOutId) != "":
+		return CategoryTransferOut, *tx.TransferOutId
+	case internal.StringValue(tx.OrderFillTransactionId) != "":
+		return CategoryTrade, *tx.OrderFillTransactionId
+	default:
+		return CategoryOther, internal.StringValue(tx.ForceTradeId)
+	}
+}
+
+// reconcile chains the entries of each coin and totals them per category
+func reconcile(entries []Entry) Reconciliation {
+	r := Reconciliation{ByCategory: make(map[Category]decimal.Decimal)}
+	balances := make(map[string]decimal.Decimal)
+	for i, entry := range entries {
+		if i == 0 {
+			r.OpeningBalance = entry.BalanceBefore
+		}
+		if previous, ok := balances[entry.CoinID]; ok && !previous.Equal(entry.BalanceBefore) {
+			r.Breaks = append(r.Breaks, Break{
+				TransactionID: entry.TransactionID,
+				Expected:      previous,
+				Actual:        entry.BalanceBefore,
+			})
+		}
+		balances[entry.CoinID] = entry.BalanceAfter
+		r.ClosingBalance = entry.BalanceAfter
+		r.NetChange = r.NetChange.Add(entry.Amount)
+		r.ByCategory[entry.Category] = r.ByCategory[entry.Category].Add(entry.Amount)
+	}
+	return r
+}
+
+// parseDecimal parses an optional decimal, returning zero for missing input
+func parseDecimal(name string, s *string) (decimal.Decimal, error) {
+	if s == nil || *s == "" {
+		return decimal.Zero, nil
+	}
+	d, err := decimal.NewFromString(*s)
+	if err != nil {
+		return decimal.Zero, fmt.Errorf("invalid %s %q: %w", name, *s, err)
+	}
+	return d, nil
+}
+
+// parseTime parses an optional millisecond timestamp
+func parseTime(s *string) time.Time {
+	if s == nil {
+		return time.Time{}
+	}
+	ms, err := strconv.ParseInt(*s, 10, 64)
+	if err != nil || ms <= 0 {
+		return time.Time{}
+	}
+	return time.UnixMilli(ms).UTC()
+}
@@ -0,0 +1,102 @@
+package risk
+
+import (
+	"fmt"
+
+	"github.com/shopspring/decimal"
+)
+
+// LeverageChange describes the effect of changing the leverage setting of a contract
+type LeverageChange struct {
+	ContractID string
+	// Leverage is the requested leverage
+	Leverage decimal.Decimal
+	// MaxLeverage is the highest leverage allowed for the current position
+	MaxLeverage decimal.Decimal
+	Before      *AccountRisk
+	After       *AccountRisk
+	// InitialMarginChange is the change in initial margin caused by the new setting
+	InitialMarginChange decimal.Decimal
+	// Allowed reports whether the account keeps a non-negative available balance
+	// after the change
+	Allowed bool
+}
+
+// MaxLeverage returns the highest leverage allowed for a position of size in a
+// contract at price: the lowest of the side limits and the tier maximum. A flat
+// position is checked against both sides.
+func (c *Calculator) MaxLeverage(contractID string, size, price decimal.Decimal) (decimal.Decimal, error) {
+	cr, ok := c.contracts[contractID]
+	if !ok {
+		return decimal.Zero, fmt.Errorf("contract not found: %s", contractID)
+	}
+
+	maxLeverage := cr.tierFor(size.Mul(price).Abs()).MaxLeverage
+	limits := []decimal.Decimal{cr.maxLong, cr.maxShort}
+	switch size.Sign() {
+	case 1:
+		limits = limits[:1]
+	case -1:
+		limits = limits[1:]
+	}
+	for _, limit := range limits {
+		if limit.IsPositive() && limit.LessThan(maxLeverage) {
+			maxLeverage = limit
+		}
+	}
+	return maxLeverage, nil
+}
+
+// CheckLeverage validates a new leverage setting for a contract against the contract
+// limits and the risk tier of the current position, and evaluates the account with it
+func (c *Calculator) CheckLeverage(acc Account, contractID string, leverage decimal.Decimal) (*LeverageChange, error) {
+	if leverage.LessThan(decimal.NewFromInt(1)) {
+		return nil, fmt.Errorf("leverage must be at least 1, got %s", leverage)
+	}
+
+	var size decimal.Decimal
+	for _, position := range acc.Positions {
+		if position.ContractID == contractID {
+			size = position.Size
+		}
+	}
+	price := acc.OraclePrices[contractID]
+	if !size.IsZero() && !price.IsPositive() {
+		return nil, fmt.Errorf("oracle price not found for contract: %s", contractID)
+	}
+
+	maxLeverage, err := c.MaxLeverage(contractID, size, price)
+	if err != nil {
+		return nil, err
+	}
+	if leverage.GreaterThan(maxLeverage) {
+		return nil, fmt.Errorf("leverage %s exceeds the maximum of %s for contract %s", leverage, maxLeverage, contractID)
+	}
+
+	before, err := c.Evaluate(acc)
+	if err != nil {
+		return nil, err
+	}
+
+	settings := make(map[string]decimal.Decimal, len(acc.Leverage)+1)
+	for id, value := range acc.Leverage {
+		settings[id] = value
+	}
+	settings[contractID] = leverage
+	acc.Leverage = settings
+
+	after, err := c.Evaluate(acc)
+	if err != nil {
+		return nil, err
+	}
+
+	return &LeverageChange{
+		ContractID:          contractID,
+		Leverage:            leverage,
+		MaxLeverage:         maxLeverage,
+		Before:              before,
+		After:               after,
+		InitialMarginChange: after.InitialMargin.Sub(before.InitialMargin),
+		Allowed:             !after.AvailableBalance.IsNegative(),
+	}, nil
+}
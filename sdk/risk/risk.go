@@ -0,0 +1,516 @@
+// Package risk computes margin, equity and liquidation figures locally from
+// metadata, positions, collateral and oracle prices, so that pre-trade checks
+// do not need a REST round trip.
+package risk
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/coin-quant/go-edgex/sdk/account"
+	"github.com/coin-quant/go-edgex/sdk/metadata"
+	"github.com/coin-quant/go-edgex/sdk/order"
+	"github.com/shopspring/decimal"
+)
+
+// starkExRiskFactor converts a tier's StarkExRisk into a maintenance margin rate
+var starkExRiskFactor = decimal.NewFromInt(1 << 32)
+
+// liquidationIterations bounds the refinement of liquidation prices across tiers
+const liquidationIterations = 8
+
+// Tier is a parsed contract risk tier
+type Tier struct {
+	Tier                    int32
+	PositionValueUpperBound decimal.Decimal
+	MaxLeverage             decimal.Decimal
+	MaintenanceMarginRate   decimal.Decimal
+}
+
+// Position is a position to evaluate. Size is positive for long and negative for short positions.
+type Position struct {
+	ContractID string
+	Size       decimal.Decimal
+	// OpenValue is the signed cost of the position, used for unrealized PnL
+	OpenValue decimal.Decimal
+}
+
+// Account is the account state to evaluate
+type Account struct {
+	// Collateral is the collateral amount. It already reflects the cash flows of open
+	// positions, so equity is Collateral plus the signed value of all positions.
+	Collateral         decimal.Decimal
+	Positions          []Position
+	OraclePrices       map[string]decimal.Decimal
+	PendingWithdraw    decimal.Decimal
+	PendingTransferOut decimal.Decimal
+	OrderFrozen        decimal.Decimal
+	// Leverage holds per-contract leverage settings. Contracts without a setting use
+	// DefaultLeverage, or the contract default leverage, capped by the tier maximum.
+	Leverage map[string]decimal.Decimal
+	// DefaultLeverage is the account-wide leverage setting, zero if not set
+	DefaultLeverage decimal.Decimal
+}
+
+// PositionRisk holds the risk figures of one position
+type PositionRisk struct {
+	ContractID        string
+	Size              decimal.Decimal
+	OraclePrice       decimal.Decimal
+	Value             decimal.Decimal
+	AbsValue          decimal.Decimal
+	UnrealizedPnl     decimal.Decimal
+	Tier              Tier
+	Leverage          decimal.Decimal
+	InitialMargin     decimal.Decimal
+	MaintenanceMargin decimal.Decimal
+	// LiquidationPrice is zero when the position cannot be liquidated by its own price move
+	LiquidationPrice decimal.Decimal
+	// BankruptcyPrice is zero when equity stays positive at any price
+	BankruptcyPrice decimal.Decimal
+}
+
+// AccountRisk holds the risk figures of an account
+type AccountRisk struct {
+	TotalEquity           decimal.Decimal
+	TotalPositionValueAbs decimal.Decimal
+	UnrealizedPnl         decimal.Decimal
+	InitialMargin         decimal.Decimal
+	MaintenanceMargin     decimal.Decimal
+	AvailableBalance      decimal.Decimal
+	// MarginRatio is maintenance margin over equity. The account is liquidatable at 1 or above.
+	MarginRatio decimal.Decimal
+	Positions   []PositionRisk
+}
+
+// Position returns the risk of the position in a contract
+func (r *AccountRisk) Position(contractID string) (PositionRisk, bool) {
+	for _, position := range r.Positions {
+		if position.ContractID == contractID {
+			return position, true
+		}
+	}
+	return PositionRisk{}, false
+}
+
+// Liquidatable reports whether maintenance margin exceeds equity
+func (r *AccountRisk) Liquidatable() bool {
+	return r.MaintenanceMargin.IsPositive() && r.TotalEquity.LessThanOrEqual(r.MaintenanceMargin)
+}
+
+// contractRisk is the risk configuration of a contract
+type contractRisk struct {
+	tiers           []Tier
+	defaultLeverage decimal.Decimal
+	maxLong         decimal.Decimal
+	maxShort        decimal.Decimal
+}
+
+// Calculator evaluates account risk against contract metadata
+type Calculator struct {
+	contracts map[string]contractRisk
+}
+
+// NewCalculator creates a new Calculator from exchange metadata
+func NewCalculator(meta *metadata.MetaData) (*Calculator, error) {
+	if meta == nil {
+		return nil, fmt.Errorf("metadata is required")
+	}
+
+	c := &Calculator{contracts: make(map[string]contractRisk, len(meta.ContractList))}
+	for _, contract := range meta.ContractList {
+		cr, err := parseContract(contract)
+		if err != nil {
+			return nil, fmt.Errorf("contract %s: %w", contract.ContractId, err)
+		}
+		c.contracts[contract.ContractId] = cr
+	}
+	return c, nil
+}
+
+// parseContract reads the risk tiers of a contract. Contracts without tiers get a
+// single unbounded tier from their maintenance margin rate and maximum leverage.
+func parseContract(contract metadata.Contract) (contractRisk, error) {
+	cr := contractRisk{
+		defaultLeverage: parseOptional(contract.DefaultLeverage),
+		maxLong:         parseOptional(contract.MaxLongLeverage),
+		maxShort:        parseOptional(contract.MaxShortLeverage),
+	}
+
+	for _, rt := range contract.RiskTierList {
+		upper, err := decimal.NewFromString(rt.PositionValueUpperBound)
+		if err != nil {
+			return cr, fmt.Errorf("invalid positionValueUpperBound in tier %d: %w", rt.Tier, err)
+		}
+		maxLeverage, err := decimal.NewFromString(rt.MaxLeverage)
+		if err != nil {
+			return cr, fmt.Errorf("invalid maxLeverage in tier %d: %w", rt.Tier, err)
+		}
+
+		var rate decimal.Decimal
+		if rt.StarkExRisk != "" {
+			risk, err := decimal.NewFromString(rt.StarkExRisk)
+			if err != nil {
+				return cr, fmt.Errorf("invalid starkExRisk in tier %d: %w", rt.Tier, err)
+			}
+			rate = risk.Div(starkExRiskFactor)
+		} else {
+			rate, err = decimal.NewFromString(rt.MaintenanceMarginRate)
+			if err != nil {
+				return cr, fmt.Errorf("invalid maintenanceMarginRate in tier %d: %w", rt.Tier, err)
+			}
+		}
+
+		cr.tiers = append(cr.tiers, Tier{
+			Tier:                    rt.Tier,
+			PositionValueUpperBound: upper,
+			MaxLeverage:             maxLeverage,
+			MaintenanceMarginRate:   rate,
+		})
+	}
+	sort.Slice(cr.tiers, func(i, j int) bool {
+		// A zero bound is unbounded and sorts last
+		bi, bj := cr.tiers[i].PositionValueUpperBound, cr.tiers[j].PositionValueUpperBound
+		if bi.IsZero() || bj.IsZero() {
+			return !bi.IsZero()
+		}
+		return bi.LessThan(bj)
+	})
+
+	if len(cr.tiers) == 0 {
+		rate, err := decimal.NewFromString(contract.MaintenanceMarginRate)
+		if err != nil {
+			return cr, fmt.Errorf("no risk tiers and invalid maintenanceMarginRate: %w", err)
+		}
+		maxLeverage := decimal.Max(cr.maxLong, cr.maxShort)
+		if !maxLeverage.IsPositive() {
+			return cr, fmt.Errorf("no risk tiers and no max leverage")
+		}
+		cr.tiers = []Tier{{
+			Tier:                  1,
+			MaxLeverage:           maxLeverage,
+			MaintenanceMarginRate: rate,
+		}}
+	}
+	return cr, nil
+}
+
+// parseOptional parses a decimal, returning zero for empty or invalid input
+func parseOptional(value string) decimal.Decimal {
+	d, err := decimal.NewFromString(value)
+	if err != nil {
+		return decimal.Zero
+	}
+	return d
+}
+
+// Tiers returns the risk tiers of a contract ordered by position value
+func (c *Calculator) Tiers(contractID string) ([]Tier, error) {
+	cr, ok := c.contracts[contractID]
+	if !ok {
+		return nil, fmt.Errorf("contract not found: %s", contractID)
+	}
+	return append([]Tier(nil), cr.tiers...), nil
+}
+
+// TierFor returns the tier that applies to an absolute position value. Values above
+// the last bound use the last tier; a zero bound means the tier is unbounded.
+func (c *Calculator) TierFor(contractID string, absValue decimal.Decimal) (Tier, error) {
+	cr, ok := c.contracts[contractID]
+	if !ok {
+		return Tier{}, fmt.Errorf("contract not found: %s", contractID)
+	}
+	return cr.tierFor(absValue), nil
+}
+
+func (cr contractRisk) tierFor(absValue decimal.Decimal) Tier {
+	for _, tier := range cr.tiers {
+		if tier.PositionValueUpperBound.IsZero() || absValue.LessThanOrEqual(tier.PositionValueUpperBound) {
+			return tier
+		}
+	}
+	return cr.tiers[len(cr.tiers)-1]
+}
+
+// leverage returns the effective leverage of a position: the configured setting or
+// contract default, capped by the tier and the contract's side limit
+func (cr contractRisk) leverage(setting decimal.Decimal, tier Tier, long bool) decimal.Decimal {
+	leverage := setting
+	if !leverage.IsPositive() {
+		leverage = cr.defaultLeverage
+	}
+	if !leverage.IsPositive() || leverage.GreaterThan(tier.MaxLeverage) {
+		leverage = tier.MaxLeverage
+	}
+	sideMax := cr.maxShort
+	if long {
+		sideMax = cr.maxLong
+	}
+	if sideMax.IsPositive() && leverage.GreaterThan(sideMax) {
+		leverage = sideMax
+	}
+	return leverage
+}
+
+// leverageSetting returns the leverage setting of a contract, zero if not set
+func (acc Account) leverageSetting(contractID string) decimal.Decimal {
+	if leverage, ok := acc.Leverage[contractID]; ok {
+		return leverage
+	}
+	return acc.DefaultLeverage
+}
+
+// Evaluate computes the risk of an account at its oracle prices
+func (c *Calculator) Evaluate(acc Account) (*AccountRisk, error) {
+	result := &AccountRisk{
+		TotalEquity: acc.Collateral,
+	}
+
+	for _, position := range acc.Positions {
+		if position.Size.IsZero() {
+			continue
+		}
+		cr, ok := c.contracts[position.ContractID]
+		if !ok {
+			return nil, fmt.Errorf("contract not found: %s", position.ContractID)
+		}
+		price, ok := acc.OraclePrices[position.ContractID]
+		if !ok || !price.IsPositive() {
+			return nil, fmt.Errorf("oracle price not found for contract: %s", position.ContractID)
+		}
+
+		value := position.Size.Mul(price)
+		absValue := value.Abs()
+		tier := cr.tierFor(absValue)
+		leverage := cr.leverage(acc.leverageSetting(position.ContractID), tier, position.Size.IsPositive())
+
+		pr := PositionRisk{
+			ContractID:        position.ContractID,
+			Size:              position.Size,
+			OraclePrice:       price,
+			Value:             value,
+			AbsValue:          absValue,
+			UnrealizedPnl:     value.Sub(position.OpenValue),
+			Tier:              tier,
+			Leverage:          leverage,
+			InitialMargin:     absValue.Div(leverage),
+			MaintenanceMargin: absValue.Mul(tier.MaintenanceMarginRate),
+		}
+		result.Positions = append(result.Positions, pr)
+
+		result.TotalEquity = result.TotalEquity.Add(value)
+		result.TotalPositionValueAbs = result.TotalPositionValueAbs.Add(absValue)
+		result.UnrealizedPnl = result.UnrealizedPnl.Add(pr.UnrealizedPnl)
+		result.InitialMargin = result.InitialMargin.Add(pr.InitialMargin)
+		result.MaintenanceMargin = result.MaintenanceMargin.Add(pr.MaintenanceMargin)
+	}
+
+	result.AvailableBalance = result.TotalEquity.
+		Sub(result.InitialMargin).
+		Sub(acc.PendingWithdraw).
+		Sub(acc.PendingTransferOut).
+		Sub(acc.OrderFrozen)
+	if result.TotalEquity.IsPositive() {
+		result.MarginRatio = result.MaintenanceMargin.Div(result.TotalEquity)
+	} else if result.MaintenanceMargin.IsPositive() {
+		result.MarginRatio = decimal.NewFromInt(1)
+	}
+
+	for i := range result.Positions {
+		pr := &result.Positions[i]
+		cr := c.contracts[pr.ContractID]
+		// Equity and maintenance margin excluding this position's value
+		otherEquity := result.TotalEquity.Sub(pr.Value)
+		otherMargin := result.MaintenanceMargin.Sub(pr.MaintenanceMargin)
+		pr.LiquidationPrice = liquidationPrice(cr, pr.Size, pr.Tier, otherEquity, otherMargin)
+		pr.BankruptcyPrice = nonNegative(otherEquity.Neg().Div(pr.Size))
+	}
+	return result, nil
+}
+
+// liquidationPrice solves otherEquity + size*P = otherMargin + |size|*P*rate for P,
+// re-evaluating the tier at the solution until it is stable
+func liquidationPrice(cr contractRisk, size decimal.Decimal, tier Tier, otherEquity, otherMargin decimal.Decimal) decimal.Decimal {
+	sign := decimal.NewFromInt(1)
+	if size.IsNegative() {
+		sign = sign.Neg()
+	}
+
+	var price decimal.Decimal
+	for i := 0; i < liquidationIterations; i++ {
+		denominator := size.Mul(decimal.NewFromInt(1).Sub(sign.Mul(tier.MaintenanceMarginRate)))
+		if denominator.IsZero() {
+			return decimal.Zero
+		}
+		price = nonNegative(otherMargin.Sub(otherEquity).Div(denominator))
+		next := cr.tierFor(size.Mul(price).Abs())
+		if next.Tier == tier.Tier {
+			break
+		}
+		tier = next
+	}
+	return price
+}
+
+// nonNegative clamps negative prices, which cannot be reached, to zero
+func nonNegative(price decimal.Decimal) decimal.Decimal {
+	if price.IsNegative() {
+		return decimal.Zero
+	}
+	return price
+}
+
+// Order is a hypothetical order for WhatIf
+type Order struct {
+	ContractID string
+	// Side is order.OrderSideBuy or order.OrderSideSell
+	Side  string
+	Size  decimal.Decimal
+	Price decimal.Decimal
+	// FeeRate is charged on the filled value
+	FeeRate decimal.Decimal
+}
+
+// WhatIfResult compares an account's risk before and after a hypothetical fill
+type WhatIfResult struct {
+	Before *AccountRisk
+	After  *AccountRisk
+	// InitialMarginChange is the change in initial margin caused by the fill
+	InitialMarginChange decimal.Decimal
+	// Fee is the fee charged for the fill
+	Fee decimal.Decimal
+	// Allowed reports whether the account keeps a non-negative available balance
+	// and stays clear of liquidation after the fill
+	Allowed bool
+}
+
+// WhatIf evaluates the account as if order were completely filled at its price
+func (c *Calculator) WhatIf(acc Account, o Order) (*WhatIfResult, error) {
+	if !o.Size.IsPositive() {
+		return nil, fmt.Errorf("order size must be positive")
+	}
+	if !o.Price.IsPositive() {
+		return nil, fmt.Errorf("order price must be positive")
+	}
+
+	var delta decimal.Decimal
+	switch o.Side {
+	case order.OrderSideBuy:
+		delta = o.Size
+	case order.OrderSideSell:
+		delta = o.Size.Neg()
+	default:
+		return nil, fmt.Errorf("invalid order side: %s", o.Side)
+	}
+
+	before, err := c.Evaluate(acc)
+	if err != nil {
+		return nil, err
+	}
+
+	fee := o.Size.Mul(o.Price).Mul(o.FeeRate)
+	after := ApplyFill(acc, o.ContractID, delta, o.Price, fee)
+	afterRisk, err := c.Evaluate(after)
+	if err != nil {
+		return nil, err
+	}
+
+	return &WhatIfResult{
+		Before:              before,
+		After:               afterRisk,
+		InitialMarginChange: afterRisk.InitialMargin.Sub(before.InitialMargin),
+		Fee:                 fee,
+		Allowed:             !afterRisk.AvailableBalance.IsNegative() && !afterRisk.Liquidatable(),
+	}, nil
+}
+
+// ApplyFill returns a copy of acc with a fill of sizeDelta (positive buys) at price
+// applied, charging fee to collateral
+func ApplyFill(acc Account, contractID string, sizeDelta, price, fee decimal.Decimal) Account {
+	positions := make([]Position, 0, len(acc.Positions)+1)
+	found := false
+	for _, position := range acc.Positions {
+		if position.ContractID == contractID {
+			position = fillPosition(position, sizeDelta, price)
+			found = true
+		}
+		positions = append(positions, position)
+	}
+	if !found {
+		positions = append(positions, fillPosition(Position{ContractID: contractID}, sizeDelta, price))
+	}
+
+	acc.Positions = positions
+	acc.Collateral = acc.Collateral.Sub(sizeDelta.Mul(price)).Sub(fee)
+	return acc
+}
+
+// fillPosition updates size and open value. Reductions keep the average open price,
+// flips reopen the remainder at the fill price.
+func fillPosition(position Position, sizeDelta, price decimal.Decimal) Position {
+	newSize := position.Size.Add(sizeDelta)
+	switch {
+	case position.Size.IsZero() || position.Size.Sign() == sizeDelta.Sign():
+		position.OpenValue = position.OpenValue.Add(sizeDelta.Mul(price))
+	case newSize.IsZero():
+		position.OpenValue = decimal.Zero
+	case newSize.Sign() == position.Size.Sign():
+		position.OpenValue = position.OpenValue.Mul(newSize).Div(position.Size)
+	default:
+		position.OpenValue = newSize.Mul(price)
+	}
+	position.Size = newSize
+	return position
+}
+
+// AccountFromAsset builds an Account for a collateral coin from a GetAccountAssetData
+// response. It fails if any amount the account is built from is malformed.
+func AccountFromAsset(data account.AccountAssetData, coinID string) (Account, error) {
+	acc := Account{
+		OraclePrices: make(map[string]decimal.Decimal, len(data.OraclePriceList)),
+		Leverage:     make(map[string]decimal.Decimal),
+	}
+	var firstErr error
+	value := func(d decimal.Decimal, err error) decimal.Decimal {
+		if err != nil && firstErr == nil {
+			firstErr = err
+		}
+		return d
+	}
+
+	if collateral, ok := data.Collateral(coinID); ok {
+		acc.Collateral = value(collateral.AmountDecimal())
+	}
+	if asset, ok := data.CollateralAsset(coinID); ok {
+		acc.PendingWithdraw = value(asset.PendingWithdrawAmountDecimal())
+		acc.PendingTransferOut = value(asset.PendingTransferOutAmountDecimal())
+		acc.OrderFrozen = value(asset.OrderFrozenAmountDecimal())
+	}
+	for _, position := range data.PositionList {
+		if position.CoinID != "" && position.CoinID != coinID {
+			continue
+		}
+		acc.Positions = append(acc.Positions, Position{
+			ContractID: position.ContractID,
+			Size:       value(position.OpenSizeDecimal()),
+			OpenValue:  value(position.OpenValueDecimal()),
+		})
+	}
+	for _, price := range data.OraclePriceList {
+		acc.OraclePrices[price.ContractID] = value(price.PriceValueDecimal())
+	}
+	if data.Account != nil {
+		settings, err := data.Account.LeverageSettings()
+		if err != nil && firstErr == nil {
+			firstErr = err
+		}
+		acc.DefaultLeverage = settings.Default
+		for contractID, leverage := range settings.Contracts {
+			acc.Leverage[contractID] = leverage
+		}
+	}
+	if firstErr != nil {
+		return Account{}, fmt.Errorf("invalid account asset: %w", firstErr)
+	}
+	return acc, nil
+}
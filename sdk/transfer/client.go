@@ -11,6 +11,7 @@ import (
 
 	"github.com/coin-quant/go-edgex/sdk/internal"
 	metadatapkg "github.com/coin-quant/go-edgex/sdk/metadata"
+	"github.com/coin-quant/go-edgex/sdk/policy"
 	"github.com/shopspring/decimal"
 )
 
@@ -94,6 +95,56 @@ func (c *Client) GetTransferInById(ctx context.Context, params GetTransferInById
 	return &result, nil
 }
 
+// GetActiveTransferOut gets a page of transfer out records
+func (c *Client) GetActiveTransferOut(ctx context.Context, params GetActiveTransferOutParams) (*ResultPageDataTransferOut, error) {
+	url := fmt.Sprintf("%s/api/v1/private/transfer/getActiveTransferOut", c.Client.GetBaseURL())
+	queryParams := map[string]string{
+		"accountId": strconv.FormatInt(c.Client.GetAccountID(), 10),
+		"size":      strconv.FormatInt(int64(params.Size), 10),
+	}
+
+	if params.OffsetData != "" {
+		queryParams["offsetData"] = params.OffsetData
+	}
+	if len(params.FilterCoinIdList) > 0 {
+		queryParams["filterCoinIdList"] = internal.JoinStrings(params.FilterCoinIdList)
+	}
+	if len(params.FilterStatusList) > 0 {
+		queryParams["filterStatusList"] = internal.JoinStrings(params.FilterStatusList)
+	}
+	if len(params.FilterTransferReasonList) > 0 {
+		queryParams["filterTransferReasonList"] = internal.JoinStrings(params.FilterTransferReasonList)
+	}
+	if params.FilterStartCreatedTime > 0 {
+		queryParams["filterStartCreatedTimeInclusive"] = strconv.FormatInt(params.FilterStartCreatedTime, 10)
+	}
+	if params.FilterEndCreatedTime > 0 {
+		queryParams["filterEndCreatedTimeExclusive"] = strconv.FormatInt(params.FilterEndCreatedTime, 10)
+	}
+
+	resp, err := c.Client.HttpRequest(url, "GET", nil, queryParams)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get active transfer out: %w", err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read response body: %w", err)
+	}
+
+	var result ResultPageDataTransferOut
+	if err := json.Unmarshal(body, &result); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal response: %w", err)
+	}
+
+	if result.Code != "SUCCESS" {
+		return nil, fmt.Errorf("request failed with code: %s", result.Code)
+	}
+
+	return &result, nil
+}
+
 // GetWithdrawAvailableAmount gets the available withdrawal amount
 func (c *Client) GetWithdrawAvailableAmount(ctx context.Context, params GetWithdrawAvailableAmountParams) (*ResultGetTransferOutAvailableAmount, error) {
 	url := fmt.Sprintf("%s/api/v1/private/transfer/getTransferOutAvailableAmount", c.Client.GetBaseURL())
@@ -133,15 +184,24 @@ func (c *Client) CreateTransferOut(ctx context.Context, params *CreateTransferOu
 	if metadata.Global == nil || metadata.Global.StarkExCollateralCoin == nil {
 		return nil, fmt.Errorf("metadata global is nil")
 	}
+	// Transfer the collateral coin unless another coin of the coin list is given
 	coin := metadata.Global.StarkExCollateralCoin
+	if params.CoinId != "" && params.CoinId != coin.CoinId {
+		var err error
+		if coin, err = metadata.FindCoin(params.CoinId); err != nil {
+			return nil, err
+		}
+	}
 	assetID, err := internal.HexToBigInteger(coin.StarkExAssetId)
 	if err != nil {
 		return nil, fmt.Errorf("failed to parse asset ID: %w", err)
 	}
-	fmt.Println(coin.CoinId)
 
 	// Generate client transfer ID if not provided
-	clientTransferId := internal.GetRandomClientId()
+	clientTransferId := params.ClientTransferId
+	if clientTransferId == "" {
+		clientTransferId = internal.GetRandomClientId()
+	}
 
 	// Calculate nonce and expiration time
 	nonce := internal.CalcNonce(clientTransferId)
@@ -160,14 +220,30 @@ func (c *Client) CreateTransferOut(ctx context.Context, params *CreateTransferOu
 		return nil, fmt.Errorf("invalid receiver account ID: %w", err)
 	}
 
-	// Convert amount to protocol format (shift by 6 decimal places)
+	// Convert amount to quantums with the coin's resolution
 	amountDm, err := decimal.NewFromString(params.Amount)
 	if err != nil {
 		return nil, fmt.Errorf("failed to parse amount: %w", err)
 	}
-	amount := amountDm.Shift(6).IntPart()
+	if err := internal.CheckStepSize(amountDm, coin.StepSize); err != nil {
+		return nil, err
+	}
+	amount, err := internal.QuantizeAmount(amountDm, coin.StarkExResolution)
+	if err != nil {
+		return nil, err
+	}
 	maxAmountFee := int64(0)
 
+	if err := c.Client.CheckOutbound(ctx, policy.Request{
+		Kind:              policy.KindTransferOut,
+		CoinID:            coin.CoinId,
+		Amount:            amountDm,
+		ReceiverAccountID: params.ReceiverAccountId,
+		ClientID:          clientTransferId,
+	}); err != nil {
+		return nil, err
+	}
+
 	// Calculate transfer hash and sign it
 	msgHash := internal.CalcTransferHash(
 		assetID,
@@ -189,7 +265,7 @@ func (c *Client) CreateTransferOut(ctx context.Context, params *CreateTransferOu
 	// Build request body
 	body := map[string]interface{}{
 		"accountId":         strconv.FormatInt(c.Client.GetAccountID(), 10),
-		"coinId":            params.CoinId,
+		"coinId":            coin.CoinId,
 		"amount":            params.Amount,
 		"receiverAccountId": params.ReceiverAccountId,
 		"receiverL2Key":     params.ReceiverL2Key,
@@ -217,7 +293,6 @@ func (c *Client) CreateTransferOut(ctx context.Context, params *CreateTransferOu
 	if err != nil {
 		return nil, fmt.Errorf("failed to read response body: %w", err)
 	}
-	fmt.Println(string(respBody))
 
 	var result ResultCreateTransferOut
 	if err := json.Unmarshal(respBody, &result); err != nil {
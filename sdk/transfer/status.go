@@ -0,0 +1,133 @@
+package transfer
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/coin-quant/go-edgex/sdk/internal"
+)
+
+// Transfer statuses. A transfer is PENDING_ while it is checked and censored,
+// SUCCESS_ once accepted and FAILED_ once rejected. An L2 reject is only final once
+// its batch was verified.
+const (
+	StatusPendingChecking        = "PENDING_CHECKING"
+	StatusPendingCensoring       = "PENDING_CENSORING"
+	StatusSuccessCensorSuccess   = "SUCCESS_CENSOR_SUCCESS"
+	StatusSuccessL2Approved      = "SUCCESS_L2_APPROVED"
+	StatusFailedCheckInvalid     = "FAILED_CHECK_INVALID"
+	StatusFailedCensorFailure    = "FAILED_CENSOR_FAILURE"
+	StatusFailedL2Reject         = "FAILED_L2_REJECT"
+	StatusFailedL2RejectApproved = "FAILED_L2_REJECT_APPROVED"
+)
+
+// defaultPollInterval is the interval used by WaitTransferOut when none is given
+const defaultPollInterval = 2 * time.Second
+
+// findPageSize is the page size used when searching transfers by client ID
+const findPageSize = 100
+
+// IsFailedStatus reports whether a transfer status is a failure
+func IsFailedStatus(status string) bool {
+	return strings.HasPrefix(status, "FAILED_")
+}
+
+// IsTerminalStatus reports whether a transfer status is final. A transfer is final
+// once it failed or passed censoring; with waitForL2 it is only final once it was
+// also approved on L2. An L2 reject is never final before it was verified.
+func IsTerminalStatus(status string, waitForL2 bool) bool {
+	if status == StatusFailedL2Reject {
+		return false
+	}
+	if !waitForL2 {
+		return IsFailedStatus(status) || strings.HasPrefix(status, "SUCCESS_")
+	}
+	return IsFailedStatus(status) || status == StatusSuccessL2Approved
+}
+
+// FailReason returns the censor or L2 reject reason of a failed transfer
+func (t *TransferOut) FailReason() string {
+	var reasons []string
+	if t.CensorFailCode != nil && *t.CensorFailCode != "" {
+		reasons = append(reasons, fmt.Sprintf("censor %s: %s", *t.CensorFailCode, internal.StringValue(t.CensorFailReason)))
+	}
+	if t.L2RejectCode != nil && *t.L2RejectCode != "" {
+		reasons = append(reasons, fmt.Sprintf("l2 reject %s: %s", *t.L2RejectCode, internal.StringValue(t.L2RejectReason)))
+	}
+	return strings.Join(reasons, ", ")
+}
+
+// WaitParams represents parameters for WaitTransferOut
+type WaitParams struct {
+	// PollInterval defaults to 2 seconds
+	PollInterval time.Duration
+	// WaitForL2 waits for L2 approval instead of returning once censoring passed
+	WaitForL2 bool
+}
+
+// WaitTransferOut polls a transfer out record until it reaches a terminal status or
+// ctx is done
+func (c *Client) WaitTransferOut(ctx context.Context, transferOutId string, params WaitParams) (*TransferOut, error) {
+	interval := params.PollInterval
+	if interval <= 0 {
+		interval = defaultPollInterval
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		resp, err := c.GetTransferOutById(ctx, GetTransferOutByIdParams{TransferId: transferOutId})
+		if err != nil {
+			return nil, err
+		}
+		if len(resp.Data) > 0 {
+			out := &resp.Data[0]
+			if IsTerminalStatus(internal.StringValue(out.Status), params.WaitForL2) {
+				return out, nil
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil, fmt.Errorf("transfer out %s not final: %w", transferOutId, ctx.Err())
+		case <-ticker.C:
+		}
+	}
+}
+
+// FindTransferOutByClientId searches the transfer out records of a coin created since
+// the given time for one with the client transfer ID. It returns nil if none is found.
+func (c *Client) FindTransferOutByClientId(ctx context.Context, clientTransferId, coinId string, since time.Time) (*TransferOut, error) {
+	params := GetActiveTransferOutParams{
+		Size:                   findPageSize,
+		FilterStartCreatedTime: since.UnixMilli(),
+	}
+	if coinId != "" {
+		params.FilterCoinIdList = []string{coinId}
+	}
+
+	for {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		resp, err := c.GetActiveTransferOut(ctx, params)
+		if err != nil {
+			return nil, err
+		}
+		if resp.Data == nil {
+			return nil, nil
+		}
+		for i := range resp.Data.DataList {
+			if internal.StringValue(resp.Data.DataList[i].ClientTransferId) == clientTransferId {
+				return &resp.Data.DataList[i], nil
+			}
+		}
+		next := resp.Data.NextPageOffsetData
+		if next == nil || *next == "" || len(resp.Data.DataList) == 0 {
+			return nil, nil
+		}
+		params.OffsetData = *next
+	}
+}
@@ -114,6 +114,7 @@ type GetTransferAvailableAmount struct {
 
 // CreateTransferOut represents the result of creating a transfer out
 type CreateTransferOut struct {
+	TransferOutId                *string      `json:"transferOutId,omitempty"`
 	Id                           *string      `json:"id,omitempty"`
 	UserId                       *string      `json:"userId,omitempty"`
 	AccountId                    *string      `json:"accountId,omitempty"`
@@ -156,6 +157,19 @@ type ResultListTransferIn struct {
 	ErrorMsg string       `json:"msg"`
 }
 
+// PageDataTransferOut represents a page of transfer out records
+type PageDataTransferOut struct {
+	DataList           []TransferOut `json:"dataList"`
+	NextPageOffsetData *string       `json:"nextPageOffsetData,omitempty"`
+}
+
+// ResultPageDataTransferOut represents paginated transfer out records
+type ResultPageDataTransferOut struct {
+	Code     string               `json:"code"`
+	Data     *PageDataTransferOut `json:"data"`
+	ErrorMsg string               `json:"msg"`
+}
+
 // ResultGetTransferOutAvailableAmount represents available transfer out amount
 type ResultGetTransferOutAvailableAmount struct {
 	Code     string                      `json:"code"`
@@ -182,6 +196,17 @@ type GetTransferInByIdParams struct {
 	TransferId string
 }
 
+// GetActiveTransferOutParams represents parameters for GetActiveTransferOut
+type GetActiveTransferOutParams struct {
+	Size                     int32
+	OffsetData               string
+	FilterCoinIdList         []string
+	FilterStatusList         []string
+	FilterTransferReasonList []string
+	FilterStartCreatedTime   int64
+	FilterEndCreatedTime     int64
+}
+
 // GetWithdrawAvailableAmountParams represents parameters for GetWithdrawAvailableAmount
 type GetWithdrawAvailableAmountParams struct {
 	CoinId string
@@ -197,4 +222,6 @@ type CreateTransferOutParams struct {
 	ExpireTime        time.Time
 	ExtraType         *string
 	ExtraDataJson     *string
+	// ClientTransferId makes the transfer idempotent, a random ID is used if empty
+	ClientTransferId string
 }
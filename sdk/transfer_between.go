@@ -0,0 +1,202 @@
+package sdk
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/coin-quant/go-edgex/sdk/internal"
+	"github.com/coin-quant/go-edgex/sdk/transfer"
+	"github.com/shopspring/decimal"
+)
+
+// defaultTransferLookback is how far back an existing transfer with the same client
+// transfer ID is searched for
+const defaultTransferLookback = 24 * time.Hour
+
+// InternalTransferParams represents the parameters for transferring collateral to
+// another account of ours
+type InternalTransferParams struct {
+	// CoinID defaults to the collateral coin
+	CoinID string
+	Amount decimal.Decimal
+	// ClientTransferID is required and makes the transfer idempotent: if a transfer
+	// with the same ID already exists it is tracked instead of creating a new one.
+	// Reusing an ID for a different receiver, coin or amount is an error.
+	ClientTransferID string
+	// Lookback bounds the search for an existing transfer, defaults to 24 hours
+	Lookback time.Duration
+	// PollInterval defaults to 2 seconds
+	PollInterval time.Duration
+	// SkipL2Wait returns once the transfer passed censoring instead of waiting for
+	// its L2 approval. Succeeded then only means that censoring passed.
+	SkipL2Wait bool
+}
+
+// InternalTransfer is the final state of a transfer between two accounts
+type InternalTransfer struct {
+	ClientTransferID string
+	TransferOutID    string
+	TransferInID     string
+	Status           string
+	Succeeded        bool
+	CensorFailCode   string
+	CensorFailReason string
+	L2RejectCode     string
+	L2RejectReason   string
+	TransferOut      *transfer.TransferOut
+	// TransferIn is nil if the receiver side could not be read
+	TransferIn *transfer.TransferIn
+}
+
+// TransferToAccount transfers collateral to the account of receiver and waits until the
+// transfer is approved on L2 or fails. The receiver's l2Key is looked up through its own
+// client. A failed transfer is returned together with an error carrying its reasons.
+func (c *Client) TransferToAccount(ctx context.Context, receiver *Client, params InternalTransferParams) (*InternalTransfer, error) {
+	if params.ClientTransferID == "" {
+		return nil, fmt.Errorf("client transfer id is required")
+	}
+	if !params.Amount.IsPositive() {
+		return nil, fmt.Errorf("transfer amount must be positive: %s", params.Amount)
+	}
+	if receiver.GetAccountID() == c.GetAccountID() {
+		return nil, fmt.Errorf("cannot transfer to the same account")
+	}
+
+	coinID := params.CoinID
+	if coinID == "" {
+		metadataResp, err := c.GetMetaData(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get metadata: %w", err)
+		}
+		if metadataResp.Data.Global == nil || metadataResp.Data.Global.StarkExCollateralCoin == nil {
+			return nil, fmt.Errorf("metadata global is nil")
+		}
+		coinID = metadataResp.Data.Global.StarkExCollateralCoin.CoinId
+	}
+	lookback := params.Lookback
+	if lookback <= 0 {
+		lookback = defaultTransferLookback
+	}
+	since := time.Now().Add(-lookback)
+
+	out, err := c.Transfer.FindTransferOutByClientId(ctx, params.ClientTransferID, coinID, since)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find existing transfer: %w", err)
+	}
+
+	transferOutID := ""
+	if out != nil {
+		if err := checkSameTransfer(out, receiver.GetAccountID(), coinID, params.Amount); err != nil {
+			return nil, err
+		}
+		transferOutID = internal.StringValue(out.Id)
+	} else {
+		receiverAccount, err := receiver.GetAccountByID(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get receiver account: %w", err)
+		}
+		if receiverAccount.Data == nil || receiverAccount.Data.L2Key == "" {
+			return nil, fmt.Errorf("receiver account has no l2 key")
+		}
+
+		resp, createErr := c.CreateTransferOut(ctx, &transfer.CreateTransferOutParams{
+			CoinId:            coinID,
+			Amount:            params.Amount.String(),
+			ReceiverAccountId: strconv.FormatInt(receiver.GetAccountID(), 10),
+			ReceiverL2Key:     receiverAccount.Data.L2Key,
+			TransferReason:    transfer.USER_TRANSFER.String(),
+			ExpireTime:        time.Now(),
+			ClientTransferId:  params.ClientTransferID,
+		})
+		switch {
+		case createErr == nil && resp.Data != nil && resp.Data.TransferOutId != nil:
+			transferOutID = *resp.Data.TransferOutId
+		default:
+			// The request may have reached the server, look the transfer up before failing
+			out, err := c.Transfer.FindTransferOutByClientId(ctx, params.ClientTransferID, coinID, since)
+			if err != nil || out == nil {
+				if createErr == nil {
+					createErr = fmt.Errorf("no transfer out id in response")
+				}
+				return nil, fmt.Errorf("failed to create transfer out: %w", createErr)
+			}
+			if err := checkSameTransfer(out, receiver.GetAccountID(), coinID, params.Amount); err != nil {
+				return nil, err
+			}
+			transferOutID = internal.StringValue(out.Id)
+		}
+	}
+
+	out, err = c.Transfer.WaitTransferOut(ctx, transferOutID, transfer.WaitParams{
+		PollInterval: params.PollInterval,
+		WaitForL2:    !params.SkipL2Wait,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to track transfer out: %w", err)
+	}
+
+	result := newInternalTransfer(params.ClientTransferID, out)
+	if result.TransferInID != "" {
+		resp, err := receiver.GetTransferInById(ctx, transfer.GetTransferInByIdParams{TransferId: result.TransferInID})
+		if err == nil && len(resp.Data) > 0 {
+			result.TransferIn = &resp.Data[0]
+		}
+	}
+	if !result.Succeeded {
+		return result, fmt.Errorf("transfer %s failed with status %s: %s", transferOutID, result.Status, out.FailReason())
+	}
+	return result, nil
+}
+
+// checkSameTransfer makes sure an existing transfer found by client transfer ID is
+// the one requested, so that a reused ID is not reported as this transfer's result
+func checkSameTransfer(out *transfer.TransferOut, receiverAccountID int64, coinID string, amount decimal.Decimal) error {
+	id := internal.StringValue(out.ClientTransferId)
+	if receiver := internal.StringValue(out.ReceiverAccountId); receiver != strconv.FormatInt(receiverAccountID, 10) {
+		return fmt.Errorf("client transfer id %s already used for a transfer to account %s", id, receiver)
+	}
+	if coin := internal.StringValue(out.CoinId); coin != coinID {
+		return fmt.Errorf("client transfer id %s already used for a transfer of coin %s", id, coin)
+	}
+	existing, err := decimal.NewFromString(internal.StringValue(out.Amount))
+	if err != nil {
+		return fmt.Errorf("invalid amount of existing transfer %s: %w", internal.StringValue(out.Id), err)
+	}
+	if !existing.Equal(amount) {
+		return fmt.Errorf("client transfer id %s already used for a transfer of %s", id, existing)
+	}
+	return nil
+}
+
+// newInternalTransfer builds the result of a transfer from its transfer out record
+func newInternalTransfer(clientTransferID string, out *transfer.TransferOut) *InternalTransfer {
+	result := &InternalTransfer{
+		ClientTransferID: clientTransferID,
+		TransferOut:      out,
+		TransferOutID:    internal.StringValue(out.Id),
+		TransferInID:     internal.StringValue(out.ReceiverTransferInId),
+		Status:           internal.StringValue(out.Status),
+		CensorFailCode:   internal.StringValue(out.CensorFailCode),
+		CensorFailReason: internal.StringValue(out.CensorFailReason),
+		L2RejectCode:     internal.StringValue(out.L2RejectCode),
+		L2RejectReason:   internal.StringValue(out.L2RejectReason),
+	}
+	result.Succeeded = !transfer.IsFailedStatus(result.Status)
+	return result
+}
+
+// TransferBetweenAccounts transfers collateral between two named accounts and waits
+// for the transfer to complete
+func (m *MultiAccountClient) TransferBetweenAccounts(ctx context.Context, from, to string, params InternalTransferParams) (*InternalTransfer, error) {
+	sender, err := m.Account(from)
+	if err != nil {
+		return nil, err
+	}
+	receiver, err := m.Account(to)
+	if err != nil {
+		return nil, err
+	}
+	return sender.TransferToAccount(ctx, receiver, params)
+}
@@ -0,0 +1,281 @@
+package withdrawal
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/coin-quant/go-edgex/sdk/asset"
+	"github.com/coin-quant/go-edgex/sdk/internal"
+	"github.com/coin-quant/go-edgex/sdk/metadata"
+	"github.com/coin-quant/go-edgex/sdk/transfer"
+	"github.com/shopspring/decimal"
+)
+
+// speedRank orders the kinds by payout time. A normal withdrawal waits for its L2
+// batch to be proven on L1 and must then be claimed; fast and cross withdrawals are
+// paid out by a liquidity provider once the L2 transfer is accepted.
+var speedRank = map[Kind]int{
+	KindFast:   0,
+	KindCross:  0,
+	KindNormal: 1,
+}
+
+// PlanParams represents the withdrawal to plan
+type PlanParams struct {
+	// CoinID defaults to the collateral coin
+	CoinID string
+	Amount decimal.Decimal
+	// Address is the destination address, used to report the claimable amount
+	Address string
+	// ChainID is the destination chain, defaults to the StarkEx chain
+	ChainID string
+}
+
+// Route is one way of carrying out a withdrawal
+type Route struct {
+	Kind    Kind
+	ChainID string
+	// Viable is false if the route cannot carry the amount, Reason tells why
+	Viable bool
+	Reason string
+	Fee    decimal.Decimal
+	// Debit is the amount leaving the account, the amount plus the fee
+	Debit decimal.Decimal
+	// Receive is the amount paid out at the destination
+	Receive   decimal.Decimal
+	MinAmount decimal.Decimal
+	// MaxAmount is zero if the route has no maximum
+	MaxAmount decimal.Decimal
+	// Available is the account balance the route can draw from
+	Available decimal.Decimal
+}
+
+// Plan holds every route of a withdrawal and the recommended ones
+type Plan struct {
+	CoinID  string
+	ChainID string
+	Amount  decimal.Decimal
+	Routes  []Route
+	// Cheapest is the viable route with the lowest fee, the fastest on a tie
+	Cheapest *Route
+	// Fastest is the viable route paying out first, the cheapest on a tie
+	Fastest *Route
+	// Claimable is the amount of earlier normal withdrawals waiting to be claimed on
+	// L1 at Address
+	Claimable decimal.Decimal
+}
+
+// Planner compares normal, fast and cross withdrawal routes
+type Planner struct {
+	asset    *asset.Client
+	transfer *transfer.Client
+}
+
+// NewPlanner creates a new Planner
+func NewPlanner(assetClient *asset.Client, transferClient *transfer.Client) *Planner {
+	return &Planner{
+		asset:    assetClient,
+		transfer: transferClient,
+	}
+}
+
+// Plan queries the available amounts, limits and fees of every route to the
+// destination and recommends the cheapest and the fastest viable one. Routes that
+// cannot be quoted are listed as not viable.
+func (p *Planner) Plan(ctx context.Context, params PlanParams, md *metadata.MetaData) (*Plan, error) {
+	if md == nil || md.Global == nil {
+		return nil, fmt.Errorf("metadata global is nil")
+	}
+	if !params.Amount.IsPositive() {
+		return nil, fmt.Errorf("amount must be positive: %s", params.Amount)
+	}
+	coinID := params.CoinID
+	if coinID == "" && md.Global.StarkExCollateralCoin != nil {
+		coinID = md.Global.StarkExCollateralCoin.CoinId
+	}
+	coin, err := md.FindCoin(coinID)
+	if err != nil {
+		return nil, err
+	}
+	chainID := params.ChainID
+	if chainID == "" {
+		chainID = md.Global.StarkExChainId
+	}
+
+	normalResp, err := p.asset.GetWithdrawAvailableAmount(ctx, asset.GetWithdrawAvailableAmountParams{CoinId: coinID})
+	if err != nil {
+		return nil, err
+	}
+	transferResp, err := p.transfer.GetWithdrawAvailableAmount(ctx, transfer.GetWithdrawAvailableAmountParams{CoinId: coinID})
+	if err != nil {
+		return nil, err
+	}
+	plan := &Plan{CoinID: coinID, ChainID: chainID, Amount: params.Amount}
+	if params.Address != "" {
+		claimResp, err := p.asset.GetNormalWithdrawableAmount(ctx, asset.GetNormalWithdrawableAmountParams{Address: params.Address})
+		if err != nil {
+			return nil, err
+		}
+		if claimResp.Data != nil {
+			plan.Claimable = decimalValue(claimResp.Data.Amount)
+		}
+	}
+
+	var normalAvailable, transferAvailable decimal.Decimal
+	if normalResp.Data != nil {
+		normalAvailable = decimalValue(normalResp.Data.AvailableAmount)
+	}
+	if transferResp.Data != nil {
+		transferAvailable = decimalValue(transferResp.Data.AvailableAmount)
+	}
+
+	plan.Routes = []Route{
+		p.normalRoute(params.Amount, coin, chainID, normalAvailable, md),
+		p.fastRoute(ctx, params.Amount, coin, chainID, transferAvailable, md),
+		p.crossRoute(ctx, params.Amount, coin, chainID, transferAvailable, md),
+	}
+	for i := range plan.Routes {
+		route := &plan.Routes[i]
+		if !route.Viable {
+			continue
+		}
+		if plan.Cheapest == nil || cheaper(route, plan.Cheapest) {
+			plan.Cheapest = route
+		}
+		if plan.Fastest == nil || faster(route, plan.Fastest) {
+			plan.Fastest = route
+		}
+	}
+	return plan, nil
+}
+
+// normalRoute quotes a normal withdrawal, which pays out on the StarkEx chain only
+func (p *Planner) normalRoute(amount decimal.Decimal, coin *metadata.Coin, chainID string, available decimal.Decimal, md *metadata.MetaData) Route {
+	route := Route{Kind: KindNormal, ChainID: chainID, Available: available}
+	if chainID != md.Global.StarkExChainId {
+		route.Reason = fmt.Sprintf("normal withdrawals pay out on chain %s only", md.Global.StarkExChainId)
+		return route
+	}
+	if err := internal.CheckStepSize(amount, coin.StepSize); err != nil {
+		route.Reason = err.Error()
+		return route
+	}
+	return finishRoute(route, amount, decimal.Zero)
+}
+
+// fastRoute quotes a fast withdrawal from its sign info
+func (p *Planner) fastRoute(ctx context.Context, amount decimal.Decimal, coin *metadata.Coin, chainID string, available decimal.Decimal, md *metadata.MetaData) Route {
+	route := Route{Kind: KindFast, ChainID: chainID, Available: available}
+	chain, token, err := md.FindChainToken(chainID, coin)
+	if err != nil {
+		route.Reason = err.Error()
+		return route
+	}
+	if !chain.AllowWithdraw || !token.WithdrawEnable {
+		route.Reason = fmt.Sprintf("withdrawal of %s disabled on chain: %s", coin.CoinName, chain.Chain)
+		return route
+	}
+	if err := multiChainLimits(&route, amount, coin, md); err != nil {
+		route.Reason = err.Error()
+		return route
+	}
+	resp, err := p.asset.GetFastWithdrawSignInfo(ctx, asset.GetFastWithdrawSignInfoParams{ChainId: chainID, Amount: amount.String()})
+	if err != nil {
+		route.Reason = err.Error()
+		return route
+	}
+	if resp.Data == nil {
+		route.Reason = "fast withdraw sign info is nil"
+		return route
+	}
+	maxAmount := internal.FirstNonEmpty(internal.StringValue(resp.Data.FastWithdrawMaxAmount), md.Global.FastWithdrawMaxAmount)
+	route.MaxAmount = minPositive(route.MaxAmount, decimalValue(&maxAmount))
+	return finishRoute(route, amount, decimalValue(resp.Data.Fee))
+}
+
+// crossRoute quotes a cross withdrawal from its sign info
+func (p *Planner) crossRoute(ctx context.Context, amount decimal.Decimal, coin *metadata.Coin, chainID string, available decimal.Decimal, md *metadata.MetaData) Route {
+	route := Route{Kind: KindCross, ChainID: chainID, Available: available}
+	chain, token, err := md.FindChainToken(chainID, coin)
+	if err != nil {
+		route.Reason = err.Error()
+		return route
+	}
+	if !chain.AllowWithdraw || !token.WithdrawEnable {
+		route.Reason = fmt.Sprintf("withdrawal of %s disabled on chain: %s", coin.CoinName, chain.Chain)
+		return route
+	}
+	if err := multiChainLimits(&route, amount, coin, md); err != nil {
+		route.Reason = err.Error()
+		return route
+	}
+	resp, err := p.asset.GetCrossWithdrawSignInfo(ctx, asset.GetCrossWithdrawSignInfoParams{ChainId: chainID, Amount: amount.String()})
+	if err != nil {
+		route.Reason = err.Error()
+		return route
+	}
+	if resp.Data == nil {
+		route.Reason = "cross withdraw sign info is nil"
+		return route
+	}
+	route.MaxAmount = minPositive(route.MaxAmount, decimalValue(resp.Data.CrossWithdrawMaxAmount))
+	return finishRoute(route, amount, decimalValue(resp.Data.Fee))
+}
+
+// finishRoute sets the fee and amounts of a route and checks it against its limits
+// and the available balance
+func finishRoute(route Route, amount, fee decimal.Decimal) Route {
+	route.Fee = fee
+	route.Debit = amount.Add(fee)
+	route.Receive = amount
+	switch {
+	case route.MinAmount.IsPositive() && amount.LessThan(route.MinAmount):
+		route.Reason = fmt.Sprintf("amount %s below min %s", amount, route.MinAmount)
+	case route.MaxAmount.IsPositive() && amount.GreaterThan(route.MaxAmount):
+		route.Reason = fmt.Sprintf("amount %s above max %s", amount, route.MaxAmount)
+	case route.Debit.GreaterThan(route.Available):
+		route.Reason = fmt.Sprintf("debit %s above available %s", route.Debit, route.Available)
+	default:
+		route.Viable = true
+	}
+	return route
+}
+
+// multiChainLimits sets the multi chain withdrawal bounds and step size of a route
+func multiChainLimits(route *Route, amount decimal.Decimal, coin *metadata.Coin, md *metadata.MetaData) error {
+	if err := internal.CheckStepSize(amount, coin.StepSize); err != nil {
+		return err
+	}
+	if md.MultiChain != nil {
+		route.MinAmount = decimalValue(&md.MultiChain.MinWithdraw)
+		route.MaxAmount = decimalValue(&md.MultiChain.MaxWithdraw)
+	}
+	return nil
+}
+
+// minPositive returns the lower of two limits, ignoring a zero one
+func minPositive(a, b decimal.Decimal) decimal.Decimal {
+	if !a.IsPositive() {
+		return b
+	}
+	if b.IsPositive() && b.LessThan(a) {
+		return b
+	}
+	return a
+}
+
+// cheaper reports whether a costs less than b, or is faster at the same fee
+func cheaper(a, b *Route) bool {
+	if !a.Fee.Equal(b.Fee) {
+		return a.Fee.LessThan(b.Fee)
+	}
+	return speedRank[a.Kind] < speedRank[b.Kind]
+}
+
+// faster reports whether a pays out before b, or costs less at the same speed
+func faster(a, b *Route) bool {
+	if speedRank[a.Kind] != speedRank[b.Kind] {
+		return speedRank[a.Kind] < speedRank[b.Kind]
+	}
+	return a.Fee.LessThan(b.Fee)
+}
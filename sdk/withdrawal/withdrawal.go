@@ -0,0 +1,595 @@
+// Package withdrawal tracks the lifecycle of normal, fast and cross withdrawals
+// under one normalized status model.
+package withdrawal
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/coin-quant/go-edgex/sdk/asset"
+	"github.com/coin-quant/go-edgex/sdk/internal"
+	"github.com/shopspring/decimal"
+)
+
+// Kind is the kind of a withdrawal
+type Kind string
+
+const (
+	// KindNormal is a withdrawal through the StarkEx contract, claimed on L1 by the user
+	KindNormal Kind = "NORMAL"
+	// KindFast is a withdrawal paid out on L1 by a liquidity provider
+	KindFast Kind = "FAST"
+	// KindCross is a withdrawal paid out on another chain by a liquidity provider
+	KindCross Kind = "CROSS"
+)
+
+// Stage is the normalized status of a withdrawal
+type Stage string
+
+const (
+	// StageSubmitted is a withdrawal waiting to be checked and censored
+	StageSubmitted Stage = "SUBMITTED"
+	// StageCensored is a withdrawal that passed censoring
+	StageCensored Stage = "CENSORED"
+	// StageL2Approved is a withdrawal approved on L2
+	StageL2Approved Stage = "L2_APPROVED"
+	// StageOnChain is a withdrawal being paid out on L1
+	StageOnChain Stage = "ON_CHAIN"
+	// StageCompleted is a withdrawal paid out on L1
+	StageCompleted Stage = "COMPLETED"
+	// StageRejected is a withdrawal that failed at any step
+	StageRejected Stage = "REJECTED"
+)
+
+// DefaultPollInterval is the interval used by WaitForCompletion when none is set
+const DefaultPollInterval = 5 * time.Second
+
+// pendingPageSize is the page size used when listing withdrawals
+const pendingPageSize = 100
+
+// orderKinds are the kinds of the fast and cross withdrawal asset orders
+var orderKinds = map[string]Kind{
+	asset.OrderTypeFastWithdraw:  KindFast,
+	asset.OrderTypeCrossWithdraw: KindCross,
+}
+
+// statusPrefixes are the status prefixes of the withdrawal records of the assets API
+var statusPrefixes = map[Kind]string{
+	KindNormal: "NORMAL_WITHDRAW_",
+	KindFast:   "FAST_WITHDRAW_",
+	KindCross:  "CROSS_WITHDRAW_",
+}
+
+// StageOf maps a status of the withdraw API records, such as those of
+// getActiveWithdraw and the private stream, to its stage. An L2 reject is only
+// final once its batch was verified, so FAILED_L2_REJECT stays censored until
+// FAILED_L2_REJECT_APPROVED arrives.
+func StageOf(status string) Stage {
+	switch {
+	case status == "FAILED_L2_REJECT_APPROVED",
+		status == "FAILED_CENSOR_FAILURE",
+		status == "FAILED_CHECK_INVALID":
+		return StageRejected
+	case status == "FAILED_L2_REJECT":
+		return StageCensored
+	case status == "SUCCESS_L2_APPROVED":
+		return StageL2Approved
+	case strings.HasPrefix(status, "SUCCESS_"):
+		return StageCensored
+	default:
+		return StageSubmitted
+	}
+}
+
+// AssetStageOf maps a status of the assets API records of a kind, such as
+// FAST_WITHDRAW_PENDING_L1_CONFIRMING, to its stage
+func AssetStageOf(kind Kind, status string) Stage {
+	status = strings.TrimPrefix(status, statusPrefixes[kind])
+	switch {
+	case status == "SUCCESS", status == "SUCCESS_L1_COMPLETED":
+		return StageCompleted
+	case strings.HasPrefix(status, "FAILED_"):
+		return StageRejected
+	case status == "PENDING_L2_APPROVING":
+		return StageCensored
+	case strings.HasPrefix(status, "PENDING_L1_"):
+		return StageOnChain
+	case status == "PENDING_RISK_CHECKING",
+		status == "PENDING_TRADE_PROCESSING",
+		status == "PENDING_SUBMIT",
+		status == "SUCCESS_SUBMIT_CENSOR",
+		strings.HasPrefix(status, "PENDING_CHECKING"),
+		strings.HasPrefix(status, "PENDING_CENSOR"):
+		return StageSubmitted
+	default:
+		// UNKNOWN and statuses not known yet are treated as just submitted
+		return StageSubmitted
+	}
+}
+
+// Withdrawal is a withdrawal of any kind
+type Withdrawal struct {
+	Kind     Kind
+	ID       string
+	ClientID string
+	CoinID   string
+	Amount   decimal.Decimal
+	// Fee is the liquidity provider fee of fast and cross withdrawals
+	Fee     decimal.Decimal
+	Address string
+	ChainID string
+	// Status is the raw exchange status
+	Status string
+	Stage  Stage
+	// Reason holds the censor, L2 or L1 reject reasons of a rejected withdrawal
+	Reason string
+	// TransferOutID is the L2 transfer to the liquidity provider of fast and cross withdrawals
+	TransferOutID string
+	CreatedTime   time.Time
+	UpdatedTime   time.Time
+}
+
+// Final reports whether the withdrawal will not change anymore. A normal withdrawal
+// is final once approved on L2 as the L1 claim is made by the user.
+func (w Withdrawal) Final() bool {
+	switch w.Stage {
+	case StageCompleted, StageRejected:
+		return true
+	case StageL2Approved:
+		return w.Kind == KindNormal
+	default:
+		return false
+	}
+}
+
+// Event is emitted when a tracked withdrawal changes stage
+type Event struct {
+	Withdrawal Withdrawal
+	// Previous is empty the first time a withdrawal is seen
+	Previous Stage
+}
+
+// Tracker follows withdrawals over REST and the private stream. It is safe for
+// concurrent use.
+type Tracker struct {
+	client       *asset.Client
+	pollInterval time.Duration
+	mu           sync.RWMutex
+	withdrawals  map[string]*Withdrawal
+	listeners    []func(Event)
+}
+
+// New creates a new Tracker
+func New(client *asset.Client) *Tracker {
+	return &Tracker{
+		client:       client,
+		pollInterval: DefaultPollInterval,
+		withdrawals:  make(map[string]*Withdrawal),
+	}
+}
+
+// SetPollInterval sets the interval WaitForCompletion polls at
+func (t *Tracker) SetPollInterval(interval time.Duration) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if interval > 0 {
+		t.pollInterval = interval
+	}
+}
+
+// OnChange registers a listener called when a withdrawal changes stage. Listeners run
+// on the goroutine that delivered the update and must not block.
+func (t *Tracker) OnChange(listener func(Event)) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.listeners = append(t.listeners, listener)
+}
+
+// Track registers a withdrawal so that it is refreshed by Pending and its kind is
+// known to Get
+func (t *Tracker) Track(kind Kind, id string) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if _, ok := t.withdrawals[id]; !ok {
+		t.withdrawals[id] = &Withdrawal{Kind: kind, ID: id}
+	}
+}
+
+// Get fetches a withdrawal. The kind of an untracked withdrawal is found by looking
+// it up as normal, fast and cross withdrawal in turn.
+func (t *Tracker) Get(ctx context.Context, id string) (*Withdrawal, error) {
+	kinds := []Kind{KindNormal, KindFast, KindCross}
+	t.mu.RLock()
+	if known, ok := t.withdrawals[id]; ok {
+		kinds = []Kind{known.Kind}
+	}
+	t.mu.RUnlock()
+
+	for _, kind := range kinds {
+		w, err := t.fetch(ctx, kind, id)
+		if err != nil {
+			return nil, err
+		}
+		if w != nil {
+			t.update(*w)
+			return w, nil
+		}
+	}
+	return nil, fmt.Errorf("withdrawal not found: %s", id)
+}
+
+// fetch reads a withdrawal of a given kind, it returns nil if there is none
+func (t *Tracker) fetch(ctx context.Context, kind Kind, id string) (*Withdrawal, error) {
+	switch kind {
+	case KindNormal:
+		resp, err := t.client.GetNormalWithdrawById(ctx, asset.GetNormalWithdrawByIdParams{NormalWithdrawIdList: id})
+		if err != nil {
+			return nil, err
+		}
+		if len(resp.Data) == 0 {
+			return nil, nil
+		}
+		w := fromNormal(resp.Data[0])
+		return &w, nil
+	case KindFast:
+		resp, err := t.client.GetFastWithdrawById(ctx, asset.GetFastWithdrawByIdParams{FastWithdrawIdList: id})
+		if err != nil {
+			return nil, err
+		}
+		if len(resp.Data) == 0 {
+			return nil, nil
+		}
+		w := fromFast(resp.Data[0])
+		return &w, nil
+	case KindCross:
+		resp, err := t.client.GetCrossWithdrawById(ctx, asset.GetCrossWithdrawByIdParams{CrossWithdrawIdList: id})
+		if err != nil {
+			return nil, err
+		}
+		if len(resp.Data) == 0 {
+			return nil, nil
+		}
+		w := fromCross(resp.Data[0])
+		return &w, nil
+	default:
+		return nil, fmt.Errorf("unknown withdrawal kind: %s", kind)
+	}
+}
+
+// Pending lists the withdrawals that are not final yet: the normal, fast and cross
+// withdrawals created since the given time, zero for no bound, and every other
+// tracked withdrawal. The result is ordered by creation time.
+func (t *Tracker) Pending(ctx context.Context, since time.Time) ([]Withdrawal, error) {
+	params := asset.GetActiveWithdrawParams{Size: pendingPageSize}
+	if !since.IsZero() {
+		params.FilterStartCreatedTime = since.UnixMilli()
+	}
+	for {
+		resp, err := t.client.GetActiveWithdraw(ctx, params)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list withdrawals: %w", err)
+		}
+		if resp.Data == nil {
+			break
+		}
+		for _, record := range resp.Data.DataList {
+			t.update(fromWithdraw(record))
+		}
+		next := resp.Data.NextPageOffsetData
+		if next == nil || *next == "" || len(resp.Data.DataList) == 0 {
+			break
+		}
+		params.OffsetData = *next
+	}
+
+	// Fast and cross withdrawals are only listed as asset orders, without their
+	// status, so they are tracked here and refreshed below
+	orderParams := asset.GetAllOrdersPageParams{
+		TypeList: asset.OrderTypeFastWithdraw + "," + asset.OrderTypeCrossWithdraw,
+		Size:     strconv.Itoa(pendingPageSize),
+	}
+	if !since.IsZero() {
+		orderParams.StartTime = strconv.FormatInt(since.Unix(), 10)
+	}
+	for {
+		resp, err := t.client.GetAllOrdersPage(ctx, orderParams)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list withdrawals: %w", err)
+		}
+		if resp.Data == nil {
+			break
+		}
+		orders, err := resp.Data.Orders()
+		if err != nil {
+			return nil, fmt.Errorf("failed to list withdrawals: %w", err)
+		}
+		for _, order := range orders {
+			kind, ok := orderKinds[order.GetType()]
+			if ok && order.GetOrderId() != "" {
+				t.Track(kind, order.GetOrderId())
+			}
+		}
+		next := resp.Data.NextPageOffsetData
+		if next == nil || *next == "" || len(orders) == 0 {
+			break
+		}
+		orderParams.OffsetData = *next
+	}
+
+	var refresh []*Withdrawal
+	t.mu.RLock()
+	for _, w := range t.withdrawals {
+		if w.Kind != KindNormal && !w.Final() {
+			refresh = append(refresh, w)
+		}
+	}
+	t.mu.RUnlock()
+	for _, w := range refresh {
+		if _, err := t.Get(ctx, w.ID); err != nil {
+			return nil, fmt.Errorf("failed to refresh withdrawal %s: %w", w.ID, err)
+		}
+	}
+
+	var pending []Withdrawal
+	t.mu.RLock()
+	for _, w := range t.withdrawals {
+		if w.Stage != "" && !w.Final() {
+			pending = append(pending, *w)
+		}
+	}
+	t.mu.RUnlock()
+	sort.Slice(pending, func(i, j int) bool {
+		return pending[i].CreatedTime.Before(pending[j].CreatedTime)
+	})
+	return pending, nil
+}
+
+// WaitForCompletion polls a withdrawal until it is final or ctx is done. A rejected
+// withdrawal is returned together with an error carrying its reason.
+func (t *Tracker) WaitForCompletion(ctx context.Context, id string) (*Withdrawal, error) {
+	t.mu.RLock()
+	interval := t.pollInterval
+	t.mu.RUnlock()
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		w, err := t.Get(ctx, id)
+		if err != nil {
+			return nil, err
+		}
+		if w.Final() {
+			if w.Stage == StageRejected {
+				return w, fmt.Errorf("withdrawal %s rejected with status %s: %s", id, w.Status, w.Reason)
+			}
+			return w, nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return w, fmt.Errorf("withdrawal %s not final: %w", id, ctx.Err())
+		case <-ticker.C:
+		}
+	}
+}
+
+// HandlePrivateMessage is a ws.MessageHandler for private account events. Normal
+// withdrawal updates are applied as they come; fast and cross withdrawals are marked
+// rejected once their transfer to the liquidity provider failed for good.
+func (t *Tracker) HandlePrivateMessage(message []byte) {
+	var event struct {
+		Content struct {
+			Data struct {
+				Withdraw    []asset.Withdraw `json:"withdraw"`
+				TransferOut []struct {
+					Id               string `json:"id"`
+					Status           string `json:"status"`
+					CensorFailCode   string `json:"censorFailCode"`
+					CensorFailReason string `json:"censorFailReason"`
+					L2RejectCode     string `json:"l2RejectCode"`
+					L2RejectReason   string `json:"l2RejectReason"`
+				} `json:"transferOut"`
+			} `json:"data"`
+		} `json:"content"`
+	}
+	if err := json.Unmarshal(message, &event); err != nil {
+		return
+	}
+
+	for _, record := range event.Content.Data.Withdraw {
+		if record.Id != nil {
+			t.update(fromWithdraw(record))
+		}
+	}
+	for _, out := range event.Content.Data.TransferOut {
+		if StageOf(out.Status) != StageRejected {
+			continue
+		}
+		t.mu.RLock()
+		var rejected *Withdrawal
+		for _, w := range t.withdrawals {
+			if w.TransferOutID != "" && w.TransferOutID == out.Id && !w.Final() {
+				copied := *w
+				rejected = &copied
+				break
+			}
+		}
+		t.mu.RUnlock()
+		if rejected == nil {
+			continue
+		}
+		rejected.Stage = StageRejected
+		rejected.Reason = joinReasons(
+			reason("censor", out.CensorFailCode, out.CensorFailReason),
+			reason("l2 reject", out.L2RejectCode, out.L2RejectReason),
+		)
+		t.update(*rejected)
+	}
+}
+
+// update stores a withdrawal and notifies listeners if its stage changed
+func (t *Tracker) update(w Withdrawal) {
+	t.mu.Lock()
+	previous := Stage("")
+	if known, ok := t.withdrawals[w.ID]; ok {
+		previous = known.Stage
+		if w.Kind == "" {
+			w.Kind = known.Kind
+		}
+	}
+	stored := w
+	t.withdrawals[w.ID] = &stored
+	listeners := t.listeners
+	t.mu.Unlock()
+
+	if previous == w.Stage {
+		return
+	}
+	event := Event{Withdrawal: w, Previous: previous}
+	for _, listener := range listeners {
+		listener(event)
+	}
+}
+
+// fromWithdraw converts a normal withdrawal record of the withdraw API
+func fromWithdraw(r asset.Withdraw) Withdrawal {
+	w := Withdrawal{
+		Kind:        KindNormal,
+		ID:          internal.StringValue(r.Id),
+		ClientID:    internal.StringValue(r.ClientWithdrawId),
+		CoinID:      internal.StringValue(r.CoinId),
+		Amount:      decimalValue(r.Amount),
+		Address:     internal.StringValue(r.EthAddress),
+		Status:      internal.StringValue(r.Status),
+		CreatedTime: timeValue(r.CreatedTime),
+		UpdatedTime: timeValue(r.UpdatedTime),
+	}
+	w.Stage = StageOf(w.Status)
+	if w.Stage == StageRejected {
+		w.Reason = joinReasons(
+			reason("censor", internal.StringValue(r.CensorFailCode), internal.StringValue(r.CensorFailReason)),
+			reason("l2 reject", internal.StringValue(r.L2RejectCode), internal.StringValue(r.L2RejectReason)),
+		)
+	}
+	return w
+}
+
+// fromNormal converts a normal withdrawal record of the assets API
+func fromNormal(r asset.CreateNormalWithdraw) Withdrawal {
+	w := Withdrawal{
+		Kind:        KindNormal,
+		ID:          internal.StringValue(r.Id),
+		ClientID:    internal.StringValue(r.ClientWithdrawId),
+		CoinID:      internal.StringValue(r.CoinId),
+		Amount:      decimalValue(r.Amount),
+		Address:     internal.FirstNonEmpty(internal.StringValue(r.EthAddress), internal.StringValue(r.ReceiverAddress)),
+		Status:      internal.StringValue(r.Status),
+		CreatedTime: timeValue(r.CreatedTime),
+		UpdatedTime: timeValue(r.UpdatedTime),
+	}
+	w.Stage = AssetStageOf(KindNormal, w.Status)
+	if w.Stage == StageRejected {
+		w.Reason = joinReasons(
+			reason("censor", internal.StringValue(r.CensorFailCode), internal.StringValue(r.CensorFailReason)),
+			reason("l2 reject", internal.StringValue(r.L2RejectCode), internal.StringValue(r.L2RejectReason)),
+		)
+	}
+	return w
+}
+
+// fromFast converts a fast withdrawal record
+func fromFast(r asset.CreateFastWithdraw) Withdrawal {
+	w := Withdrawal{
+		Kind:          KindFast,
+		ID:            internal.StringValue(r.Id),
+		ClientID:      internal.FirstNonEmpty(internal.StringValue(r.ClientFastWithdrawId), internal.StringValue(r.ClientWithdrawId)),
+		CoinID:        internal.StringValue(r.CoinId),
+		Amount:        decimalValue(r.Amount),
+		Fee:           decimalValue(r.Fee),
+		Address:       internal.FirstNonEmpty(internal.StringValue(r.EthAddress), internal.StringValue(r.ReceiverAddress)),
+		ChainID:       internal.StringValue(r.ChainId),
+		Status:        internal.StringValue(r.Status),
+		TransferOutID: internal.StringValue(r.TransferOutId),
+		CreatedTime:   timeValue(r.CreatedTime),
+		UpdatedTime:   timeValue(r.UpdatedTime),
+	}
+	w.Stage = AssetStageOf(KindFast, w.Status)
+	if w.Stage == StageRejected {
+		w.Reason = joinReasons(
+			reason("censor", internal.StringValue(r.CensorFailCode), internal.StringValue(r.CensorFailReason)),
+			reason("l2 reject", internal.StringValue(r.L2RejectCode), internal.StringValue(r.L2RejectReason)),
+			reason("l1 reject", internal.StringValue(r.L1RejectedReasonCode), internal.StringValue(r.L1RejectedReasonMsg)),
+		)
+	}
+	return w
+}
+
+// fromCross converts a cross withdrawal record
+func fromCross(r asset.CreateCrossWithdraw) Withdrawal {
+	w := Withdrawal{
+		Kind:          KindCross,
+		ID:            internal.StringValue(r.Id),
+		ClientID:      internal.FirstNonEmpty(internal.StringValue(r.ClientCrossWithdrawId), internal.StringValue(r.ClientWithdrawId)),
+		CoinID:        internal.StringValue(r.CoinId),
+		Amount:        decimalValue(r.Amount),
+		Fee:           decimalValue(r.Fee),
+		Address:       internal.FirstNonEmpty(internal.StringValue(r.EthAddress), internal.StringValue(r.ReceiverAddress)),
+		ChainID:       internal.FirstNonEmpty(internal.StringValue(r.ChainId), internal.StringValue(r.ReceiverChainId)),
+		Status:        internal.StringValue(r.Status),
+		TransferOutID: internal.StringValue(r.TransferOutId),
+		CreatedTime:   timeValue(r.CreatedTime),
+		UpdatedTime:   timeValue(r.UpdatedTime),
+	}
+	w.Stage = AssetStageOf(KindCross, w.Status)
+	if w.Stage == StageRejected {
+		w.Reason = joinReasons(
+			reason("censor", internal.StringValue(r.CensorFailCode), internal.StringValue(r.CensorFailReason)),
+			reason("l2 reject", internal.StringValue(r.L2RejectCode), internal.StringValue(r.L2RejectReason)),
+			reason("l1 reject", internal.StringValue(r.L1RejectedReasonCode), internal.StringValue(r.L1RejectedReasonMsg)),
+		)
+	}
+	return w
+}
+
+// reason formats a reject code and message, it is empty if there is neither
+func reason(step, code, msg string) string {
+	if code == "" && msg == "" {
+		return ""
+	}
+	return fmt.Sprintf("%s %s: %s", step, code, msg)
+}
+
+// joinReasons joins the non empty reasons
+func joinReasons(reasons ...string) string {
+	var parts []string
+	for _, r := range reasons {
+		if r != "" {
+			parts = append(parts, r)
+		}
+	}
+	return strings.Join(parts, ", ")
+}
+
+// decimalValue parses an optional decimal, zero if absent or invalid
+func decimalValue(s *string) decimal.Decimal {
+	d, err := decimal.NewFromString(internal.StringValue(s))
+	if err != nil {
+		return decimal.Zero
+	}
+	return d
+}
+
+// timeValue parses an optional millisecond timestamp
+func timeValue(s *string) time.Time {
+	ms, err := strconv.ParseInt(internal.StringValue(s), 10, 64)
+	if err != nil || ms == 0 {
+		return time.Time{}
+	}
+	return time.UnixMilli(ms)
+}
@@ -5,8 +5,10 @@ import (
 	"context"
 	"encoding/hex"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"math/big"
+	"net"
 	"net/http"
 	"strings"
 	"sync"
@@ -19,12 +21,26 @@ import (
 
 var WebsocketDebug = false
 
+// ErrClosed is reported by Err after Close and returned by sends on a closed client
+var ErrClosed = errors.New("websocket client closed")
+
+const (
+	// sendQueueSize is the number of outgoing frames buffered for the writer goroutine
+	sendQueueSize = 256
+	// writeTimeout bounds a single frame write
+	writeTimeout = 10 * time.Second
+	// DefaultCloseTimeout bounds the close handshake performed by Close
+	DefaultCloseTimeout = 5 * time.Second
+)
+
 // Client represents a WebSocket client
 type Client struct {
 	conn              *websocket.Conn
 	url               string
 	mu                sync.RWMutex
 	handlers          map[string]MessageHandler
+	listeners         map[string][]listener
+	nextListenerID    uint64
 	done              chan struct{}
 	pingTicker        *time.Ticker
 	isPrivate         bool
@@ -34,11 +50,35 @@ type Client struct {
 	onDisconnectHooks []func(error)
 	accountID         int64
 	starkPriKey       string
+	heartbeat         HeartbeatConfig
+	health            healthState
+	onStaleHooks      []func(string)
+	startOnce         sync.Once
+	sendQueue         chan outgoingMessage
+	closeOnce         sync.Once
+	terminated        chan struct{}
+	terminateOnce     sync.Once
+	err               error
+	dropErr           error
+	reading           bool
+	recorder          *Recorder
+}
+
+// outgoingMessage is a frame queued for the writer goroutine
+type outgoingMessage struct {
+	data   []byte
+	result chan error
 }
 
 // MessageHandler is a function type for handling WebSocket messages
 type MessageHandler func(message []byte)
 
+// listener is a handler registered with AddHandler
+type listener struct {
+	id      uint64
+	handler MessageHandler
+}
+
 // Message represents a WebSocket message
 type Message struct {
 	Type string          `json:"type"`
@@ -51,16 +91,63 @@ func NewClient(url string, isPrivate bool, accountID int64, starkPriKey string)
 	return &Client{
 		url:           url,
 		handlers:      make(map[string]MessageHandler),
+		listeners:     make(map[string][]listener),
 		done:          make(chan struct{}),
 		isPrivate:     isPrivate,
 		subscriptions: make(map[string]struct{}),
 		accountID:     accountID,
 		starkPriKey:   starkPriKey,
+		heartbeat:     DefaultHeartbeatConfig(),
+		health:        newHealthState(),
+		sendQueue:     make(chan outgoingMessage, sendQueueSize),
+		terminated:    make(chan struct{}),
 	}
 }
 
-// Connect establishes a WebSocket connection
+// Connect establishes a WebSocket connection. A client that stopped for good, see
+// Done, cannot be connected again.
 func (c *Client) Connect(ctx context.Context) error {
+	conn, err := c.dial(ctx)
+	if err != nil {
+		return err
+	}
+
+	c.mu.Lock()
+	if c.isTerminated() {
+		stopErr := c.err
+		c.mu.Unlock()
+		_ = conn.Close()
+		return fmt.Errorf("WebSocket client stopped: %w", stopErr)
+	}
+	if c.isClosed() || c.reading {
+		c.mu.Unlock()
+		_ = conn.Close()
+		return fmt.Errorf("WebSocket client is closed or already connected")
+	}
+	c.conn = conn
+	c.reading = true
+	c.mu.Unlock()
+	c.health.connected(time.Now())
+
+	// Start writing, ping and stale feed monitoring, then message handling
+	c.startOnce.Do(func() {
+		c.pingTicker = time.NewTicker(c.heartbeat.PingInterval)
+		go c.writeMessages()
+		go c.handlePing()
+		go c.monitorHealth()
+	})
+	go c.handleMessages(conn)
+
+	// Call connect hooks
+	for _, hook := range c.onConnectHooks {
+		hook()
+	}
+
+	return nil
+}
+
+// dial opens a new connection, signing the handshake for private connections
+func (c *Client) dial(ctx context.Context) (*websocket.Conn, error) {
 	dialer := websocket.Dialer{}
 	headers := http.Header{}
 
@@ -72,20 +159,20 @@ func (c *Client) Connect(ctx context.Context) error {
 		// Generate signature content
 		path := fmt.Sprintf("/api/v1/private/wsaccountId=%d", c.accountID)
 		signContent := fmt.Sprintf("%d%s%s", timestamp, "GET", path)
-		fmt.Printf("Signing content: %s\n", signContent)
+		if WebsocketDebug {
+			fmt.Printf("Signing content: %s\n", signContent)
+		}
 
 		// Hash the content
 		hash := sha3.NewLegacyKeccak256()
 		hash.Write([]byte(signContent))
 		messageHash := hash.Sum(nil)
-		fmt.Printf("Message hash: %x\n", messageHash)
 
 		// Decode private key
 		privKeyBytes, err := hex.DecodeString(c.starkPriKey)
 		if err != nil {
-			return fmt.Errorf("failed to decode private key: %w", err)
+			return nil, fmt.Errorf("failed to decode private key: %w", err)
 		}
-		fmt.Printf("Private key bytes: %x\n", privKeyBytes)
 
 		// Convert to big.Int
 		starkPrivKey := big.NewInt(0).SetBytes(privKeyBytes)
@@ -95,7 +182,7 @@ func (c *Client) Connect(ctx context.Context) error {
 		// Sign the message
 		r, s, err := starkcurve.Sign(starkPrivKey.Bytes(), msgHashInt.Bytes())
 		if err != nil {
-			return fmt.Errorf("failed to sign message: %w", err)
+			return nil, fmt.Errorf("failed to sign message: %w", err)
 		}
 
 		// Convert r and s to 32-byte hex strings
@@ -110,42 +197,99 @@ func (c *Client) Connect(ctx context.Context) error {
 
 	conn, _, err := dialer.DialContext(ctx, c.url, headers)
 	if err != nil {
-		return fmt.Errorf("failed to connect to WebSocket: %w", err)
+		return nil, fmt.Errorf("failed to connect to WebSocket: %w", err)
 	}
 
-	c.mu.Lock()
-	c.conn = conn
-	c.mu.Unlock()
+	return conn, nil
+}
 
-	// Start ping ticker
-	c.pingTicker = time.NewTicker(30 * time.Second)
+// Close gracefully closes the WebSocket connection, waiting up to
+// DefaultCloseTimeout for the server to acknowledge the close frame.
+// It is safe to call Close more than once.
+func (c *Client) Close() error {
+	ctx, cancel := context.WithTimeout(context.Background(), DefaultCloseTimeout)
+	defer cancel()
+	return c.Shutdown(ctx)
+}
 
-	// Start message handling
-	go c.handleMessages()
-	go c.handlePing()
+// Shutdown sends a close frame and waits until the server acknowledges it or
+// ctx is done, then releases the connection. Only the first call has an effect.
+func (c *Client) Shutdown(ctx context.Context) error {
+	var err error
+	c.closeOnce.Do(func() {
+		close(c.done)
+
+		c.mu.RLock()
+		conn := c.conn
+		reading := c.reading
+		c.mu.RUnlock()
+
+		if conn != nil {
+			deadline, ok := ctx.Deadline()
+			if !ok {
+				deadline = time.Now().Add(DefaultCloseTimeout)
+			}
+			closeMsg := websocket.FormatCloseMessage(websocket.CloseNormalClosure, "")
+			if writeErr := conn.WriteControl(websocket.CloseMessage, closeMsg, deadline); writeErr == nil && reading {
+				select {
+				case <-c.terminated:
+				case <-ctx.Done():
+				}
+			}
+			err = conn.Close()
+			if errors.Is(err, net.ErrClosed) {
+				err = nil
+			}
+		}
 
-	// Call connect hooks
-	for _, hook := range c.onConnectHooks {
-		hook()
-	}
+		c.terminate(ErrClosed)
+	})
+	return err
+}
 
-	return nil
+// Done returns a channel that is closed when the client stops for good, either
+// because Close was called or because the connection dropped without auto reconnect
+func (c *Client) Done() <-chan struct{} {
+	return c.terminated
 }
 
-// Close closes the WebSocket connection
-func (c *Client) Close() error {
-	close(c.done)
-	if c.pingTicker != nil {
-		c.pingTicker.Stop()
+// Err returns why the client stopped, or nil while it is still running
+func (c *Client) Err() error {
+	select {
+	case <-c.terminated:
+		c.mu.RLock()
+		defer c.mu.RUnlock()
+		return c.err
+	default:
+		return nil
 	}
+}
 
-	c.mu.RLock()
-	defer c.mu.RUnlock()
+// terminate records the final error and closes the Done channel once
+func (c *Client) terminate(err error) {
+	c.terminateOnce.Do(func() {
+		if c.pingTicker != nil {
+			c.pingTicker.Stop()
+		}
+		c.mu.Lock()
+		c.err = err
+		c.reading = false
+		c.mu.Unlock()
+		close(c.terminated)
+	})
+}
+
+// disconnect closes the current connection so the read loop terminates and
+// reports err as the reason
+func (c *Client) disconnect(err error) {
+	c.mu.Lock()
+	conn := c.conn
+	c.dropErr = err
+	c.mu.Unlock()
 
-	if c.conn != nil {
-		return c.conn.Close()
+	if conn != nil {
+		_ = conn.Close()
 	}
-	return nil
 }
 
 // QuoteEvent represents a quote event message
@@ -159,68 +303,217 @@ type QuoteEvent struct {
 	} `json:"content"`
 }
 
-// handleMessages processes incoming WebSocket messages
-func (c *Client) handleMessages() {
+// handleMessages processes incoming WebSocket messages, reconnecting when the
+// connection drops and auto reconnect is enabled
+func (c *Client) handleMessages(conn *websocket.Conn) {
+	for {
+		err := c.readMessages(conn)
+		c.mu.Lock()
+		if c.dropErr != nil {
+			err = c.dropErr
+			c.dropErr = nil
+		}
+		c.mu.Unlock()
+		c.health.disconnected()
+		for _, hook := range c.onDisconnectHooks {
+			hook(err)
+		}
+
+		if c.isClosed() {
+			c.terminate(ErrClosed)
+			return
+		}
+		if !c.heartbeat.AutoReconnect {
+			_ = conn.Close()
+			c.terminate(err)
+			return
+		}
+		_ = conn.Close()
+		if conn = c.reconnect(); conn == nil {
+			c.terminate(ErrClosed)
+			return
+		}
+	}
+}
+
+// readMessages reads from conn until it fails and returns the read error
+func (c *Client) readMessages(conn *websocket.Conn) error {
 	for {
 		select {
 		case <-c.done:
-			return
+			return ErrClosed
 		default:
-			c.mu.RLock()
-			conn := c.conn
-			c.mu.RUnlock()
+		}
 
-			if conn == nil {
-				return
-			}
+		if c.heartbeat.ReadTimeout > 0 {
+			_ = conn.SetReadDeadline(time.Now().Add(c.heartbeat.ReadTimeout))
+		}
 
-			_, message, err := conn.ReadMessage()
-			if err != nil {
-				for _, hook := range c.onDisconnectHooks {
-					hook(err)
-				}
-				return
-			}
-			if WebsocketDebug {
-				fmt.Printf("WebSocket Message: %s\n", string(message))
-			}
+		_, message, err := conn.ReadMessage()
+		if err != nil {
+			return err
+		}
+		now := time.Now()
+		c.health.messageReceived(now)
+		if WebsocketDebug {
+			fmt.Printf("WebSocket Message: %s\n", string(message))
+		}
 
-			// Call message hooks
-			for _, hook := range c.onMessageHooks {
-				hook(message)
-			}
+		c.dispatch(message, now, true)
+	}
+}
 
-			var msg Message
-			if err := json.Unmarshal(message, &msg); err != nil {
-				continue
-			}
+// dispatch runs the message hooks and routes a frame to its handler. Live frames
+// are also recorded, answer server pings and update the connection health;
+// replayed frames only reach hooks and handlers.
+func (c *Client) dispatch(message []byte, now time.Time, live bool) {
+	// Call message hooks
+	for _, hook := range c.onMessageHooks {
+		hook(message)
+	}
 
-			// Handle ping messages
-			if msg.Type == "ping" {
-				c.handlePong(msg.Time)
-				continue
+	var msg Message
+	if err := json.Unmarshal(message, &msg); err != nil {
+		if live {
+			c.record("", message, now)
+		}
+		return
+	}
+
+	channel := msg.Type
+	var quoteEvent QuoteEvent
+	if msg.Type == "quote-event" {
+		if err := json.Unmarshal(message, &quoteEvent); err != nil {
+			if live {
+				c.record(channel, message, now)
 			}
+			return
+		}
+		channel = quoteEvent.Channel
+	}
+	if live {
+		c.record(channel, message, now)
+	}
 
-			// Handle quote events
-			if msg.Type == "quote-event" {
-				var quoteEvent QuoteEvent
-				if err := json.Unmarshal(message, &quoteEvent); err != nil {
-					continue
-				}
+	switch msg.Type {
+	case "ping":
+		// Handle ping messages
+		if live {
+			c.handlePong(msg.Time)
+		}
+		return
+	case "pong":
+		// Track round trip time of our own pings
+		if live {
+			c.health.pongReceived(now, msg.Time)
+		}
+		return
+	}
 
-				// Extract channel type from channel string (e.g., "ticker" from "ticker.10000001")
-				channelType := strings.Split(quoteEvent.Channel, ".")[0]
-				if handler, ok := c.handlers[channelType]; ok {
-					handler(message)
-				}
-				continue
-			}
+	if live {
+		c.health.channelMessage(channel, now)
+	}
+
+	// Handle quote events by channel type (e.g., "ticker" from "ticker.10000001"),
+	// other messages by their type. Listeners added with AddHandler are matched on
+	// the full channel name as well.
+	handlerKey := msg.Type
+	if msg.Type == "quote-event" {
+		handlerKey = strings.Split(channel, ".")[0]
+	}
+	for _, handler := range c.handlersFor(handlerKey, channel) {
+		handler(message)
+	}
+}
+
+// handlersFor returns the OnMessage handler for handlerKey followed by the
+// listeners registered for channel and, when it differs, for handlerKey
+func (c *Client) handlersFor(handlerKey, channel string) []MessageHandler {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	var handlers []MessageHandler
+	if handler, ok := c.handlers[handlerKey]; ok {
+		handlers = append(handlers, handler)
+	}
+	for _, l := range c.listeners[channel] {
+		handlers = append(handlers, l.handler)
+	}
+	if handlerKey != channel {
+		for _, l := range c.listeners[handlerKey] {
+			handlers = append(handlers, l.handler)
+		}
+	}
+	return handlers
+}
+
+// reconnect dials again with exponential backoff and restores subscriptions.
+// It returns nil when the client is closed before a connection is made.
+func (c *Client) reconnect() *websocket.Conn {
+	backoff := c.heartbeat.ReconnectMinBackoff
+	for {
+		select {
+		case <-c.done:
+			return nil
+		case <-time.After(backoff):
+		}
 
-			// Call registered handlers for other message types
-			if handler, ok := c.handlers[msg.Type]; ok {
-				handler(message)
+		ctx, cancel := context.WithTimeout(context.Background(), c.heartbeat.DialTimeout)
+		conn, err := c.dial(ctx)
+		cancel()
+		if err != nil {
+			backoff *= 2
+			if backoff > c.heartbeat.ReconnectMaxBackoff {
+				backoff = c.heartbeat.ReconnectMaxBackoff
 			}
+			continue
+		}
+
+		c.mu.Lock()
+		if c.isClosed() {
+			c.mu.Unlock()
+			_ = conn.Close()
+			return nil
+		}
+		c.conn = conn
+		topics := make([]string, 0, len(c.subscriptions))
+		for topic := range c.subscriptions {
+			topics = append(topics, topic)
+		}
+		c.mu.Unlock()
+		c.health.reconnected(time.Now())
+
+		for _, topic := range topics {
+			_ = c.sendMessage(map[string]interface{}{
+				"type":    "subscribe",
+				"channel": topic,
+			})
 		}
+
+		for _, hook := range c.onConnectHooks {
+			hook()
+		}
+		return conn
+	}
+}
+
+// isClosed reports whether Close has been called
+func (c *Client) isClosed() bool {
+	select {
+	case <-c.done:
+		return true
+	default:
+		return false
+	}
+}
+
+// isTerminated reports whether the client stopped for good
+func (c *Client) isTerminated() bool {
+	select {
+	case <-c.terminated:
+		return true
+	default:
+		return false
 	}
 }
 
@@ -231,22 +524,16 @@ func (c *Client) handlePing() {
 		case <-c.done:
 			return
 		case <-c.pingTicker.C:
-			c.mu.RLock()
-			conn := c.conn
-			c.mu.RUnlock()
-
-			if conn == nil {
-				return
-			}
-
+			now := time.Now()
 			pingMsg := Message{
 				Type: "ping",
-				Time: fmt.Sprintf("%d", time.Now().UnixMilli()),
+				Time: fmt.Sprintf("%d", now.UnixMilli()),
 			}
 
 			if err := c.sendMessage(pingMsg); err != nil {
-				return
+				continue
 			}
+			c.health.pingSent(now)
 		}
 	}
 }
@@ -279,6 +566,7 @@ func (c *Client) Subscribe(topic string, params map[string]interface{}) error {
 	c.mu.Lock()
 	c.subscriptions[topic] = struct{}{}
 	c.mu.Unlock()
+	c.health.subscribed(topic, time.Now())
 
 	return nil
 }
@@ -305,13 +593,55 @@ func (c *Client) Unsubscribe(topic string) error {
 	return nil
 }
 
-// OnMessage registers a handler for a specific message type
+// OnMessage registers a handler for a specific message type, replacing any
+// handler previously registered with OnMessage for msgType
 func (c *Client) OnMessage(msgType string, handler MessageHandler) {
 	c.mu.Lock()
 	defer c.mu.Unlock()
 	c.handlers[msgType] = handler
 }
 
+// AddHandler registers an additional handler for a full channel name (e.g.
+// "ticker.10000001"), a channel type (e.g. "ticker") or a private message type.
+// Other handlers for key stay in place. The returned function removes the handler.
+func (c *Client) AddHandler(key string, handler MessageHandler) func() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.nextListenerID++
+	id := c.nextListenerID
+	c.listeners[key] = append(c.listeners[key], listener{id: id, handler: handler})
+
+	var once sync.Once
+	return func() {
+		once.Do(func() { c.removeHandler(key, id) })
+	}
+}
+
+// removeHandler removes the listener id registered for key
+func (c *Client) removeHandler(key string, id uint64) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	listeners := c.listeners[key]
+	for i, l := range listeners {
+		if l.id == id {
+			listeners = append(listeners[:i:i], listeners[i+1:]...)
+			break
+		}
+	}
+	if len(listeners) == 0 {
+		delete(c.listeners, key)
+		return
+	}
+	c.listeners[key] = listeners
+}
+
+// hasHandlers reports whether any listener is registered for key
+func (c *Client) hasHandlers(key string) bool {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return len(c.listeners[key]) > 0
+}
+
 // OnMessageHook registers a hook that will be called for all messages
 func (c *Client) OnMessageHook(hook MessageHandler) {
 	c.mu.Lock()
@@ -329,7 +659,7 @@ func (c *Client) OnDisconnect(hook func(error)) {
 	c.onDisconnectHooks = append(c.onDisconnectHooks, hook)
 }
 
-// sendMessage sends a message through the WebSocket connection
+// sendMessage queues a message for the writer goroutine and waits for it to be written
 func (c *Client) sendMessage(msg interface{}) error {
 	c.mu.RLock()
 	conn := c.conn
@@ -339,5 +669,44 @@ func (c *Client) sendMessage(msg interface{}) error {
 		return fmt.Errorf("WebSocket connection is not established")
 	}
 
-	return conn.WriteJSON(msg)
+	data, err := json.Marshal(msg)
+	if err != nil {
+		return fmt.Errorf("failed to marshal message: %w", err)
+	}
+
+	out := outgoingMessage{data: data, result: make(chan error, 1)}
+	select {
+	case c.sendQueue <- out:
+	case <-c.done:
+		return ErrClosed
+	}
+
+	select {
+	case err := <-out.result:
+		return err
+	case <-c.done:
+		return ErrClosed
+	}
+}
+
+// writeMessages is the only goroutine that writes data frames to the connection
+func (c *Client) writeMessages() {
+	for {
+		select {
+		case <-c.done:
+			return
+		case out := <-c.sendQueue:
+			c.mu.RLock()
+			conn := c.conn
+			c.mu.RUnlock()
+
+			if conn == nil {
+				out.result <- fmt.Errorf("WebSocket connection is not established")
+				continue
+			}
+
+			_ = conn.SetWriteDeadline(time.Now().Add(writeTimeout))
+			out.result <- conn.WriteMessage(websocket.TextMessage, out.data)
+		}
+	}
 }
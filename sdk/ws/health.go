@@ -0,0 +1,258 @@
+package ws
+
+import (
+	"errors"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// ErrStaleFeed is reported when a channel with a stale timeout stops receiving messages
+var ErrStaleFeed = errors.New("websocket feed is stale")
+
+// HeartbeatConfig controls liveness checks and automatic reconnects
+type HeartbeatConfig struct {
+	// PingInterval is how often a ping is sent to the server
+	PingInterval time.Duration
+	// ReadTimeout fails the connection when nothing is read for this long. Zero disables it.
+	ReadTimeout time.Duration
+	// StaleCheckInterval is how often per-channel stale timeouts are evaluated
+	StaleCheckInterval time.Duration
+	// AutoReconnect reconnects and resubscribes after the connection drops
+	AutoReconnect bool
+	// DialTimeout bounds each reconnect attempt
+	DialTimeout time.Duration
+	// ReconnectMinBackoff and ReconnectMaxBackoff bound the delay between reconnect attempts
+	ReconnectMinBackoff time.Duration
+	ReconnectMaxBackoff time.Duration
+}
+
+// DefaultHeartbeatConfig returns the heartbeat configuration used by NewClient
+func DefaultHeartbeatConfig() HeartbeatConfig {
+	return HeartbeatConfig{
+		PingInterval:        30 * time.Second,
+		ReadTimeout:         90 * time.Second,
+		StaleCheckInterval:  time.Second,
+		AutoReconnect:       true,
+		DialTimeout:         10 * time.Second,
+		ReconnectMinBackoff: time.Second,
+		ReconnectMaxBackoff: 30 * time.Second,
+	}
+}
+
+// Health is a snapshot of a connection's liveness
+type Health struct {
+	Connected          bool
+	ConnectedTime      time.Time
+	LastMessageTime    time.Time
+	LastPingTime       time.Time
+	LastPongTime       time.Time
+	RTT                time.Duration
+	ReconnectCount     int
+	ChannelLastMessage map[string]time.Time
+	StaleChannels      []string
+}
+
+// healthState tracks liveness information shared by the read, ping and monitor goroutines
+type healthState struct {
+	mu             sync.Mutex
+	isConnected    bool
+	connectedTime  time.Time
+	lastMessage    time.Time
+	lastPing       time.Time
+	lastPong       time.Time
+	rtt            time.Duration
+	reconnectCount int
+	channelLast    map[string]time.Time
+	subscribedTime map[string]time.Time
+	staleTimeouts  map[string]time.Duration
+}
+
+func newHealthState() healthState {
+	return healthState{
+		channelLast:    make(map[string]time.Time),
+		subscribedTime: make(map[string]time.Time),
+		staleTimeouts:  make(map[string]time.Duration),
+	}
+}
+
+func (h *healthState) connected(now time.Time) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.isConnected = true
+	h.connectedTime = now
+}
+
+func (h *healthState) reconnected(now time.Time) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.isConnected = true
+	h.connectedTime = now
+	h.reconnectCount++
+}
+
+func (h *healthState) disconnected() {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.isConnected = false
+}
+
+func (h *healthState) messageReceived(now time.Time) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.lastMessage = now
+}
+
+func (h *healthState) channelMessage(channel string, now time.Time) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.channelLast[channel] = now
+}
+
+func (h *healthState) subscribed(channel string, now time.Time) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.subscribedTime[channel] = now
+}
+
+func (h *healthState) pingSent(now time.Time) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.lastPing = now
+}
+
+// pongReceived records a pong and derives the round trip time from the echoed ping time
+func (h *healthState) pongReceived(now time.Time, pingTime string) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.lastPong = now
+	if sent, err := strconv.ParseInt(pingTime, 10, 64); err == nil && sent > 0 {
+		h.rtt = now.Sub(time.UnixMilli(sent))
+	}
+}
+
+func (h *healthState) setStaleTimeout(channel string, timeout time.Duration) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if timeout <= 0 {
+		delete(h.staleTimeouts, channel)
+		return
+	}
+	h.staleTimeouts[channel] = timeout
+}
+
+// staleChannels returns the channels whose last message is older than their stale timeout.
+// A channel's clock starts at the later of its subscription and the current connection.
+func (h *healthState) staleChannels(now time.Time) []string {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	return h.staleChannelsLocked(now)
+}
+
+func (h *healthState) staleChannelsLocked(now time.Time) []string {
+	if !h.isConnected {
+		return nil
+	}
+
+	var stale []string
+	for channel, timeout := range h.staleTimeouts {
+		ref := h.connectedTime
+		if t := h.subscribedTime[channel]; t.After(ref) {
+			ref = t
+		}
+		if t := h.channelLast[channel]; t.After(ref) {
+			ref = t
+		}
+		if now.Sub(ref) > timeout {
+			stale = append(stale, channel)
+		}
+	}
+	sort.Strings(stale)
+	return stale
+}
+
+func (h *healthState) snapshot(now time.Time) Health {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	channelLast := make(map[string]time.Time, len(h.channelLast))
+	for channel, t := range h.channelLast {
+		channelLast[channel] = t
+	}
+	return Health{
+		Connected:          h.isConnected,
+		ConnectedTime:      h.connectedTime,
+		LastMessageTime:    h.lastMessage,
+		LastPingTime:       h.lastPing,
+		LastPongTime:       h.lastPong,
+		RTT:                h.rtt,
+		ReconnectCount:     h.reconnectCount,
+		ChannelLastMessage: channelLast,
+		StaleChannels:      h.staleChannelsLocked(now),
+	}
+}
+
+// SetHeartbeatConfig replaces the heartbeat configuration. It must be called before Connect.
+func (c *Client) SetHeartbeatConfig(cfg HeartbeatConfig) {
+	defaults := DefaultHeartbeatConfig()
+	if cfg.PingInterval <= 0 {
+		cfg.PingInterval = defaults.PingInterval
+	}
+	if cfg.StaleCheckInterval <= 0 {
+		cfg.StaleCheckInterval = defaults.StaleCheckInterval
+	}
+	if cfg.DialTimeout <= 0 {
+		cfg.DialTimeout = defaults.DialTimeout
+	}
+	if cfg.ReconnectMinBackoff <= 0 {
+		cfg.ReconnectMinBackoff = defaults.ReconnectMinBackoff
+	}
+	if cfg.ReconnectMaxBackoff < cfg.ReconnectMinBackoff {
+		cfg.ReconnectMaxBackoff = cfg.ReconnectMinBackoff
+	}
+	c.heartbeat = cfg
+}
+
+// SetStaleTimeout marks a channel as stale when no message arrives on it for timeout.
+// Public channels use the full channel name (e.g. "depth.10000001.15"), private
+// channels use the message type. A zero timeout removes the check.
+func (c *Client) SetStaleTimeout(channel string, timeout time.Duration) {
+	c.health.setStaleTimeout(channel, timeout)
+}
+
+// OnStale registers a hook that will be called when a channel goes stale
+func (c *Client) OnStale(hook func(channel string)) {
+	c.onStaleHooks = append(c.onStaleHooks, hook)
+}
+
+// Health returns a snapshot of the connection's liveness
+func (c *Client) Health() Health {
+	return c.health.snapshot(time.Now())
+}
+
+// monitorHealth periodically checks stale timeouts and drops the connection when
+// a feed is stale, which triggers a reconnect if enabled
+func (c *Client) monitorHealth() {
+	ticker := time.NewTicker(c.heartbeat.StaleCheckInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-c.done:
+			return
+		case now := <-ticker.C:
+			stale := c.health.staleChannels(now)
+			if len(stale) == 0 {
+				continue
+			}
+			for _, channel := range stale {
+				for _, hook := range c.onStaleHooks {
+					hook(channel)
+				}
+			}
+			c.health.disconnected()
+			c.disconnect(ErrStaleFeed)
+		}
+	}
+}
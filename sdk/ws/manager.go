@@ -4,16 +4,18 @@ import (
 	"context"
 	"fmt"
 	"sync"
+	"time"
 )
 
 // Manager handles WebSocket connections
 type Manager struct {
 	publicClient  *Client
 	privateClient *Client
-	baseURL      string
-	accountID    int64
-	starkPriKey  string
-	mu           sync.RWMutex
+	baseURL       string
+	accountID     int64
+	starkPriKey   string
+	mu            sync.RWMutex
+	heartbeat     *HeartbeatConfig
 }
 
 // NewManager creates a new WebSocket manager
@@ -25,6 +27,33 @@ func NewManager(baseURL string, accountID int64, starkPriKey string) *Manager {
 	}
 }
 
+// TickerChannel returns the public channel name for 24-hour ticker updates
+func TickerChannel(contractID string) string {
+	return fmt.Sprintf("ticker.%s", contractID)
+}
+
+// KLineChannel returns the public channel name for last price K-line updates
+func KLineChannel(contractID string, interval string) string {
+	return fmt.Sprintf("kline.LAST_PRICE.%s.%s", contractID, interval)
+}
+
+// DepthChannel returns the public channel name for depth updates
+func DepthChannel(contractID string) string {
+	return fmt.Sprintf("depth.%s.15", contractID)
+}
+
+// TradesChannel returns the public channel name for latest trades
+func TradesChannel(contractID string) string {
+	return fmt.Sprintf("trades.%s", contractID)
+}
+
+// SetHeartbeatConfig sets the heartbeat configuration applied to connections opened afterwards
+func (m *Manager) SetHeartbeatConfig(cfg HeartbeatConfig) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.heartbeat = &cfg
+}
+
 // ConnectPublic connects to the public WebSocket endpoint
 func (m *Manager) ConnectPublic(ctx context.Context) error {
 	m.mu.Lock()
@@ -35,7 +64,10 @@ func (m *Manager) ConnectPublic(ctx context.Context) error {
 	}
 
 	url := fmt.Sprintf("%s/api/v1/public/ws", m.baseURL)
-	client := NewClient(url, false, 0, "")  // No auth needed for public
+	client := NewClient(url, false, 0, "") // No auth needed for public
+	if m.heartbeat != nil {
+		client.SetHeartbeatConfig(*m.heartbeat)
+	}
 	if err := client.Connect(ctx); err != nil {
 		return err
 	}
@@ -55,6 +87,9 @@ func (m *Manager) ConnectPrivate(ctx context.Context) error {
 
 	url := fmt.Sprintf("%s/api/v1/private/ws?accountId=%d", m.baseURL, m.accountID)
 	client := NewClient(url, true, m.accountID, m.starkPriKey)
+	if m.heartbeat != nil {
+		client.SetHeartbeatConfig(*m.heartbeat)
+	}
 	if err := client.Connect(ctx); err != nil {
 		return err
 	}
@@ -63,36 +98,124 @@ func (m *Manager) ConnectPrivate(ctx context.Context) error {
 	return nil
 }
 
-// SubscribeMarketTicker subscribes to 24-hour market ticker updates
-func (m *Manager) SubscribeMarketTicker(contractID string, handler MessageHandler) error {
+// SubscribeMarketTicker subscribes to 24-hour market ticker updates. handler only
+// receives the ticker of contractID and is added next to other handlers.
+// The returned function removes it, unsubscribing once no handler is left.
+func (m *Manager) SubscribeMarketTicker(contractID string, handler MessageHandler) (func(), error) {
+	return m.publicHandler(TickerChannel(contractID), handler)
+}
+
+// SubscribeKLine subscribes to K-line (candlestick) data. handler only receives the
+// K-lines of contractID and interval and is added next to other handlers.
+// The returned function removes it, unsubscribing once no handler is left.
+func (m *Manager) SubscribeKLine(contractID string, interval string, handler MessageHandler) (func(), error) {
+	return m.publicHandler(KLineChannel(contractID, interval), handler)
+}
+
+// SubscribeDepth subscribes to market depth updates. handler only receives the
+// depth of contractID and is added next to other handlers.
+// The returned function removes it, unsubscribing once no handler is left.
+func (m *Manager) SubscribeDepth(contractID string, handler MessageHandler) (func(), error) {
+	return m.publicHandler(DepthChannel(contractID), handler)
+}
+
+// SubscribeTrades subscribes to latest trades. handler only receives the trades
+// of contractID and is added next to other handlers.
+// The returned function removes it, unsubscribing once no handler is left.
+func (m *Manager) SubscribeTrades(contractID string, handler MessageHandler) (func(), error) {
+	return m.publicHandler(TradesChannel(contractID), handler)
+}
+
+// StreamMarketTicker subscribes to 24-hour market ticker updates and delivers them over a Stream
+func (m *Manager) StreamMarketTicker(contractID string, cfg StreamConfig) (*Stream, error) {
+	return m.publicStream(TickerChannel(contractID), cfg)
+}
+
+// StreamKLine subscribes to K-line (candlestick) data and delivers it over a Stream
+func (m *Manager) StreamKLine(contractID string, interval string, cfg StreamConfig) (*Stream, error) {
+	return m.publicStream(KLineChannel(contractID, interval), cfg)
+}
+
+// StreamDepth subscribes to market depth updates and delivers them over a Stream
+func (m *Manager) StreamDepth(contractID string, cfg StreamConfig) (*Stream, error) {
+	return m.publicStream(DepthChannel(contractID), cfg)
+}
+
+// StreamTrades subscribes to latest trades and delivers them over a Stream
+func (m *Manager) StreamTrades(contractID string, cfg StreamConfig) (*Stream, error) {
+	return m.publicStream(TradesChannel(contractID), cfg)
+}
+
+// StreamPrivateMessage delivers private WebSocket messages of the given type over a Stream
+func (m *Manager) StreamPrivateMessage(msgType string, cfg StreamConfig) (*Stream, error) {
+	m.mu.RLock()
+	client := m.privateClient
+	m.mu.RUnlock()
+
+	if client == nil {
+		return nil, fmt.Errorf("private WebSocket connection not established")
+	}
+
+	return client.Stream(msgType, cfg), nil
+}
+
+// publicHandler adds a handler for a channel of the public connection and subscribes it
+func (m *Manager) publicHandler(channel string, handler MessageHandler) (func(), error) {
 	m.mu.RLock()
 	client := m.publicClient
 	m.mu.RUnlock()
 
 	if client == nil {
-		return fmt.Errorf("public WebSocket connection not established")
+		return nil, fmt.Errorf("public WebSocket connection not established")
 	}
 
-	client.OnMessage("ticker", handler)
-	return client.Subscribe(fmt.Sprintf("ticker.%s", contractID), nil)
+	return client.subscribeHandler(channel, handler)
 }
 
-// SubscribeKLine subscribes to K-line (candlestick) data
-func (m *Manager) SubscribeKLine(contractID string, interval string, handler MessageHandler) error {
+// publicStream creates a Stream bound to a channel of the public connection and subscribes it
+func (m *Manager) publicStream(channel string, cfg StreamConfig) (*Stream, error) {
 	m.mu.RLock()
 	client := m.publicClient
 	m.mu.RUnlock()
 
 	if client == nil {
-		return fmt.Errorf("public WebSocket connection not established")
+		return nil, fmt.Errorf("public WebSocket connection not established")
+	}
+
+	return client.streamChannel(channel, cfg)
+}
+
+// OnPrivateMessage registers a handler for private WebSocket messages, replacing
+// any handler previously registered with OnPrivateMessage for msgType
+func (m *Manager) OnPrivateMessage(msgType string, handler MessageHandler) error {
+	m.mu.RLock()
+	client := m.privateClient
+	m.mu.RUnlock()
+
+	if client == nil {
+		return fmt.Errorf("private WebSocket connection not established")
 	}
 
-	client.OnMessage("kline", handler)
-	return client.Subscribe(fmt.Sprintf("kline.LAST_PRICE.%s.%s", contractID, interval), nil)
+	client.OnMessage(msgType, handler)
+	return nil
 }
 
-// SubscribeDepth subscribes to market depth updates
-func (m *Manager) SubscribeDepth(contractID string, handler MessageHandler) error {
+// AddPrivateHandler registers an additional handler for private WebSocket messages
+// of msgType, keeping the handlers already registered. The returned function removes it.
+func (m *Manager) AddPrivateHandler(msgType string, handler MessageHandler) (func(), error) {
+	m.mu.RLock()
+	client := m.privateClient
+	m.mu.RUnlock()
+
+	if client == nil {
+		return nil, fmt.Errorf("private WebSocket connection not established")
+	}
+
+	return client.AddHandler(msgType, handler), nil
+}
+
+// OnPublicMessage registers a handler for all public WebSocket messages
+func (m *Manager) OnPublicMessage(handler MessageHandler) error {
 	m.mu.RLock()
 	client := m.publicClient
 	m.mu.RUnlock()
@@ -101,12 +224,12 @@ func (m *Manager) SubscribeDepth(contractID string, handler MessageHandler) erro
 		return fmt.Errorf("public WebSocket connection not established")
 	}
 
-	client.OnMessage("depth", handler)
-	return client.Subscribe(fmt.Sprintf("depth.%s.15", contractID), nil)
+	client.OnMessageHook(handler)
+	return nil
 }
 
-// SubscribeTrades subscribes to latest trades
-func (m *Manager) SubscribeTrades(contractID string, handler MessageHandler) error {
+// SetPublicStaleTimeout reconnects the public connection when channel receives nothing for timeout
+func (m *Manager) SetPublicStaleTimeout(channel string, timeout time.Duration) error {
 	m.mu.RLock()
 	client := m.publicClient
 	m.mu.RUnlock()
@@ -115,12 +238,12 @@ func (m *Manager) SubscribeTrades(contractID string, handler MessageHandler) err
 		return fmt.Errorf("public WebSocket connection not established")
 	}
 
-	client.OnMessage("trades", handler)
-	return client.Subscribe(fmt.Sprintf("trades.%s", contractID), nil)
+	client.SetStaleTimeout(channel, timeout)
+	return nil
 }
 
-// OnPrivateMessage registers a handler for private WebSocket messages
-func (m *Manager) OnPrivateMessage(msgType string, handler MessageHandler) error {
+// SetPrivateStaleTimeout reconnects the private connection when msgType receives nothing for timeout
+func (m *Manager) SetPrivateStaleTimeout(msgType string, timeout time.Duration) error {
 	m.mu.RLock()
 	client := m.privateClient
 	m.mu.RUnlock()
@@ -129,12 +252,38 @@ func (m *Manager) OnPrivateMessage(msgType string, handler MessageHandler) error
 		return fmt.Errorf("private WebSocket connection not established")
 	}
 
-	client.OnMessage(msgType, handler)
+	client.SetStaleTimeout(msgType, timeout)
 	return nil
 }
 
-// OnPublicMessage registers a handler for all public WebSocket messages
-func (m *Manager) OnPublicMessage(handler MessageHandler) error {
+// PublicHealth returns a liveness snapshot of the public connection
+func (m *Manager) PublicHealth() (Health, error) {
+	m.mu.RLock()
+	client := m.publicClient
+	m.mu.RUnlock()
+
+	if client == nil {
+		return Health{}, fmt.Errorf("public WebSocket connection not established")
+	}
+
+	return client.Health(), nil
+}
+
+// PrivateHealth returns a liveness snapshot of the private connection
+func (m *Manager) PrivateHealth() (Health, error) {
+	m.mu.RLock()
+	client := m.privateClient
+	m.mu.RUnlock()
+
+	if client == nil {
+		return Health{}, fmt.Errorf("private WebSocket connection not established")
+	}
+
+	return client.Health(), nil
+}
+
+// RecordPublic writes every frame received on the public connection to r
+func (m *Manager) RecordPublic(r *Recorder) error {
 	m.mu.RLock()
 	client := m.publicClient
 	m.mu.RUnlock()
@@ -143,7 +292,21 @@ func (m *Manager) OnPublicMessage(handler MessageHandler) error {
 		return fmt.Errorf("public WebSocket connection not established")
 	}
 
-	client.OnMessageHook(handler)
+	client.Record(r)
+	return nil
+}
+
+// RecordPrivate writes every frame received on the private connection to r
+func (m *Manager) RecordPrivate(r *Recorder) error {
+	m.mu.RLock()
+	client := m.privateClient
+	m.mu.RUnlock()
+
+	if client == nil {
+		return fmt.Errorf("private WebSocket connection not established")
+	}
+
+	client.Record(r)
 	return nil
 }
 
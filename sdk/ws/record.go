@@ -0,0 +1,324 @@
+package ws
+
+import (
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"os"
+	"strings"
+	"sync"
+	"time"
+)
+
+// RecordedFrame is a single line of a recording
+type RecordedFrame struct {
+	// ReceivedAt is the receive time in Unix nanoseconds
+	ReceivedAt int64 `json:"ts"`
+	// Channel is the quote channel (e.g. "depth.10000001.15") or the message type
+	Channel string `json:"channel,omitempty"`
+	// Frame is the received frame. Insignificant JSON whitespace is not preserved.
+	Frame json.RawMessage `json:"frame"`
+	// Text is set when the frame was not valid JSON and is stored as a JSON string
+	Text bool `json:"text,omitempty"`
+}
+
+// Time returns the receive time of the frame
+func (f RecordedFrame) Time() time.Time {
+	return time.Unix(0, f.ReceivedAt)
+}
+
+// Message returns the frame bytes to feed back into a handler
+func (f RecordedFrame) Message() ([]byte, error) {
+	if !f.Text {
+		return f.Frame, nil
+	}
+	var text string
+	if err := json.Unmarshal(f.Frame, &text); err != nil {
+		return nil, err
+	}
+	return []byte(text), nil
+}
+
+// Recorder writes received frames as JSON lines, optionally gzip compressed
+type Recorder struct {
+	mu     sync.Mutex
+	buf    *bufio.Writer
+	gz     *gzip.Writer
+	closer io.Closer
+	enc    *json.Encoder
+	frames uint64
+	err    error
+	closed bool
+}
+
+// NewRecorder creates a new Recorder writing to w. The caller keeps ownership of w.
+func NewRecorder(w io.Writer, compress bool) *Recorder {
+	r := &Recorder{}
+	if compress {
+		r.gz = gzip.NewWriter(w)
+		w = r.gz
+	}
+	r.buf = bufio.NewWriter(w)
+	r.enc = json.NewEncoder(r.buf)
+	return r
+}
+
+// CreateRecorder creates a Recorder writing to a new file at path.
+// The file is gzip compressed when path ends with ".gz".
+func CreateRecorder(path string) (*Recorder, error) {
+	file, err := os.Create(path)
+	if err != nil {
+		return nil, err
+	}
+	r := NewRecorder(file, strings.HasSuffix(path, ".gz"))
+	r.closer = file
+	return r, nil
+}
+
+// Record appends a frame to the recording
+func (r *Recorder) Record(channel string, message []byte, receivedAt time.Time) error {
+	frame := RecordedFrame{
+		ReceivedAt: receivedAt.UnixNano(),
+		Channel:    channel,
+		Frame:      message,
+	}
+	if !json.Valid(message) {
+		text, _ := json.Marshal(string(message))
+		frame.Frame = text
+		frame.Text = true
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.closed {
+		return ErrClosed
+	}
+	if r.err != nil {
+		return r.err
+	}
+	if err := r.enc.Encode(frame); err != nil {
+		r.err = err
+		return err
+	}
+	r.frames++
+	return nil
+}
+
+// Frames returns the number of frames recorded
+func (r *Recorder) Frames() uint64 {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.frames
+}
+
+// Err returns the first write error, if any
+func (r *Recorder) Err() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.err
+}
+
+// Flush writes buffered frames to the underlying writer
+func (r *Recorder) Flush() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.flushLocked()
+}
+
+func (r *Recorder) flushLocked() error {
+	if r.err != nil {
+		return r.err
+	}
+	if err := r.buf.Flush(); err != nil {
+		r.err = err
+		return err
+	}
+	if r.gz != nil {
+		if err := r.gz.Flush(); err != nil {
+			r.err = err
+			return err
+		}
+	}
+	return nil
+}
+
+// Close flushes the recording and closes the file opened by CreateRecorder
+func (r *Recorder) Close() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.closed {
+		return nil
+	}
+	r.closed = true
+
+	err := r.flushLocked()
+	if r.gz != nil {
+		if gzErr := r.gz.Close(); err == nil {
+			err = gzErr
+		}
+	}
+	if r.closer != nil {
+		if closeErr := r.closer.Close(); err == nil {
+			err = closeErr
+		}
+	}
+	return err
+}
+
+// Record writes every frame received by the client to r. Pass nil to stop recording.
+func (c *Client) Record(r *Recorder) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.recorder = r
+}
+
+// record writes a received frame to the active recorder, if any
+func (c *Client) record(channel string, message []byte, receivedAt time.Time) {
+	c.mu.RLock()
+	r := c.recorder
+	c.mu.RUnlock()
+
+	if r != nil {
+		_ = r.Record(channel, message, receivedAt)
+	}
+}
+
+// RecordingReader reads frames from a recording
+type RecordingReader struct {
+	dec    *json.Decoder
+	closer io.Closer
+}
+
+// NewRecordingReader creates a new RecordingReader. Gzip compressed input is detected automatically.
+func NewRecordingReader(r io.Reader) (*RecordingReader, error) {
+	br := bufio.NewReader(r)
+	magic, err := br.Peek(2)
+	if err != nil && !errors.Is(err, io.EOF) {
+		return nil, err
+	}
+
+	var src io.Reader = br
+	if bytes.Equal(magic, []byte{0x1f, 0x8b}) {
+		gz, err := gzip.NewReader(br)
+		if err != nil {
+			return nil, err
+		}
+		src = gz
+	}
+	return &RecordingReader{dec: json.NewDecoder(src)}, nil
+}
+
+// OpenRecording opens a recording file written by CreateRecorder
+func OpenRecording(path string) (*RecordingReader, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	reader, err := NewRecordingReader(file)
+	if err != nil {
+		file.Close()
+		return nil, err
+	}
+	reader.closer = file
+	return reader, nil
+}
+
+// Next returns the next frame, or io.EOF at the end of the recording
+func (r *RecordingReader) Next() (RecordedFrame, error) {
+	var frame RecordedFrame
+	if err := r.dec.Decode(&frame); err != nil {
+		return RecordedFrame{}, err
+	}
+	return frame, nil
+}
+
+// Close closes the file opened by OpenRecording
+func (r *RecordingReader) Close() error {
+	if r.closer == nil {
+		return nil
+	}
+	return r.closer.Close()
+}
+
+// ReplayConfig controls how a recording is replayed
+type ReplayConfig struct {
+	// RealTime waits between frames to reproduce the recorded timing.
+	// Otherwise frames are replayed as fast as possible.
+	RealTime bool
+	// Speed scales the recorded timing when RealTime is set, e.g. 2 replays twice as fast.
+	// Zero means original speed.
+	Speed float64
+	// Channels restricts the replay to these channels. Empty replays every frame.
+	Channels []string
+}
+
+// Replay feeds a recording through the client's hooks and handlers, including streams,
+// as if the frames had been received. No connection is needed and nothing is sent.
+// It returns the number of frames replayed.
+func (c *Client) Replay(ctx context.Context, r *RecordingReader, cfg ReplayConfig) (int, error) {
+	speed := cfg.Speed
+	if speed <= 0 {
+		speed = 1
+	}
+	var channels map[string]struct{}
+	if len(cfg.Channels) > 0 {
+		channels = make(map[string]struct{}, len(cfg.Channels))
+		for _, channel := range cfg.Channels {
+			channels[channel] = struct{}{}
+		}
+	}
+
+	var (
+		count     int
+		firstAt   int64
+		startTime time.Time
+	)
+	for {
+		if err := ctx.Err(); err != nil {
+			return count, err
+		}
+
+		frame, err := r.Next()
+		if errors.Is(err, io.EOF) {
+			return count, nil
+		}
+		if err != nil {
+			return count, err
+		}
+		if channels != nil {
+			if _, ok := channels[frame.Channel]; !ok {
+				continue
+			}
+		}
+
+		if cfg.RealTime {
+			if startTime.IsZero() {
+				firstAt = frame.ReceivedAt
+				startTime = time.Now()
+			}
+			offset := time.Duration(float64(frame.ReceivedAt-firstAt) / speed)
+			if wait := time.Until(startTime.Add(offset)); wait > 0 {
+				timer := time.NewTimer(wait)
+				select {
+				case <-ctx.Done():
+					timer.Stop()
+					return count, ctx.Err()
+				case <-timer.C:
+				}
+			}
+		}
+
+		message, err := frame.Message()
+		if err != nil {
+			return count, err
+		}
+		c.dispatch(message, frame.Time(), false)
+		count++
+	}
+}
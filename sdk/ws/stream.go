@@ -0,0 +1,210 @@
+package ws
+
+import (
+	"errors"
+	"sync"
+	"sync/atomic"
+)
+
+// ErrStreamOverflow is reported when a stream using OverflowDisconnect runs out of buffer space
+var ErrStreamOverflow = errors.New("websocket stream buffer overflow")
+
+// OverflowPolicy decides what a Stream does when its buffer is full
+type OverflowPolicy int
+
+const (
+	// OverflowDropOldest discards the oldest buffered message to make room for the new one
+	OverflowDropOldest OverflowPolicy = iota
+	// OverflowDropNewest discards the incoming message and keeps the buffer as is
+	OverflowDropNewest
+	// OverflowBlock waits for the consumer to make room. This stalls the read loop,
+	// so server pings are not answered while the buffer stays full.
+	OverflowBlock
+	// OverflowDisconnect drops the message and closes the underlying connection
+	OverflowDisconnect
+)
+
+// String returns the string representation of OverflowPolicy
+func (p OverflowPolicy) String() string {
+	switch p {
+	case OverflowDropOldest:
+		return "DROP_OLDEST"
+	case OverflowDropNewest:
+		return "DROP_NEWEST"
+	case OverflowBlock:
+		return "BLOCK"
+	case OverflowDisconnect:
+		return "DISCONNECT"
+	default:
+		return "UNKNOWN"
+	}
+}
+
+// DefaultStreamBufferSize is used when StreamConfig.BufferSize is not positive
+const DefaultStreamBufferSize = 1024
+
+// StreamConfig holds the configuration for creating a new Stream
+type StreamConfig struct {
+	BufferSize int
+	Policy     OverflowPolicy
+}
+
+// Stream delivers WebSocket messages over a bounded channel so that slow
+// consumers do not run on the connection's read goroutine
+type Stream struct {
+	ch         chan []byte
+	policy     OverflowPolicy
+	mu         sync.RWMutex
+	closed     bool
+	done       chan struct{}
+	closeOnce  sync.Once
+	delivered  atomic.Uint64
+	dropped    atomic.Uint64
+	disconnect func(error)
+	release    func()
+}
+
+// NewStream creates a new Stream that is not yet attached to a connection.
+// Use Handler to feed it from any MessageHandler based API.
+func NewStream(cfg StreamConfig) *Stream {
+	size := cfg.BufferSize
+	if size <= 0 {
+		size = DefaultStreamBufferSize
+	}
+	return &Stream{
+		ch:     make(chan []byte, size),
+		policy: cfg.Policy,
+		done:   make(chan struct{}),
+	}
+}
+
+// C returns the channel messages are delivered on. It is closed by Close.
+func (s *Stream) C() <-chan []byte {
+	return s.ch
+}
+
+// Handler returns a MessageHandler that enqueues messages into the stream
+func (s *Stream) Handler() MessageHandler {
+	return s.push
+}
+
+// Delivered returns the number of messages accepted into the buffer
+func (s *Stream) Delivered() uint64 {
+	return s.delivered.Load()
+}
+
+// Dropped returns the number of messages discarded because the buffer was full
+func (s *Stream) Dropped() uint64 {
+	return s.dropped.Load()
+}
+
+// Len returns the number of messages currently buffered
+func (s *Stream) Len() int {
+	return len(s.ch)
+}
+
+// Close stops delivery and closes the channel returned by C. A stream created
+// by a Client or Manager is also unregistered, and its channel unsubscribed once
+// no other handler listens on it.
+func (s *Stream) Close() {
+	s.closeOnce.Do(func() {
+		close(s.done)
+		s.mu.Lock()
+		s.closed = true
+		close(s.ch)
+		s.mu.Unlock()
+		if s.release != nil {
+			s.release()
+		}
+	})
+}
+
+// push enqueues a message according to the stream's overflow policy
+func (s *Stream) push(message []byte) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	if s.closed {
+		return
+	}
+
+	select {
+	case s.ch <- message:
+		s.delivered.Add(1)
+		return
+	default:
+	}
+
+	switch s.policy {
+	case OverflowDropOldest:
+		for {
+			select {
+			case <-s.ch:
+				s.dropped.Add(1)
+			default:
+			}
+			select {
+			case s.ch <- message:
+				s.delivered.Add(1)
+				return
+			default:
+			}
+		}
+	case OverflowBlock:
+		select {
+		case s.ch <- message:
+			s.delivered.Add(1)
+		case <-s.done:
+			s.dropped.Add(1)
+		}
+	case OverflowDisconnect:
+		s.dropped.Add(1)
+		if s.disconnect != nil {
+			s.disconnect(ErrStreamOverflow)
+		}
+	default:
+		s.dropped.Add(1)
+	}
+}
+
+// Stream registers a bounded channel consumer for a message type, channel type
+// or full channel name. It is added next to the handlers already registered for
+// key; Close removes it again.
+func (c *Client) Stream(key string, cfg StreamConfig) *Stream {
+	stream := NewStream(cfg)
+	stream.disconnect = c.disconnect
+	stream.release = c.AddHandler(key, stream.Handler())
+	return stream
+}
+
+// streamChannel registers a stream for a public channel and subscribes to it.
+// Closing the stream unsubscribes the channel when no other handler is left on it.
+func (c *Client) streamChannel(channel string, cfg StreamConfig) (*Stream, error) {
+	stream := NewStream(cfg)
+	stream.disconnect = c.disconnect
+	release, err := c.subscribeHandler(channel, stream.Handler())
+	if err != nil {
+		stream.Close()
+		return nil, err
+	}
+	stream.release = release
+	return stream, nil
+}
+
+// subscribeHandler adds handler for a public channel and subscribes to it. The
+// returned function removes the handler and unsubscribes the channel when no other
+// handler is left on it.
+func (c *Client) subscribeHandler(channel string, handler MessageHandler) (func(), error) {
+	remove := c.AddHandler(channel, handler)
+	release := func() {
+		remove()
+		if !c.hasHandlers(channel) {
+			_ = c.Unsubscribe(channel)
+		}
+	}
+	if err := c.Subscribe(channel, nil); err != nil {
+		release()
+		return nil, err
+	}
+	return release, nil
+}
@@ -0,0 +1,457 @@
+// Package wstest provides an in-process fake of the edgeX WebSocket API for
+// deterministic, offline tests of code built on sdk/ws.
+package wstest
+
+import (
+	"context"
+	"encoding/hex"
+	"fmt"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/coin-quant/go-edgex/sdk/ws"
+	"github.com/coin-quant/go-edgex/starkcurve"
+	"github.com/gorilla/websocket"
+	"golang.org/x/crypto/sha3"
+)
+
+// Config holds the configuration for creating a new Server
+type Config struct {
+	// AccountID is the only account accepted on the private endpoint. Zero accepts any account.
+	AccountID int64
+	// StarkPublicKey is the hex x-coordinate of the account's Stark public key.
+	// When set, private connections must carry a valid signature for it.
+	StarkPublicKey string
+	// PingInterval makes the server ping every connection periodically. Zero disables it.
+	PingInterval time.Duration
+}
+
+// Frame is one scripted message played by Play
+type Frame struct {
+	// Delay is waited before the frame is sent
+	Delay time.Duration
+	// Channel and DataType describe a public quote event
+	Channel  string
+	DataType string
+	// Type sends a private message of this type instead of a quote event
+	Type string
+	// Data is the quote event data or the private message content
+	Data interface{}
+}
+
+// Server is a fake edgeX WebSocket server listening on a local address
+type Server struct {
+	// URL is the base URL to pass to ws.NewManager, e.g. ws://127.0.0.1:1234
+	URL string
+
+	cfg        Config
+	httpServer *httptest.Server
+	upgrader   websocket.Upgrader
+	mu         sync.Mutex
+	conns      map[*serverConn]struct{}
+	skip       map[string]int
+	accepted   int
+	rejected   int
+	pongs      int
+	subscribed chan string
+	done       chan struct{}
+	closeOnce  sync.Once
+}
+
+// serverConn is a single client connection
+type serverConn struct {
+	conn          *websocket.Conn
+	private       bool
+	writeMu       sync.Mutex
+	subscriptions map[string]struct{}
+}
+
+// NewServer starts a new fake server
+func NewServer(cfg Config) *Server {
+	s := &Server{
+		cfg:        cfg,
+		conns:      make(map[*serverConn]struct{}),
+		skip:       make(map[string]int),
+		subscribed: make(chan string, 1024),
+		done:       make(chan struct{}),
+	}
+	mux := http.NewServeMux()
+	mux.HandleFunc("/api/v1/public/ws", s.handlePublic)
+	mux.HandleFunc("/api/v1/private/ws", s.handlePrivate)
+	s.httpServer = httptest.NewServer(mux)
+	s.URL = "ws" + strings.TrimPrefix(s.httpServer.URL, "http")
+
+	if cfg.PingInterval > 0 {
+		go s.pingLoop()
+	}
+	return s
+}
+
+// PublicURL returns the URL of the public endpoint
+func (s *Server) PublicURL() string {
+	return s.URL + "/api/v1/public/ws"
+}
+
+// PrivateURL returns the URL of the private endpoint for an account
+func (s *Server) PrivateURL(accountID int64) string {
+	return fmt.Sprintf("%s/api/v1/private/ws?accountId=%d", s.URL, accountID)
+}
+
+// Close drops every connection and shuts the server down
+func (s *Server) Close() {
+	s.closeOnce.Do(func() {
+		close(s.done)
+		s.DropConnections()
+		s.httpServer.Close()
+	})
+}
+
+// handlePublic upgrades a public connection
+func (s *Server) handlePublic(w http.ResponseWriter, r *http.Request) {
+	s.serve(w, r, false)
+}
+
+// handlePrivate checks the signed headers and upgrades a private connection
+func (s *Server) handlePrivate(w http.ResponseWriter, r *http.Request) {
+	if err := s.verifyPrivate(r); err != nil {
+		s.mu.Lock()
+		s.rejected++
+		s.mu.Unlock()
+		http.Error(w, err.Error(), http.StatusUnauthorized)
+		return
+	}
+	s.serve(w, r, true)
+}
+
+// verifyPrivate checks the account and, if a public key is configured, the Stark signature
+func (s *Server) verifyPrivate(r *http.Request) error {
+	accountID := r.URL.Query().Get("accountId")
+	if s.cfg.AccountID != 0 && accountID != strconv.FormatInt(s.cfg.AccountID, 10) {
+		return fmt.Errorf("unexpected accountId: %s", accountID)
+	}
+	if s.cfg.StarkPublicKey == "" {
+		return nil
+	}
+
+	timestamp := r.Header.Get("X-edgeX-Api-Timestamp")
+	signature := r.Header.Get("X-edgeX-Api-Signature")
+	if timestamp == "" || len(signature) != 128 {
+		return fmt.Errorf("missing or malformed auth headers")
+	}
+	rInt, ok := new(big.Int).SetString(signature[:64], 16)
+	if !ok {
+		return fmt.Errorf("invalid signature r")
+	}
+	sInt, ok := new(big.Int).SetString(signature[64:], 16)
+	if !ok {
+		return fmt.Errorf("invalid signature s")
+	}
+
+	// Must match the content signed by ws.Client
+	signContent := fmt.Sprintf("%s%s%s", timestamp, "GET", fmt.Sprintf("/api/v1/private/wsaccountId=%s", accountID))
+	hash := sha3.NewLegacyKeccak256()
+	hash.Write([]byte(signContent))
+	curve := starkcurve.NewStarkCurve()
+	msgHashInt := new(big.Int).SetBytes(hash.Sum(nil))
+	msgHashInt.Mod(msgHashInt, curve.N)
+
+	pubKey, err := hex.DecodeString(strings.TrimPrefix(s.cfg.StarkPublicKey, "0x"))
+	if err != nil {
+		return fmt.Errorf("invalid configured public key: %w", err)
+	}
+	pubX := new(big.Int).SetBytes(pubKey)
+	y, minusY := curve.GetYCoordinate(pubX)
+	for _, pubY := range []*big.Int{y, minusY} {
+		if starkcurve.Verify(msgHashInt.Bytes(), pubX, pubY, rInt, sInt) {
+			return nil
+		}
+	}
+	return fmt.Errorf("signature verification failed")
+}
+
+// serve upgrades the request and reads client frames until the connection ends
+func (s *Server) serve(w http.ResponseWriter, r *http.Request, private bool) {
+	conn, err := s.upgrader.Upgrade(w, r, nil)
+	if err != nil {
+		return
+	}
+	sc := &serverConn{
+		conn:          conn,
+		private:       private,
+		subscriptions: make(map[string]struct{}),
+	}
+
+	s.mu.Lock()
+	s.conns[sc] = struct{}{}
+	s.accepted++
+	s.mu.Unlock()
+
+	defer func() {
+		s.mu.Lock()
+		delete(s.conns, sc)
+		s.mu.Unlock()
+		conn.Close()
+	}()
+
+	for {
+		var msg struct {
+			Type    string `json:"type"`
+			Time    string `json:"time"`
+			Channel string `json:"channel"`
+		}
+		if err := conn.ReadJSON(&msg); err != nil {
+			return
+		}
+
+		switch msg.Type {
+		case "ping":
+			_ = sc.writeJSON(ws.Message{Type: "pong", Time: msg.Time})
+		case "pong":
+			s.mu.Lock()
+			s.pongs++
+			s.mu.Unlock()
+		case "subscribe":
+			s.mu.Lock()
+			sc.subscriptions[msg.Channel] = struct{}{}
+			s.mu.Unlock()
+			_ = sc.writeJSON(map[string]string{"type": "subscribed", "channel": msg.Channel})
+			select {
+			case s.subscribed <- msg.Channel:
+			default:
+			}
+		case "unsubscribe":
+			s.mu.Lock()
+			delete(sc.subscriptions, msg.Channel)
+			s.mu.Unlock()
+			_ = sc.writeJSON(map[string]string{"type": "unsubscribed", "channel": msg.Channel})
+		}
+	}
+}
+
+// writeJSON writes a frame, serializing writers on the connection
+func (sc *serverConn) writeJSON(v interface{}) error {
+	sc.writeMu.Lock()
+	defer sc.writeMu.Unlock()
+	return sc.conn.WriteJSON(v)
+}
+
+// pingLoop pings every connection until the server is closed
+func (s *Server) pingLoop() {
+	ticker := time.NewTicker(s.cfg.PingInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-s.done:
+			return
+		case <-ticker.C:
+			s.Ping()
+		}
+	}
+}
+
+// Ping sends a server ping to every connection
+func (s *Server) Ping() {
+	msg := ws.Message{Type: "ping", Time: strconv.FormatInt(time.Now().UnixMilli(), 10)}
+	for _, sc := range s.connections(func(*serverConn) bool { return true }) {
+		_ = sc.writeJSON(msg)
+	}
+}
+
+// Pongs returns the number of pongs received in reply to server pings
+func (s *Server) Pongs() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.pongs
+}
+
+// Connections returns the number of open connections
+func (s *Server) Connections() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return len(s.conns)
+}
+
+// AcceptedConnections returns the total number of connections accepted so far
+func (s *Server) AcceptedConnections() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.accepted
+}
+
+// RejectedConnections returns the number of private connections refused during authentication
+func (s *Server) RejectedConnections() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.rejected
+}
+
+// Subscriptions returns the channels subscribed on any open connection
+func (s *Server) Subscriptions() []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	seen := make(map[string]struct{})
+	for sc := range s.conns {
+		for channel := range sc.subscriptions {
+			seen[channel] = struct{}{}
+		}
+	}
+	channels := make([]string, 0, len(seen))
+	for channel := range seen {
+		channels = append(channels, channel)
+	}
+	sort.Strings(channels)
+	return channels
+}
+
+// WaitForSubscription blocks until a subscribe request for channel arrives or timeout elapses
+func (s *Server) WaitForSubscription(channel string, timeout time.Duration) error {
+	timer := time.NewTimer(timeout)
+	defer timer.Stop()
+
+	for {
+		select {
+		case got := <-s.subscribed:
+			if got == channel {
+				return nil
+			}
+		case <-timer.C:
+			return fmt.Errorf("timeout waiting for subscription to %s", channel)
+		}
+	}
+}
+
+// SendQuote sends a quote event to every connection subscribed to channel
+func (s *Server) SendQuote(channel string, dataType string, data interface{}) error {
+	s.mu.Lock()
+	if n := s.skip[channel]; n > 0 {
+		s.skip[channel] = n - 1
+		s.mu.Unlock()
+		return nil
+	}
+	s.mu.Unlock()
+
+	event := map[string]interface{}{
+		"type":    "quote-event",
+		"channel": channel,
+		"content": map[string]interface{}{
+			"channel":  channel,
+			"dataType": dataType,
+			"data":     data,
+		},
+	}
+	return s.broadcast(event, func(sc *serverConn) bool {
+		_, ok := sc.subscriptions[channel]
+		return ok
+	})
+}
+
+// SendTicker sends a ticker snapshot for a contract
+func (s *Server) SendTicker(contractID string, ticker interface{}) error {
+	return s.SendQuote(ws.TickerChannel(contractID), "Snapshot", []interface{}{ticker})
+}
+
+// SendKLine sends a K-line update for a contract and interval
+func (s *Server) SendKLine(contractID string, interval string, kline interface{}) error {
+	return s.SendQuote(ws.KLineChannel(contractID, interval), "Changed", []interface{}{kline})
+}
+
+// SendDepth sends a depth update for a contract. depthType is "SNAPSHOT" or "CHANGED".
+func (s *Server) SendDepth(contractID string, depthType string, depth interface{}) error {
+	dataType := "Changed"
+	if depthType == "SNAPSHOT" {
+		dataType = "Snapshot"
+	}
+	return s.SendQuote(ws.DepthChannel(contractID), dataType, []interface{}{depth})
+}
+
+// SendTrades sends the latest trades for a contract
+func (s *Server) SendTrades(contractID string, trades []interface{}) error {
+	return s.SendQuote(ws.TradesChannel(contractID), "Changed", trades)
+}
+
+// SendPrivate sends a message of the given type to every private connection
+func (s *Server) SendPrivate(msgType string, content interface{}) error {
+	msg := map[string]interface{}{
+		"type":    msgType,
+		"content": content,
+	}
+	return s.broadcast(msg, func(sc *serverConn) bool {
+		return sc.private
+	})
+}
+
+// SendRaw sends an arbitrary frame to every connection
+func (s *Server) SendRaw(frame interface{}) error {
+	return s.broadcast(frame, func(*serverConn) bool { return true })
+}
+
+// Play sends frames in order, honouring each frame's delay, until done or ctx is cancelled
+func (s *Server) Play(ctx context.Context, frames []Frame) error {
+	for _, frame := range frames {
+		if frame.Delay > 0 {
+			select {
+			case <-ctx.Done():
+				return ctx.Err()
+			case <-time.After(frame.Delay):
+			}
+		}
+
+		var err error
+		if frame.Type != "" {
+			err = s.SendPrivate(frame.Type, frame.Data)
+		} else {
+			err = s.SendQuote(frame.Channel, frame.DataType, frame.Data)
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// SkipFrames silently drops the next n frames sent on channel to simulate a gap
+func (s *Server) SkipFrames(channel string, n int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.skip[channel] += n
+}
+
+// DropConnections abruptly closes every connection without a close handshake
+func (s *Server) DropConnections() {
+	for _, sc := range s.connections(func(*serverConn) bool { return true }) {
+		_ = sc.conn.Close()
+	}
+}
+
+// broadcast writes v to every connection matching filter
+func (s *Server) broadcast(v interface{}, filter func(*serverConn) bool) error {
+	var firstErr error
+	for _, sc := range s.connections(filter) {
+		if err := sc.writeJSON(v); err != nil && firstErr == nil {
+			firstErr = err
+		}
+	}
+	return firstErr
+}
+
+// connections returns the open connections matching filter
+func (s *Server) connections(filter func(*serverConn) bool) []*serverConn {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	conns := make([]*serverConn, 0, len(s.conns))
+	for sc := range s.conns {
+		if filter(sc) {
+			conns = append(conns, sc)
+		}
+	}
+	return conns
+}
@@ -0,0 +1,163 @@
+// Package starkkey derives, loads and checks Stark key pairs. Keys can be derived
+// deterministically from an Ethereum signature with the StarkEx key grinding
+// algorithm, so that a wallet can recover its L2 key at any time.
+package starkkey
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"math/big"
+	"strings"
+
+	"github.com/coin-quant/go-edgex/starkcurve"
+)
+
+// ethSignatureLength is the length of an r, s, v Ethereum signature in bytes
+const ethSignatureLength = 65
+
+// KeyPair is a Stark private key with its public key. The public key is the x
+// coordinate of the public point, which the exchange calls the l2Key.
+type KeyPair struct {
+	PrivateKey *big.Int
+	PublicKey  *big.Int
+	PublicKeyY *big.Int
+}
+
+// FromPrivateKey loads a key pair from a hex private key, with or without 0x prefix
+func FromPrivateKey(privateKey string) (*KeyPair, error) {
+	key, ok := new(big.Int).SetString(strings.TrimPrefix(strings.TrimPrefix(privateKey, "0x"), "0X"), 16)
+	if !ok {
+		return nil, fmt.Errorf("invalid stark private key")
+	}
+	return newKeyPair(key)
+}
+
+// Generate creates a random key pair. random defaults to crypto/rand.
+func Generate(random io.Reader) (*KeyPair, error) {
+	if random == nil {
+		random = rand.Reader
+	}
+	curve := starkcurve.NewStarkCurve()
+	for {
+		key, err := rand.Int(random, curve.N)
+		if err != nil {
+			return nil, fmt.Errorf("failed to generate stark private key: %w", err)
+		}
+		if key.Sign() > 0 {
+			return newKeyPair(key)
+		}
+	}
+}
+
+// FromEthSignature derives the key pair of an Ethereum signature, given as hex r, s
+// and v. The r component seeds GrindKey, as in the StarkEx key derivation.
+func FromEthSignature(signature string) (*KeyPair, error) {
+	sig, err := hex.DecodeString(strings.TrimPrefix(signature, "0x"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid eth signature: %w", err)
+	}
+	if len(sig) != ethSignatureLength {
+		return nil, fmt.Errorf("invalid eth signature length: %d", len(sig))
+	}
+	return newKeyPair(GrindKey(sig[:32]))
+}
+
+// GrindKey derives a private key from a seed. It hashes the seed with an increasing
+// index until the SHA-256 digest falls below the largest multiple of the curve order,
+// so that reducing it modulo the order is unbiased.
+func GrindKey(seed []byte) *big.Int {
+	order := starkcurve.NewStarkCurve().N
+	digestLimit := new(big.Int).Lsh(big.NewInt(1), 256)
+	maxAllowed := new(big.Int).Sub(digestLimit, new(big.Int).Mod(digestLimit, order))
+
+	for index := int64(0); ; index++ {
+		indexBytes := big.NewInt(index).Bytes()
+		if len(indexBytes) == 0 {
+			indexBytes = []byte{0}
+		}
+		digest := sha256.Sum256(append(append([]byte{}, seed...), indexBytes...))
+		key := new(big.Int).SetBytes(digest[:])
+		if key.Cmp(maxAllowed) < 0 {
+			return key.Mod(key, order)
+		}
+	}
+}
+
+// YCoordinates returns the two y coordinates of the curve point with the given hex x
+// coordinate, the lower one first
+func YCoordinates(publicKey string) (*big.Int, *big.Int, error) {
+	x, ok := new(big.Int).SetString(strings.TrimPrefix(publicKey, "0x"), 16)
+	if !ok {
+		return nil, nil, fmt.Errorf("invalid stark public key: %s", publicKey)
+	}
+	curve := starkcurve.NewStarkCurve()
+	// y² = x³ + x + b
+	rhs := new(big.Int).Mul(x, x)
+	rhs.Mul(rhs, x)
+	rhs.Add(rhs, x)
+	rhs.Add(rhs, curve.B)
+	rhs.Mod(rhs, curve.P)
+	y := new(big.Int).ModSqrt(rhs, curve.P)
+	if y == nil {
+		return nil, nil, fmt.Errorf("stark public key not on curve: %s", publicKey)
+	}
+	minusY := new(big.Int).Sub(curve.P, y)
+	if minusY.Cmp(y) < 0 {
+		return minusY, y, nil
+	}
+	return y, minusY, nil
+}
+
+// PrivateKeyHex returns the private key as 64 hex digits, as ClientConfig.StarkPriKey expects
+func (k *KeyPair) PrivateKeyHex() string {
+	return fmt.Sprintf("%064x", k.PrivateKey)
+}
+
+// PublicKeyHex returns the public key in the 0x prefixed l2Key format
+func (k *KeyPair) PublicKeyHex() string {
+	return fmt.Sprintf("0x%064x", k.PublicKey)
+}
+
+// PublicKeyYHex returns the y coordinate of the public key in the 0x prefixed format
+func (k *KeyPair) PublicKeyYHex() string {
+	return fmt.Sprintf("0x%064x", k.PublicKeyY)
+}
+
+// Validate checks that the key pair matches an account's l2Key and, if not empty,
+// its l2KeyYCoordinate
+func (k *KeyPair) Validate(l2Key, l2KeyYCoordinate string) error {
+	x, ok := new(big.Int).SetString(strings.TrimPrefix(l2Key, "0x"), 16)
+	if !ok {
+		return fmt.Errorf("invalid l2 key: %s", l2Key)
+	}
+	if x.Cmp(k.PublicKey) != 0 {
+		return fmt.Errorf("stark key %s does not match l2 key %s", k.PublicKeyHex(), l2Key)
+	}
+	if l2KeyYCoordinate == "" {
+		return nil
+	}
+	y, ok := new(big.Int).SetString(strings.TrimPrefix(l2KeyYCoordinate, "0x"), 16)
+	if !ok {
+		return fmt.Errorf("invalid l2 key y coordinate: %s", l2KeyYCoordinate)
+	}
+	if y.Cmp(k.PublicKeyY) != 0 {
+		return fmt.Errorf("stark key y coordinate %s does not match %s", k.PublicKeyYHex(), l2KeyYCoordinate)
+	}
+	return nil
+}
+
+// newKeyPair computes the public point of a private key
+func newKeyPair(key *big.Int) (*KeyPair, error) {
+	curve := starkcurve.NewStarkCurve()
+	if key.Sign() <= 0 || key.Cmp(curve.N) >= 0 {
+		return nil, fmt.Errorf("stark private key out of range")
+	}
+	x, y := curve.ScalarBaseMult(key.Bytes())
+	if x == nil {
+		return nil, fmt.Errorf("invalid stark private key")
+	}
+	return &KeyPair{PrivateKey: key, PublicKey: x, PublicKeyY: y}, nil
+}
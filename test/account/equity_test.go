@@ -0,0 +1,102 @@
+package account
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/coin-quant/go-edgex/sdk"
+	"github.com/coin-quant/go-edgex/sdk/account"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEquityCurve(t *testing.T) {
+	day := int64(24 * 60 * 60 * 1000)
+	start := int64(1700006400000) // 2023-11-15 00:00 UTC
+	var snapshots []account.AccountAssetSnapshot
+	err := json.Unmarshal([]byte(`[
+		{"snapshotTime": "`+itoa(start+2*day)+`", "totalEquity": "900", "unrealizePnl": "-100", "totalRealizePnl": "0"},
+		{"snapshotTime": "`+itoa(start)+`", "totalEquity": "1000", "unrealizePnl": "0", "totalRealizePnl": "0"},
+		{"snapshotTime": "`+itoa(start+day)+`", "totalEquity": "1200", "unrealizePnl": "150", "totalRealizePnl": "50", "availableAmount": "700"},
+		{"snapshotTime": "`+itoa(start+4*day+60000)+`", "totalEquity": "1080", "unrealizePnl": "0", "totalRealizePnl": "80"}
+	]`), &snapshots)
+	assert.NoError(t, err)
+
+	curve := account.NewEquityCurve(snapshots, account.GranularityDay)
+	if !assert.Len(t, curve.Points, 5) {
+		return
+	}
+	assert.Equal(t, time.UnixMilli(start).UTC(), curve.Points[0].Time)
+	assert.True(t, curve.Points[3].Filled)
+	assert.True(t, decimal.RequireFromString("900").Equal(curve.Points[3].TotalEquity))
+	assert.Equal(t, time.UnixMilli(start+4*day).UTC(), curve.Points[4].Time)
+	assert.Equal(t, "700", curve.Points[1].Available.String())
+	assert.Len(t, curve.Returns, 4)
+
+	stats := curve.Stats
+	assert.True(t, decimal.RequireFromString("0.08").Equal(stats.TotalReturn))
+	assert.True(t, decimal.RequireFromString("80").Equal(stats.PnL))
+	assert.True(t, decimal.RequireFromString("300").Equal(stats.MaxDrawdown))
+	assert.True(t, decimal.RequireFromString("0.25").Equal(stats.MaxDrawdownPercent))
+	assert.Equal(t, curve.Points[1].Time, stats.PeakTime)
+	assert.Equal(t, curve.Points[2].Time, stats.TroughTime)
+
+	returns := []float64{0.2, -0.25, 0, 0.2}
+	mean := (0.2 - 0.25 + 0 + 0.2) / 4
+	var variance float64
+	for _, r := range returns {
+		variance += (r - mean) * (r - mean)
+	}
+	expected := math.Sqrt(variance / 3)
+	assert.InDelta(t, expected, stats.Volatility, 1e-9)
+	assert.InDelta(t, expected*math.Sqrt(365), stats.AnnualizedVolatility, 1e-9)
+
+	empty := account.NewEquityCurve(nil, account.GranularityHour)
+	assert.Empty(t, empty.Points)
+}
+
+func TestGetEquityCurveTimeTag(t *testing.T) {
+	var timeTags []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/api/v1/private/account/getAccountAssetSnapshotPage":
+			timeTags = append(timeTags, r.URL.Query().Get("filterTimeTag"))
+			fmt.Fprint(w, `{"code":"SUCCESS","data":{"dataList":[{"snapshotTime":"1700006400000","totalEquity":"1000"}]}}`)
+		case "/api/v1/private/account/getAccountAsset":
+			fmt.Fprint(w, `{"code":"SUCCESS","data":{"collateralAssetModelList":[{"coinId":"1000","availableAmount":"750"}]}}`)
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+
+	client, err := sdk.NewClient(&sdk.ClientConfig{BaseURL: server.URL, AccountID: 7,
+		StarkPriKey: "04a266bc1e005725a278034bc4ab0f3075a7110a47d390b0b1b7841cabac0c4d"})
+	assert.NoError(t, err)
+	ctx := context.Background()
+
+	for _, granularity := range []account.Granularity{account.GranularityHour, account.GranularityDay, account.GranularityDefault} {
+		curve, err := client.Account.GetEquityCurve(ctx, account.GetEquityCurveParams{CoinID: "1000", Granularity: granularity})
+		assert.NoError(t, err)
+		if assert.Len(t, curve.Points, 1) {
+			assert.Equal(t, "750", curve.Points[0].Available.String())
+		}
+	}
+	assert.Equal(t, []string{"0", "1", "1"}, timeTags)
+
+	// Closed windows only use the snapshots
+	curve, err := client.Account.GetEquityCurve(ctx, account.GetEquityCurveParams{CoinID: "1000", To: time.UnixMilli(1700092800000)})
+	assert.NoError(t, err)
+	assert.True(t, curve.Points[0].Available.IsZero())
+}
+
+func itoa(v int64) string {
+	b, _ := json.Marshal(v)
+	return string(b)
+}
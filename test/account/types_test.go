@@ -0,0 +1,153 @@
+package account
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/coin-quant/go-edgex/sdk/account"
+	"github.com/coin-quant/go-edgex/sdk/risk"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+)
+
+const accountAssetJSON = `{
+  "code": "SUCCESS",
+  "data": {
+    "account": {"id": "542", "l2Key": "0x1", "maxLeverageLimit": "50", "contractIdToTradeSetting": {"10000001": {"isSetMaxLeverage": true, "maxLeverage": "20"}}},
+    "collateralList": [{"coinId": "1000", "amount": "-1234.5", "cumFundingFeeAmount": "-1.25"}],
+    "positionList": [{
+      "contractId": "10000001", "coinId": "1000", "openSize": "-0.5", "openValue": "-30000", "openFee": "-3", "fundingFee": "0.5",
+      "longTermStat": {"cumOpenSize": "1"}, "shortTotalStat": {"cumCloseFee": "-2"}
+    }],
+    "version": "1024",
+    "positionAssetList": [{"contractId": "10000001", "liquidatePrice": "65000", "bankruptPrice": "66000", "unrealizePnl": "-100.5", "starkExRiskValue": "1500"}],
+    "collateralAssetModelList": [{"coinId": "1000", "totalEquity": "28665", "availableAmount": "25000.75"}],
+    "oraclePriceList": [{"contractId": "10000001", "priceType": "ORACLE_PRICE", "priceValue": "60201"}]
+  }
+}`
+
+// valueOf returns a helper that formats a decimal accessor result, failing the
+// test on a parse error
+func valueOf(t *testing.T) func(decimal.Decimal, error) string {
+	return func(d decimal.Decimal, err error) string {
+		t.Helper()
+		assert.NoError(t, err)
+		return d.String()
+	}
+}
+
+func TestAccountAssetModels(t *testing.T) {
+	value := valueOf(t)
+	var resp account.GetAccountAssetDataResponse
+	assert.NoError(t, json.Unmarshal([]byte(accountAssetJSON), &resp))
+
+	data := resp.Data
+	assert.Equal(t, "542", data.Account.ID)
+	assert.Equal(t, "20", data.Account.ContractIDToTradeSetting["10000001"].MaxLeverage)
+
+	position, ok := data.Position("10000001")
+	assert.True(t, ok)
+	assert.True(t, position.IsShort())
+	assert.Equal(t, "-0.5", value(position.OpenSizeDecimal()))
+	assert.Equal(t, "60000", value(position.AvgOpenPrice()))
+	assert.Equal(t, "0.5", value(position.FundingFeeDecimal()))
+	assert.Equal(t, "1", value(position.LongTermStat.CumOpenSizeDecimal()))
+	assert.Equal(t, "-2", value(position.ShortTotalStat.CumCloseFeeDecimal()))
+
+	positionAsset, ok := data.PositionAsset("10000001")
+	assert.True(t, ok)
+	assert.Equal(t, "65000", value(positionAsset.LiquidatePriceDecimal()))
+	assert.Equal(t, "-100.5", value(positionAsset.UnrealizePnlDecimal()))
+	assert.Equal(t, "0", value(positionAsset.WorstClosePriceDecimal()))
+
+	collateralAsset, ok := data.CollateralAsset("1000")
+	assert.True(t, ok)
+	assert.Equal(t, "25000.75", value(collateralAsset.AvailableAmountDecimal()))
+
+	collateral, ok := data.Collateral("1000")
+	assert.True(t, ok)
+	assert.Equal(t, "-1.25", value(collateral.CumFundingFeeAmountDecimal()))
+
+	price, ok := data.OraclePrice("10000001")
+	assert.True(t, ok)
+	assert.Equal(t, "60201", value(price.PriceValueDecimal()))
+
+	_, ok = data.Position("10000002")
+	assert.False(t, ok)
+
+	// GetAccountAsset keeps returning the generated model
+	var raw account.GetAccountAssetResponse
+	assert.NoError(t, json.Unmarshal([]byte(accountAssetJSON), &raw))
+	assert.Equal(t, "542", *raw.Data.Account.Id)
+	assert.Equal(t, "1024", *raw.Data.Version)
+}
+
+func TestCollateralTransactionModel(t *testing.T) {
+	value := valueOf(t)
+	var tx account.CollateralTransaction
+	err := json.Unmarshal([]byte(`{"id": "1", "type": "SETTLE_FUNDING_FEE", "deltaAmount": "-0.42", "fundingRate": "0.0001", "fundingPositionSize": "2", "censorStatus": "CENSOR_SUCCESS"}`), &tx)
+	assert.NoError(t, err)
+
+	assert.Equal(t, "-0.42", value(tx.DeltaAmountDecimal()))
+	assert.Equal(t, "0.0001", value(tx.FundingRateDecimal()))
+	assert.Equal(t, "2", value(tx.FundingPositionSizeDecimal()))
+	assert.Equal(t, "0", value(tx.FillPriceDecimal()))
+	assert.Equal(t, "CENSOR_SUCCESS", *tx.CensorStatus)
+}
+
+func TestLeverageSettings(t *testing.T) {
+	var acc account.Account
+	err := json.Unmarshal([]byte(`{
+		"id": "542",
+		"defaultTradeSetting": {"isSetMaxLeverage": true, "maxLeverage": "10"},
+		"contractIdToTradeSetting": {
+			"10000001": {"isSetMaxLeverage": true, "maxLeverage": "20"},
+			"10000002": {"isSetFeeRate": true, "takerFeeRate": "0.0005"}
+		}
+	}`), &acc)
+	assert.NoError(t, err)
+
+	settings, err := acc.LeverageSettings()
+	assert.NoError(t, err)
+	leverage, ok := settings.Leverage("10000001")
+	assert.True(t, ok)
+	assert.True(t, decimal.RequireFromString("20").Equal(leverage))
+
+	leverage, ok = settings.Leverage("10000002")
+	assert.True(t, ok)
+	assert.True(t, decimal.RequireFromString("10").Equal(leverage))
+
+	settings, err = account.Account{}.LeverageSettings()
+	assert.NoError(t, err)
+	_, ok = settings.Leverage("10000001")
+	assert.False(t, ok)
+
+	acc.ContractIDToTradeSetting["10000001"] = account.TradeSetting{IsSetMaxLeverage: true, MaxLeverage: "2O"}
+	_, err = acc.LeverageSettings()
+	assert.ErrorContains(t, err, `invalid maxLeverage "2O"`)
+}
+
+func TestMalformedDecimals(t *testing.T) {
+	position := account.Position{OpenSize: "0.5x", OpenValue: "-30000"}
+	_, err := position.OpenSizeDecimal()
+	assert.ErrorContains(t, err, `invalid openSize "0.5x"`)
+	_, err = position.AvgOpenPrice()
+	assert.Error(t, err)
+	assert.False(t, position.IsLong())
+	assert.False(t, position.IsShort())
+
+	d, err := account.Position{}.OpenSizeDecimal()
+	assert.NoError(t, err)
+	assert.True(t, d.IsZero())
+
+	var tx account.CollateralTransaction
+	assert.NoError(t, json.Unmarshal([]byte(`{"id": "1", "deltaAmount": "1,000"}`), &tx))
+	_, err = tx.DeltaAmountDecimal()
+	assert.ErrorContains(t, err, `invalid deltaAmount "1,000"`)
+
+	var resp account.GetAccountAssetDataResponse
+	assert.NoError(t, json.Unmarshal([]byte(accountAssetJSON), &resp))
+	resp.Data.CollateralList[0].Amount = "NaN"
+	_, err = risk.AccountFromAsset(resp.Data, "1000")
+	assert.ErrorContains(t, err, `invalid amount "NaN"`)
+}
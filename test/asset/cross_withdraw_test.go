@@ -0,0 +1,84 @@
+package asset
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/coin-quant/go-edgex/sdk"
+	"github.com/coin-quant/go-edgex/sdk/asset"
+	"github.com/stretchr/testify/assert"
+)
+
+const testBscUsdtAddress = "0x55d398326f99059ff775485246999027b3197955"
+
+const testCrossMetaData = `{"code":"SUCCESS","data":{
+	"global":{"starkExChainId":"1"},
+	"coinList":[{"coinId":"1000","coinName":"USDT","starkExAssetId":"0x2ce625e94458d39dd0bf3b45a843544dd4a14b8169045a3a3d15aa564b936c5","starkExResolution":"0xf4240"}],
+	"multiChain":{"coinId":"1000","minWithdraw":"10","maxWithdraw":"1000000","chainList":[
+		{"chain":"BNB Chain","chainId":"56","allowWithdraw":true,
+			"tokenList":[{"token":"USDT","tokenAddress":"` + testBscUsdtAddress + `","decimals":"18","withdrawEnable":true}]},
+		{"chain":"Arbitrum","chainId":"42161","allowWithdraw":false,
+			"tokenList":[{"token":"USDT","tokenAddress":"0x01","decimals":"6","withdrawEnable":true}]}]}}}`
+
+func TestCreateCrossWithdrawSigned(t *testing.T) {
+	var created map[string]interface{}
+	maxAmount := "20000"
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Path {
+		case "/api/v1/public/meta/getMetaData":
+			fmt.Fprint(w, testCrossMetaData)
+		case "/api/v1/private/assets/getCrossWithdrawSignInfo":
+			fmt.Fprintf(w, `{"code":"SUCCESS","data":{"lpAccountId":"551109015904453258","crossWithdrawL2Key":"0x0456",
+				"crossWithdrawMaxAmount":%q,"fee":"0.8","mpcAddress":"0xabc","mpcSignature":"0xdef","mpcSignTime":"1735887600"}}`, maxAmount)
+		case "/api/v1/private/assets/createCrossWithdraw":
+			_ = json.NewDecoder(r.Body).Decode(&created)
+			fmt.Fprint(w, `{"code":"SUCCESS","data":{"crossWithdrawId":"43"}}`)
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+
+	client, err := sdk.NewClient(&sdk.ClientConfig{BaseURL: server.URL, AccountID: 7, StarkPriKey: testStarkPrivateKey})
+	assert.NoError(t, err)
+	ctx := context.Background()
+
+	resp, err := client.CreateCrossWithdraw(ctx, &asset.CreateCrossWithdrawParams{
+		CoinId:     "1000",
+		Amount:     "500",
+		EthAddress: testEthAddress,
+		ChainId:    "56",
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, "43", *resp.Data.CrossWithdrawId)
+
+	assert.Equal(t, "500", created["amount"])
+	assert.Equal(t, "0.8", created["fee"])
+	assert.Equal(t, "56", created["chainId"])
+	assert.Equal(t, testBscUsdtAddress, created["erc20Address"])
+	assert.Equal(t, "551109015904453258", created["lpAccountId"])
+	assert.Equal(t, "0xabc", created["mpcAddress"])
+	assert.Equal(t, "0xdef", created["mpcSignature"])
+	assert.Equal(t, "1735887600", created["mpcSignTime"])
+	assert.NotEmpty(t, created["clientCrossWithdrawId"])
+	assert.Len(t, created["l2Signature"], 128)
+
+	_, err = client.CreateCrossWithdraw(ctx, &asset.CreateCrossWithdrawParams{CoinId: "1000", Amount: "5", EthAddress: testEthAddress, ChainId: "56"})
+	assert.ErrorContains(t, err, "below min withdraw")
+	_, err = client.CreateCrossWithdraw(ctx, &asset.CreateCrossWithdrawParams{CoinId: "1000", Amount: "30000", EthAddress: testEthAddress, ChainId: "56"})
+	assert.ErrorContains(t, err, "exceeds cross withdraw max amount")
+	_, err = client.CreateCrossWithdraw(ctx, &asset.CreateCrossWithdrawParams{CoinId: "1000", Amount: "50", EthAddress: testEthAddress, ChainId: "42161"})
+	assert.ErrorContains(t, err, "disabled")
+
+	// A max amount that does not parse fails closed
+	maxAmount = "20k"
+	created = nil
+	_, err = client.CreateCrossWithdraw(ctx, &asset.CreateCrossWithdrawParams{CoinId: "1000", Amount: "30000", EthAddress: testEthAddress, ChainId: "56"})
+	assert.ErrorContains(t, err, "failed to parse cross withdraw max amount")
+	assert.Nil(t, created)
+}
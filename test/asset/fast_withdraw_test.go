@@ -0,0 +1,183 @@
+package asset
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/coin-quant/go-edgex/sdk"
+	"github.com/coin-quant/go-edgex/sdk/asset"
+	"github.com/coin-quant/go-edgex/starkcurve"
+	"github.com/coin-quant/go-edgex/starkkey"
+	"github.com/stretchr/testify/assert"
+	"golang.org/x/crypto/sha3"
+)
+
+const (
+	testStarkPrivateKey = "04a266bc1e005725a278034bc4ab0f3075a7110a47d390b0b1b7841cabac0c4d"
+	testEthAddress      = "0x1fB51aa234287C3CA1F957eA9AD0E148Bb814b7A"
+	testUsdtAddress     = "0xdac17f958d2ee523a2206206994597c13d831ec7"
+	testFactRegistry    = "0xBE9a129909EbCb954bC065536D2bfAfBd170d27A"
+)
+
+const testMetaData = `{"code":"SUCCESS","data":{
+	"global":{"starkExChainId":"1","fastWithdrawAccountId":"551109015904453258",
+		"fastWithdrawAccountL2Key":"0x0123","fastWithdrawRegistryAddress":"` + testFactRegistry + `",
+		"fastWithdrawMaxAmount":"100000"},
+	"coinList":[{"coinId":"1000","coinName":"USDT","starkExAssetId":"0x2ce625e94458d39dd0bf3b45a843544dd4a14b8169045a3a3d15aa564b936c5","starkExResolution":"0xf4240"}],
+	"multiChain":{"coinId":"1000","minWithdraw":"10","maxWithdraw":"200000","chainList":[
+		{"chain":"Ethereum","chainId":"1","allowWithdraw":true,
+			"tokenList":[{"token":"USDT","tokenAddress":"` + testUsdtAddress + `","decimals":"6","withdrawEnable":true}]},
+		{"chain":"BNB Chain","chainId":"56","allowWithdraw":false,
+			"tokenList":[{"token":"USDT","tokenAddress":"` + testUsdtAddress + `","decimals":"2","withdrawEnable":true}]},
+		{"chain":"Arbitrum","chainId":"42161","allowWithdraw":true,
+			"tokenList":[{"token":"USDT","tokenAddress":"` + testUsdtAddress + `","decimals":"2","withdrawEnable":true}]}]}}}`
+
+// expectedFact recomputes the ERC20 transfer fact of a fast withdrawal
+func expectedFact(t *testing.T, recipient, amount, token, clientID string) string {
+	decode := func(address string) []byte {
+		b, err := hex.DecodeString(strings.TrimPrefix(address, "0x"))
+		assert.NoError(t, err)
+		return b
+	}
+	units, _ := big.NewInt(0).SetString(amount, 10)
+	digest := sha256.Sum256([]byte(clientID))
+	salt, _ := big.NewInt(0).SetString(hex.EncodeToString(digest[:])[:8], 16)
+
+	hash := sha3.NewLegacyKeccak256()
+	hash.Write(decode(recipient))
+	hash.Write(units.FillBytes(make([]byte, 32)))
+	hash.Write(decode(token))
+	hash.Write(salt.FillBytes(make([]byte, 32)))
+	return fmt.Sprintf("0x%x", hash.Sum(nil))
+}
+
+// expectedConditionalTransferHash recomputes the StarkEx conditional transfer hash
+func expectedConditionalTransferHash(assetID, receiverKey, condition *big.Int, sender, receiver, nonce, amount, expireHour int64) []byte {
+	hash := func(a *big.Int, b *big.Int) *big.Int {
+		return new(big.Int).SetBytes(starkcurve.CalcHash([]*big.Int{a, b}))
+	}
+	msg := hash(hash(hash(assetID, big.NewInt(0)), receiverKey), condition)
+
+	packed0 := big.NewInt(sender)
+	packed0.Lsh(packed0, 64).Add(packed0, big.NewInt(receiver))
+	packed0.Lsh(packed0, 64).Add(packed0, big.NewInt(sender))
+	packed0.Lsh(packed0, 32).Add(packed0, big.NewInt(nonce))
+	msg = hash(msg, packed0)
+
+	packed1 := big.NewInt(5) // conditional transfer
+	packed1.Lsh(packed1, 64).Add(packed1, big.NewInt(amount))
+	packed1.Lsh(packed1, 64) // max amount fee is zero
+	packed1.Lsh(packed1, 32).Add(packed1, big.NewInt(expireHour))
+	packed1.Lsh(packed1, 81)
+	return hash(msg, packed1).Bytes()
+}
+
+// verifyL2Signature checks an r||s signature of a hash against the test key
+func verifyL2Signature(t *testing.T, hash []byte, signature string) bool {
+	keyPair, err := starkkey.FromPrivateKey(testStarkPrivateKey)
+	assert.NoError(t, err)
+	r, ok := new(big.Int).SetString(signature[:64], 16)
+	assert.True(t, ok)
+	s, ok := new(big.Int).SetString(signature[64:], 16)
+	assert.True(t, ok)
+	return starkcurve.Verify(hash, keyPair.PublicKey, keyPair.PublicKeyY, r, s)
+}
+
+func TestCreateFastWithdrawSigned(t *testing.T) {
+	var created map[string]interface{}
+	var signInfoQuery string
+	maxAmount := "50000"
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Path {
+		case "/api/v1/public/meta/getMetaData":
+			fmt.Fprint(w, testMetaData)
+		case "/api/v1/private/assets/getFastWithdrawSignInfo":
+			signInfoQuery = r.URL.RawQuery
+			fmt.Fprintf(w, `{"code":"SUCCESS","data":{"lpAccountId":"1","fee":"1.5","fastWithdrawMaxAmount":%q}}`, maxAmount)
+		case "/api/v1/private/assets/createFastWithdraw":
+			_ = json.NewDecoder(r.Body).Decode(&created)
+			fmt.Fprint(w, `{"code":"SUCCESS","data":{"fastWithdrawId":"42"}}`)
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+
+	client, err := sdk.NewClient(&sdk.ClientConfig{BaseURL: server.URL, AccountID: 7, StarkPriKey: testStarkPrivateKey})
+	assert.NoError(t, err)
+	ctx := context.Background()
+
+	resp, err := client.CreateFastWithdraw(ctx, &asset.CreateFastWithdrawParams{
+		CoinId:           "1000",
+		Amount:           "250.5",
+		EthAddress:       testEthAddress,
+		ClientWithdrawId: "treasury-1",
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, "42", *resp.Data.FastWithdrawId)
+	assert.Contains(t, signInfoQuery, "chainId=1")
+
+	assert.Equal(t, "7", created["accountId"])
+	assert.Equal(t, "250.5", created["amount"])
+	assert.Equal(t, "1.5", created["fee"])
+	assert.Equal(t, "1", created["chainId"])
+	assert.Equal(t, testUsdtAddress, created["erc20Address"])
+	assert.Equal(t, "551109015904453258", created["lpAccountId"])
+	assert.Equal(t, testFactRegistry, created["factRegistryAddress"])
+	assert.Equal(t, "treasury-1", created["clientFastWithdrawId"])
+	assert.Equal(t, expectedFact(t, testEthAddress, "250500000", testUsdtAddress, "treasury-1"), created["fact"])
+	if assert.Len(t, created["l2Signature"], 128) {
+		fact, _ := new(big.Int).SetString(strings.TrimPrefix(created["fact"].(string), "0x"), 16)
+		registry, _ := hex.DecodeString(strings.TrimPrefix(testFactRegistry, "0x"))
+		conditionHash := sha3.NewLegacyKeccak256()
+		conditionHash.Write(registry)
+		conditionHash.Write(fact.FillBytes(make([]byte, 32)))
+		condition := new(big.Int).SetBytes(conditionHash.Sum(nil))
+		condition.And(condition, new(big.Int).Sub(new(big.Int).Lsh(big.NewInt(1), 250), big.NewInt(1)))
+
+		assetID, _ := new(big.Int).SetString("2ce625e94458d39dd0bf3b45a843544dd4a14b8169045a3a3d15aa564b936c5", 16)
+		expireTime, _ := strconv.ParseInt(created["expireTime"].(string), 10, 64)
+		digest := sha256.Sum256([]byte("treasury-1"))
+		nonce, _ := strconv.ParseInt(hex.EncodeToString(digest[:])[:8], 16, 64)
+		hash := expectedConditionalTransferHash(assetID, big.NewInt(0x0123), condition,
+			7, 551109015904453258, nonce, 252000000, expireTime/(60*60*1000))
+		assert.True(t, verifyL2Signature(t, hash, created["l2Signature"].(string)))
+
+		// A transfer to another receiver does not verify
+		other := expectedConditionalTransferHash(assetID, big.NewInt(0x0124), condition,
+			7, 551109015904453258, nonce, 252000000, expireTime/(60*60*1000))
+		assert.False(t, verifyL2Signature(t, other, created["l2Signature"].(string)))
+	}
+
+	_, err = client.CreateFastWithdraw(ctx, &asset.CreateFastWithdrawParams{CoinId: "1000", Amount: "60000", EthAddress: testEthAddress})
+	assert.ErrorContains(t, err, "exceeds fast withdraw max amount")
+
+	_, err = client.CreateFastWithdraw(ctx, &asset.CreateFastWithdrawParams{CoinId: "1000", Amount: "1", EthAddress: testEthAddress, ChainId: "56"})
+	assert.ErrorContains(t, err, "disabled on chain")
+
+	_, err = client.CreateFastWithdraw(ctx, &asset.CreateFastWithdrawParams{CoinId: "1000", Amount: "1.123", EthAddress: testEthAddress, ChainId: "42161"})
+	assert.ErrorContains(t, err, "below min withdraw")
+
+	_, err = client.CreateFastWithdraw(ctx, &asset.CreateFastWithdrawParams{CoinId: "1000", Amount: "20.123", EthAddress: testEthAddress, ChainId: "42161"})
+	assert.ErrorContains(t, err, "more decimals than token")
+
+	_, err = client.CreateFastWithdraw(ctx, &asset.CreateFastWithdrawParams{CoinId: "1000", Amount: "1", EthAddress: testEthAddress, ChainId: "10"})
+	assert.ErrorContains(t, err, "chain not found")
+
+	// A max amount that does not parse fails closed
+	maxAmount = "50,000"
+	created = nil
+	_, err = client.CreateFastWithdraw(ctx, &asset.CreateFastWithdrawParams{CoinId: "1000", Amount: "60000", EthAddress: testEthAddress})
+	assert.ErrorContains(t, err, "failed to parse fast withdraw max amount")
+	assert.Nil(t, created)
+}
@@ -0,0 +1,85 @@
+package asset
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/coin-quant/go-edgex/sdk"
+	"github.com/coin-quant/go-edgex/sdk/asset"
+	"github.com/coin-quant/go-edgex/sdk/metadata"
+	"github.com/coin-quant/go-edgex/sdk/transfer"
+	"github.com/stretchr/testify/assert"
+)
+
+// testResolutionMetaData lists the USDT collateral with 6 decimals and a coin with 8
+func testResolutionMetaData() *metadata.MetaData {
+	usdt := metadata.Coin{
+		CoinId:            "1000",
+		CoinName:          "USDT",
+		StepSize:          "0.000001",
+		StarkExAssetId:    "0x2ce625e94458d39dd0bf3b45a843544dd4a14b8169045a3a3d15aa564b936c5",
+		StarkExResolution: "0xf4240",
+	}
+	wbtc := metadata.Coin{
+		CoinId:            "2000",
+		CoinName:          "WBTC",
+		StepSize:          "0.0001",
+		StarkExAssetId:    "0x0400000000000000000000000000000000000000000000000000000000000001",
+		StarkExResolution: "0x5f5e100",
+	}
+	return &metadata.MetaData{
+		Global:   &metadata.Global{StarkExCollateralCoin: &usdt},
+		CoinList: []metadata.Coin{usdt, wbtc},
+	}
+}
+
+func TestAmountResolution(t *testing.T) {
+	var created []map[string]interface{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var body map[string]interface{}
+		_ = json.NewDecoder(r.Body).Decode(&body)
+		created = append(created, body)
+		fmt.Fprint(w, `{"code":"SUCCESS","data":{}}`)
+	}))
+	defer server.Close()
+
+	client, err := sdk.NewClient(&sdk.ClientConfig{BaseURL: server.URL, AccountID: 7, StarkPriKey: testStarkPrivateKey})
+	assert.NoError(t, err)
+	ctx := context.Background()
+	md := testResolutionMetaData()
+
+	withdraw := func(coinId, amount string) error {
+		_, err := client.Asset.CreateNormalWithdraw(ctx, &asset.CreateNormalWithdrawParams{CoinId: coinId, Amount: amount, EthAddress: testEthAddress}, md)
+		return err
+	}
+	assert.NoError(t, withdraw("1000", "12.345678"))
+	assert.ErrorContains(t, withdraw("1000", "12.3456789"), "step size")
+	assert.NoError(t, withdraw("2000", "0.1234"))
+	assert.ErrorContains(t, withdraw("2000", "0.12345"), "step size")
+
+	transferOut := func(coinId, amount string) error {
+		_, err := client.Transfer.CreateTransferOut(ctx, &transfer.CreateTransferOutParams{
+			CoinId:            coinId,
+			Amount:            amount,
+			ReceiverAccountId: "8",
+			ReceiverL2Key:     "0x0123",
+			TransferReason:    transfer.USER_TRANSFER.String(),
+			ExpireTime:        time.Now(),
+		}, md)
+		return err
+	}
+	assert.NoError(t, transferOut("2000", "1.5"))
+	assert.NoError(t, transferOut("", "1.5"))
+	assert.ErrorContains(t, transferOut("3000", "1"), "coin not found")
+	assert.ErrorContains(t, transferOut("1000", "0"), "positive")
+
+	if assert.Len(t, created, 4) {
+		assert.Equal(t, "2000", created[2]["coinId"])
+		assert.Equal(t, "1000", created[3]["coinId"])
+	}
+}
@@ -0,0 +1,128 @@
+package deposit
+
+import (
+	"context"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/coin-quant/go-edgex/sdk"
+	"github.com/coin-quant/go-edgex/sdk/deposit"
+	"github.com/coin-quant/go-edgex/sdk/metadata"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+)
+
+const testStarkPrivateKey = "04a266bc1e005725a278034bc4ab0f3075a7110a47d390b0b1b7841cabac0c4d"
+
+func TestStatus(t *testing.T) {
+	assert.False(t, deposit.StatusPendingCensoring.IsTerminal(false))
+	assert.True(t, deposit.StatusSuccessCensorSuccess.IsTerminal(false))
+	assert.False(t, deposit.StatusSuccessCensorSuccess.IsTerminal(true))
+	assert.True(t, deposit.StatusSuccessL2Approved.IsTerminal(true))
+	assert.True(t, deposit.StatusFailedCensorFailure.IsTerminal(true))
+	assert.False(t, deposit.StatusFailedL2Reject.IsTerminal(true))
+	assert.True(t, deposit.StatusFailedL2Reject.IsFailed())
+}
+
+func TestWaitForDeposit(t *testing.T) {
+	statuses := []string{"PENDING_CENSORING", "PENDING_CENSORING", "SUCCESS_CENSOR_SUCCESS", "SUCCESS_L2_APPROVED"}
+	polls := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/v1/private/deposit/getDepositById" {
+			http.NotFound(w, r)
+			return
+		}
+		status := statuses[len(statuses)-1]
+		if polls < len(statuses) {
+			status = statuses[polls]
+		}
+		polls++
+		fmt.Fprintf(w, `{"code":"SUCCESS","data":[{"id":"%s","status":"%s"}]}`, r.URL.Query().Get("depositIdList"), status)
+	}))
+	defer server.Close()
+
+	client, err := sdk.NewClient(&sdk.ClientConfig{BaseURL: server.URL, AccountID: 7, StarkPriKey: testStarkPrivateKey})
+	assert.NoError(t, err)
+
+	var seen []deposit.Status
+	d, err := client.WaitForDeposit(context.Background(), "9", deposit.WaitParams{
+		PollInterval: time.Millisecond,
+		WaitForL2:    true,
+		OnStatus:     func(d *deposit.Deposit) { seen = append(seen, d.GetStatus()) },
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, deposit.StatusSuccessL2Approved, d.GetStatus())
+	assert.Equal(t, []deposit.Status{deposit.StatusPendingCensoring, deposit.StatusSuccessCensorSuccess, deposit.StatusSuccessL2Approved}, seen)
+	assert.Equal(t, 4, polls)
+}
+
+func TestWaitForDepositFailed(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"code":"SUCCESS","data":[{"id":"9","status":"FAILED_CENSOR_FAILURE","censorFailCode":"RISK","censorFailReason":"blocked address"}]}`)
+	}))
+	defer server.Close()
+
+	client, err := sdk.NewClient(&sdk.ClientConfig{BaseURL: server.URL, AccountID: 7, StarkPriKey: testStarkPrivateKey})
+	assert.NoError(t, err)
+
+	d, err := client.Deposit.WaitForDeposit(context.Background(), "9", deposit.WaitParams{PollInterval: time.Millisecond})
+	assert.ErrorContains(t, err, "blocked address")
+	assert.Equal(t, deposit.StatusFailedCensorFailure, d.GetStatus())
+}
+
+func TestBuildDepositCalldata(t *testing.T) {
+	md := &metadata.MetaData{
+		Global: &metadata.Global{StarkExChainId: "1", StarkExContractAddress: "0xfAaE2946e846133af314d1Df13684c89fA7d83DD"},
+		CoinList: []metadata.Coin{{
+			CoinId:            "1000",
+			CoinName:          "USDT",
+			StarkExAssetId:    "0x2ce625e94458d39dd0bf3b45a843544dd4a14b8169045a3a3d15aa564b936c5",
+			StarkExResolution: "0xf4240",
+		}},
+		MultiChain: &metadata.MultiChain{ChainList: []metadata.Chain{{
+			ChainId:   "1",
+			TokenList: []metadata.MultiChainToken{{Token: "USDT", TokenAddress: "0xdac17f958d2ee523a2206206994597c13d831ec7", Decimals: "6"}},
+		}}},
+	}
+
+	calldata, err := deposit.BuildDepositCalldata(md, "1000", "0x0123", 42, decimal.RequireFromString("12.5"))
+	assert.NoError(t, err)
+	assert.Equal(t, "12500000", calldata.QuantizedAmount.String())
+	assert.Equal(t, "12500000", calldata.TokenAmount.String())
+
+	assert.Equal(t, "0xdac17f958d2ee523a2206206994597c13d831ec7", calldata.Approve.To)
+	assert.Equal(t, "0x095ea7b3"+
+		"000000000000000000000000faae2946e846133af314d1df13684c89fa7d83dd"+
+		"0000000000000000000000000000000000000000000000000000000000bebc20", calldata.Approve.DataHex())
+
+	assert.Equal(t, md.Global.StarkExContractAddress, calldata.Deposit.To)
+	data := calldata.Deposit.Data
+	assert.Len(t, data, 4+4*32)
+	assert.Equal(t, "2505c3d9", hex.EncodeToString(data[:4]))
+	assert.Equal(t, "0000000000000000000000000000000000000000000000000000000000000123", hex.EncodeToString(data[4:36]))
+	assert.Equal(t, "02ce625e94458d39dd0bf3b45a843544dd4a14b8169045a3a3d15aa564b936c5", hex.EncodeToString(data[36:68]))
+	assert.Equal(t, "000000000000000000000000000000000000000000000000000000000000002a", hex.EncodeToString(data[68:100]))
+
+	_, err = deposit.BuildDepositCalldata(md, "1000", "0x0123", 42, decimal.RequireFromString("0.0000001"))
+	assert.ErrorContains(t, err, "resolution")
+
+	// Stark keys are field elements, below 2^251
+	_, err = deposit.BuildDepositCalldata(md, "1000", "0x"+strings.Repeat("f", 66), 42, decimal.NewFromInt(1))
+	assert.ErrorContains(t, err, "invalid stark key")
+	_, err = deposit.BuildDepositCalldata(md, "1000", "0x8"+strings.Repeat("0", 62), 42, decimal.NewFromInt(1))
+	assert.ErrorContains(t, err, "invalid stark key")
+	_, err = deposit.BuildDepositCalldata(md, "1000", "0x7"+strings.Repeat("f", 62), 42, decimal.NewFromInt(1))
+	assert.NoError(t, err)
+
+	// The approval is never smaller than the deposit
+	md.MultiChain.ChainList[0].TokenList[0].Decimals = "2"
+	_, err = deposit.BuildDepositCalldata(md, "1000", "0x0123", 42, decimal.RequireFromString("1.005"))
+	assert.ErrorContains(t, err, "more decimals than token")
+	_, err = deposit.BuildDepositCalldata(md, "2000", "0x0123", 42, decimal.NewFromInt(1))
+	assert.ErrorContains(t, err, "coin not found")
+}
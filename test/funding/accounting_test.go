@@ -0,0 +1,77 @@
+package funding
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/coin-quant/go-edgex/sdk/account"
+	"github.com/coin-quant/go-edgex/sdk/funding"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+)
+
+func d(value string) decimal.Decimal {
+	return decimal.RequireFromString(value)
+}
+
+func TestSummarizeFundingTransactions(t *testing.T) {
+	var txs []account.CollateralTransaction
+	err := json.Unmarshal([]byte(`[
+		{"id": "1", "type": "SETTLE_FUNDING_FEE", "coinId": "1000", "positionContractId": "10000001", "deltaAmount": "-6", "fundingRate": "0.0001", "fundingPositionSize": "1", "fundingOraclePrice": "60000", "fundingTime": "1699985600000"},
+		{"id": "2", "type": "SETTLE_FUNDING_FEE", "coinId": "1000", "positionContractId": "10000001", "deltaAmount": "-3", "fundingRate": "0.00005", "fundingPositionSize": "1", "fundingOraclePrice": "60000", "fundingTime": "1700000000000"},
+		{"id": "3", "type": "SETTLE_FUNDING_FEE", "coinId": "1000", "positionContractId": "10000002", "deltaAmount": "1.5", "fundingRate": "0.0001", "fundingPositionSize": "-5", "fundingOraclePrice": "3000", "fundingTime": "1700100000000"},
+		{"id": "4", "type": "DEPOSIT", "coinId": "1000", "deltaAmount": "1000"}
+	]`), &txs)
+	assert.NoError(t, err)
+
+	var payments []funding.Payment
+	for _, tx := range txs {
+		payment, ok, err := funding.PaymentFromTransaction(tx)
+		assert.NoError(t, err)
+		if ok {
+			payments = append(payments, payment)
+		}
+	}
+	assert.Len(t, payments, 3)
+	assert.Equal(t, time.UnixMilli(1699985600000).UTC(), payments[0].Time)
+	assert.True(t, d("60000").Equal(payments[0].OraclePrice))
+
+	summary := funding.Summarize(payments)
+	assert.True(t, d("-7.5").Equal(summary.Total))
+	assert.True(t, d("-9").Equal(summary.Paid))
+	assert.True(t, d("1.5").Equal(summary.Received))
+	assert.True(t, d("-9").Equal(summary.ByContract["10000001"]))
+
+	if assert.Len(t, summary.Daily, 2) {
+		assert.Equal(t, "10000001", summary.Daily[0].ContractID)
+		assert.Equal(t, 2, summary.Daily[0].Payments)
+		assert.Equal(t, time.Date(2023, 11, 14, 0, 0, 0, 0, time.UTC), summary.Daily[0].Day)
+		assert.Equal(t, "10000002", summary.Daily[1].ContractID)
+	}
+}
+
+func TestEstimatePayment(t *testing.T) {
+	contractID, oraclePrice, predicted := "10000001", "60000", "0.0002"
+	timestamp, interval := "1700000000000", "240"
+	rate := funding.FundingRate{
+		ContractId:             &contractID,
+		OraclePrice:            &oraclePrice,
+		PredictedFundingRate:   &predicted,
+		FundingTimestamp:       &timestamp,
+		FundingRateIntervalMin: &interval,
+	}
+
+	long, err := funding.EstimatePayment(rate, d("2"))
+	assert.NoError(t, err)
+	assert.True(t, d("-24").Equal(long.Amount))
+	assert.Equal(t, time.UnixMilli(1700014400000).UTC(), long.FundingTime)
+
+	short, err := funding.EstimatePayment(rate, d("-2"))
+	assert.NoError(t, err)
+	assert.True(t, d("24").Equal(short.Amount))
+
+	rate.PredictedFundingRate = nil
+	_, err = funding.EstimatePayment(rate, d("1"))
+	assert.Error(t, err)
+}
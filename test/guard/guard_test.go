@@ -0,0 +1,235 @@
+package guard
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"runtime"
+	"testing"
+	"time"
+
+	"github.com/coin-quant/go-edgex/sdk/account"
+	"github.com/coin-quant/go-edgex/sdk/guard"
+	"github.com/coin-quant/go-edgex/sdk/order"
+	"github.com/coin-quant/go-edgex/sdk/portfolio"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+)
+
+type fakeExchange struct {
+	created   int
+	cancelled int
+}
+
+func (f *fakeExchange) CreateOrder(ctx context.Context, params *order.CreateOrderParams) (*order.ResultCreateOrder, error) {
+	f.created++
+	id := fmt.Sprintf("%d", f.created)
+	return &order.ResultCreateOrder{Code: order.ResponseCodeSuccess, Data: &order.CreateOrder{OrderId: &id}}, nil
+}
+
+func (f *fakeExchange) CancelAllOrders(ctx context.Context, contractIDs []string) (interface{}, error) {
+	f.cancelled++
+	return nil, nil
+}
+
+func d(value string) decimal.Decimal {
+	return decimal.RequireFromString(value)
+}
+
+func testPortfolio() *portfolio.Portfolio {
+	p := portfolio.New(portfolio.Config{CoinID: "1000"})
+	p.Load(account.AccountAssetData{
+		CollateralList:  []account.Collateral{{CoinID: "1000", Amount: "-50000"}},
+		PositionList:    []account.Position{{CoinID: "1000", ContractID: "10000001", OpenSize: "1", OpenValue: "60000"}},
+		OraclePriceList: []account.OraclePrice{{ContractID: "10000001", PriceValue: "60000"}},
+	})
+	return p
+}
+
+func limitOrder(side, size, price string) *order.CreateOrderParams {
+	return &order.CreateOrderParams{ContractId: "10000001", Type: order.OrderTypeLimit, Side: side, Size: size, Price: price}
+}
+
+func assertLimit(t *testing.T, expected guard.Limit, err error) {
+	t.Helper()
+	var limitErr *guard.LimitError
+	if assert.True(t, errors.As(err, &limitErr), "expected a limit error, got %v", err) {
+		assert.Equal(t, expected, limitErr.Limit)
+	}
+}
+
+func TestGuardLimits(t *testing.T) {
+	exchange := &fakeExchange{}
+	g := guard.New(exchange, exchange, testPortfolio(), guard.Limits{
+		MaxOrderNotional:       d("200000"),
+		DefaultMaxPositionSize: d("2"),
+		MaxGrossExposure:       d("150000"),
+		PriceCollar:            d("0.05"),
+	})
+
+	assertLimit(t, guard.LimitOrderNotional, g.Check(limitOrder(order.OrderSideBuy, "4", "60000")))
+	assertLimit(t, guard.LimitPriceCollar, g.Check(limitOrder(order.OrderSideBuy, "0.1", "64000")))
+	assertLimit(t, guard.LimitPositionSize, g.Check(limitOrder(order.OrderSideBuy, "1.5", "60000")))
+	assert.NoError(t, g.Check(limitOrder(order.OrderSideBuy, "1", "60000")))
+
+	// Reducing a position is allowed even above the size limit, flipping it is not
+	assert.NoError(t, g.Check(limitOrder(order.OrderSideSell, "1", "60000")))
+	assertLimit(t, guard.LimitPositionSize, g.Check(limitOrder(order.OrderSideSell, "3.1", "60000")))
+
+	// Market orders are valued at the reference price
+	market := &order.CreateOrderParams{ContractId: "10000001", Type: order.OrderTypeMarket, Side: order.OrderSideBuy, Size: "3.5"}
+	assertLimit(t, guard.LimitOrderNotional, g.Check(market))
+
+	result, err := g.CreateOrder(context.Background(), limitOrder(order.OrderSideBuy, "0.5", "60000"))
+	assert.NoError(t, err)
+	assert.Equal(t, "1", *result.Data.OrderId)
+	assert.Equal(t, 1, g.OpenOrders())
+}
+
+func TestGuardGrossExposure(t *testing.T) {
+	exchange := &fakeExchange{}
+	g := guard.New(exchange, exchange, testPortfolio(), guard.Limits{MaxGrossExposure: d("100000")})
+
+	assertLimit(t, guard.LimitGrossExposure, g.Check(limitOrder(order.OrderSideBuy, "1", "60000")))
+	assert.NoError(t, g.Check(limitOrder(order.OrderSideBuy, "0.5", "60000")))
+}
+
+func TestGuardOrderRateAndOpenOrders(t *testing.T) {
+	exchange := &fakeExchange{}
+	g := guard.New(exchange, exchange, nil, guard.Limits{MaxOrdersPerSecond: 2, MaxOpenOrders: 3})
+	now := time.Unix(1700000000, 0)
+	g.SetClock(func() time.Time { return now })
+	ctx := context.Background()
+
+	_, err := g.CreateOrder(ctx, limitOrder(order.OrderSideBuy, "1", "100"))
+	assert.NoError(t, err)
+	_, err = g.CreateOrder(ctx, limitOrder(order.OrderSideBuy, "1", "100"))
+	assert.NoError(t, err)
+	_, err = g.CreateOrder(ctx, limitOrder(order.OrderSideBuy, "1", "100"))
+	assertLimit(t, guard.LimitOrderRate, err)
+
+	now = now.Add(1500 * time.Millisecond)
+	_, err = g.CreateOrder(ctx, limitOrder(order.OrderSideBuy, "1", "100"))
+	assert.NoError(t, err)
+	_, err = g.CreateOrder(ctx, limitOrder(order.OrderSideBuy, "1", "100"))
+	assertLimit(t, guard.LimitOpenOrders, err)
+	assert.Equal(t, 3, exchange.created)
+
+	g.HandlePrivateMessage([]byte(`{"type":"trade-event","content":{"event":"ORDER_UPDATE","data":{"order":[{"id":"2","status":"FILLED"},{"id":"3","status":"OPEN"}]}}}`))
+	assert.Equal(t, 2, g.OpenOrders())
+	_, err = g.CreateOrder(ctx, limitOrder(order.OrderSideBuy, "1", "100"))
+	assert.NoError(t, err)
+
+	// A rejected order frees its slot
+	now = now.Add(1500 * time.Millisecond)
+	_, err = g.CreateOrder(ctx, limitOrder(order.OrderSideBuy, "1", "100"))
+	assertLimit(t, guard.LimitOpenOrders, err)
+	g.HandlePrivateMessage([]byte(`{"type":"trade-event","content":{"event":"ORDER_UPDATE","data":{"order":[{"id":"4","status":"CANCELED","cancelReason":"MARGIN_NOT_ENOUGH"},{"id":"1","status":"UNTRIGGERED"}]}}}`))
+	assert.Equal(t, 2, g.OpenOrders())
+	_, err = g.CreateOrder(ctx, limitOrder(order.OrderSideBuy, "1", "100"))
+	assert.NoError(t, err)
+}
+
+// anonymousExchange accepts orders without returning their IDs, so that the guard
+// does not track them as open
+type anonymousExchange struct{}
+
+func (anonymousExchange) CreateOrder(ctx context.Context, params *order.CreateOrderParams) (*order.ResultCreateOrder, error) {
+	return &order.ResultCreateOrder{Code: order.ResponseCodeSuccess}, nil
+}
+
+func TestGuardWithoutRateLimitKeepsNoHistory(t *testing.T) {
+	g := guard.New(anonymousExchange{}, &fakeExchange{}, nil, guard.Limits{})
+	ctx := context.Background()
+	params := limitOrder(order.OrderSideBuy, "1", "100")
+
+	var before, after runtime.MemStats
+	runtime.GC()
+	runtime.ReadMemStats(&before)
+	for i := 0; i < 200000; i++ {
+		_, err := g.CreateOrder(ctx, params)
+		if !assert.NoError(t, err) {
+			return
+		}
+	}
+	runtime.GC()
+	runtime.ReadMemStats(&after)
+	// Keeping every send time would hold several megabytes
+	assert.Less(t, int64(after.HeapAlloc)-int64(before.HeapAlloc), int64(1<<20))
+	runtime.KeepAlive(g)
+}
+
+func TestIsTerminalOrderStatus(t *testing.T) {
+	for _, status := range []string{order.OrderStatusFilled, order.OrderStatusCanceled, order.OrderStatusUnknown, "UNRECOGNIZED"} {
+		assert.True(t, order.IsTerminalOrderStatus(status), status)
+	}
+	for _, status := range []string{order.OrderStatusPending, order.OrderStatusOpen, order.OrderStatusCanceling, order.OrderStatusUntriggered} {
+		assert.False(t, order.IsTerminalOrderStatus(status), status)
+	}
+}
+
+func TestGuardKillSwitch(t *testing.T) {
+	exchange := &fakeExchange{}
+	p := testPortfolio()
+	g := guard.New(exchange, exchange, p, guard.Limits{
+		MaxDailyLoss: d("1000"),
+		PriceCollar:  d("0.05"),
+		KillOn:       []guard.Limit{guard.LimitPriceCollar, guard.LimitDailyLoss},
+	})
+	ctx := context.Background()
+
+	var reasons []error
+	g.OnTrip(func(reason error) { reasons = append(reasons, reason) })
+
+	// Sets the daily baseline at the current equity
+	assert.NoError(t, g.Check(limitOrder(order.OrderSideBuy, "0.1", "60000")))
+
+	_, err := g.CreateOrder(ctx, limitOrder(order.OrderSideBuy, "0.1", "70000"))
+	assertLimit(t, guard.LimitPriceCollar, err)
+	assert.Equal(t, 1, exchange.cancelled)
+	assert.Len(t, reasons, 1)
+	assertLimit(t, guard.LimitPriceCollar, g.Engaged())
+
+	_, err = g.CreateOrder(ctx, limitOrder(order.OrderSideBuy, "0.1", "60000"))
+	assert.ErrorIs(t, err, guard.ErrKillSwitch)
+	assert.Equal(t, 0, exchange.created)
+
+	g.Reset()
+	assert.NoError(t, g.Engaged())
+
+	// Equity drops by 1500 from the baseline
+	p.UpdatePrice("10000001", d("58500"))
+	_, err = g.CreateOrder(ctx, limitOrder(order.OrderSideBuy, "0.1", "58500"))
+	assertLimit(t, guard.LimitDailyLoss, err)
+	assert.Equal(t, 2, exchange.cancelled)
+
+	g.Reset()
+	g.ResetDailyBaseline()
+	_, err = g.CreateOrder(ctx, limitOrder(order.OrderSideBuy, "0.1", "58500"))
+	assert.NoError(t, err)
+}
+
+func TestGuardDailyLossWaitsForPrices(t *testing.T) {
+	exchange := &fakeExchange{}
+	p := portfolio.New(portfolio.Config{CoinID: "1000", PriceSource: portfolio.PriceLast})
+	p.Load(account.AccountAssetData{
+		CollateralList: []account.Collateral{{CoinID: "1000", Amount: "-50000"}},
+		PositionList:   []account.Position{{CoinID: "1000", ContractID: "10000001", OpenSize: "1", OpenValue: "60000"}},
+	})
+	g := guard.New(exchange, exchange, p, guard.Limits{
+		MaxDailyLoss: d("1000"),
+		KillOn:       []guard.Limit{guard.LimitDailyLoss},
+	})
+	p.OnChange(g.HandlePortfolioEvent)
+
+	// Without a price the position is missing from equity, which is not a loss
+	assert.NoError(t, g.Check(limitOrder(order.OrderSideBuy, "0.1", "60000")))
+	g.ResetDailyBaseline()
+	assert.NoError(t, g.Engaged())
+
+	// The baseline is taken once the price is known
+	p.UpdatePrice("10000001", d("60000"))
+	p.UpdatePrice("10000001", d("58500"))
+	assert.Eventually(t, func() bool { return g.Engaged() != nil }, time.Second, 10*time.Millisecond)
+	assertLimit(t, guard.LimitDailyLoss, g.Engaged())
+}
@@ -0,0 +1,129 @@
+package keystore
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/coin-quant/go-edgex/keystore"
+	"github.com/coin-quant/go-edgex/sdk"
+	"github.com/coin-quant/go-edgex/starkkey"
+	"github.com/stretchr/testify/assert"
+)
+
+const testStarkPrivateKey = "04a266bc1e005725a278034bc4ab0f3075a7110a47d390b0b1b7841cabac0c4d"
+
+func testKey(t *testing.T) *keystore.Key {
+	keyPair, err := starkkey.FromPrivateKey(testStarkPrivateKey)
+	assert.NoError(t, err)
+	return &keystore.Key{KeyPair: keyPair, AccountID: 7, Environment: "testnet"}
+}
+
+func TestEncryptDecrypt(t *testing.T) {
+	key := testKey(t)
+	data, err := keystore.Encrypt(key, "secret", keystore.LightScryptParams)
+	assert.NoError(t, err)
+	assert.NotContains(t, string(data), testStarkPrivateKey)
+
+	var stored map[string]interface{}
+	assert.NoError(t, json.Unmarshal(data, &stored))
+	assert.Equal(t, float64(3), stored["version"])
+	assert.Equal(t, key.KeyPair.PublicKeyHex(), stored["publicKey"])
+	assert.Equal(t, "7", stored["accountId"])
+	assert.Equal(t, "testnet", stored["environment"])
+	assert.NotEmpty(t, stored["id"])
+
+	loaded, err := keystore.Decrypt(data, "secret")
+	assert.NoError(t, err)
+	assert.Equal(t, testStarkPrivateKey, loaded.KeyPair.PrivateKeyHex())
+	assert.Equal(t, int64(7), loaded.AccountID)
+	assert.Equal(t, "testnet", loaded.Environment)
+	assert.Equal(t, stored["id"], loaded.ID)
+
+	_, err = keystore.Decrypt(data, "wrong")
+	assert.ErrorIs(t, err, keystore.ErrDecrypt)
+
+	// A keystore claiming another public key is rejected
+	other, err := starkkey.Generate(nil)
+	assert.NoError(t, err)
+	stored["publicKey"] = other.PublicKeyHex()
+	tampered, _ := json.Marshal(stored)
+	_, err = keystore.Decrypt(tampered, "secret")
+	assert.ErrorIs(t, err, keystore.ErrDecrypt)
+
+	_, err = keystore.Encrypt(&keystore.Key{}, "secret", keystore.LightScryptParams)
+	assert.Error(t, err)
+}
+
+func TestDecryptTamperedMetadata(t *testing.T) {
+	data, err := keystore.Encrypt(testKey(t), "secret", keystore.LightScryptParams)
+	assert.NoError(t, err)
+
+	for field, value := range map[string]interface{}{"accountId": "8", "environment": "mainnet"} {
+		var stored map[string]interface{}
+		assert.NoError(t, json.Unmarshal(data, &stored))
+		stored[field] = value
+		tampered, _ := json.Marshal(stored)
+		_, err = keystore.Decrypt(tampered, "secret")
+		assert.ErrorIs(t, err, keystore.ErrDecrypt, field)
+	}
+}
+
+func TestScryptParamsBounds(t *testing.T) {
+	data, err := keystore.Encrypt(testKey(t), "secret", keystore.LightScryptParams)
+	assert.NoError(t, err)
+
+	for name, params := range map[string]map[string]interface{}{
+		"huge n":        {"n": 1 << 30},
+		"n not power 2": {"n": 3000},
+		"huge r":        {"r": 1 << 20},
+		"huge p":        {"p": 1 << 20},
+		"zero p":        {"p": 0},
+	} {
+		var stored map[string]interface{}
+		assert.NoError(t, json.Unmarshal(data, &stored))
+		kdfParams := stored["crypto"].(map[string]interface{})["kdfparams"].(map[string]interface{})
+		for k, v := range params {
+			kdfParams[k] = v
+		}
+		crafted, _ := json.Marshal(stored)
+		_, err = keystore.Decrypt(crafted, "secret")
+		assert.ErrorContains(t, err, "scrypt", name)
+	}
+
+	_, err = keystore.Encrypt(testKey(t), "secret", keystore.ScryptParams{N: 1 << 22, R: 8, P: 1})
+	assert.Error(t, err)
+	_, err = keystore.Encrypt(testKey(t), "secret", keystore.StandardScryptParams)
+	assert.NoError(t, err)
+}
+
+func TestSaveLoad(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "key.json")
+	data, err := keystore.Encrypt(testKey(t), "secret", keystore.LightScryptParams)
+	assert.NoError(t, err)
+	assert.NoError(t, keystore.WriteFile(path, data))
+
+	info, err := os.Stat(path)
+	assert.NoError(t, err)
+	assert.Equal(t, os.FileMode(0o600), info.Mode().Perm())
+
+	key, err := keystore.Load(path, "secret")
+	assert.NoError(t, err)
+	assert.Equal(t, testStarkPrivateKey, key.KeyPair.PrivateKeyHex())
+
+	_, err = keystore.Load(filepath.Join(t.TempDir(), "missing.json"), "secret")
+	assert.Error(t, err)
+
+	client, err := sdk.NewClientFromKeystore(&sdk.ClientConfig{BaseURL: "http://localhost"}, path, "secret")
+	assert.NoError(t, err)
+	assert.Equal(t, int64(7), client.GetAccountID())
+	assert.Equal(t, testStarkPrivateKey, client.GetStarkPriKey())
+
+	_, err = sdk.NewClientFromKeystore(&sdk.ClientConfig{AccountID: 8}, path, "secret")
+	assert.ErrorContains(t, err, "account 7")
+	_, err = sdk.NewClientFromKeystore(&sdk.ClientConfig{StarkPriKey: testStarkPrivateKey}, path, "secret")
+	assert.Error(t, err)
+	_, err = sdk.NewClientFromKeystore(&sdk.ClientConfig{}, path, "wrong")
+	assert.ErrorIs(t, err, keystore.ErrDecrypt)
+}
@@ -0,0 +1,148 @@
+package onboard
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	"github.com/coin-quant/go-edgex/sdk"
+	"github.com/coin-quant/go-edgex/sdk/onboard"
+	"github.com/coin-quant/go-edgex/starkkey"
+	"github.com/stretchr/testify/assert"
+)
+
+const testEthAddress = "0x742d35Cc6634C0532925a3b844Bc454e4438f44e"
+
+// testSigner signs deterministically with the hash of the message
+type testSigner struct {
+	mu     sync.Mutex
+	signed []string
+}
+
+func (s *testSigner) Address() string {
+	return testEthAddress
+}
+
+func (s *testSigner) SignMessage(ctx context.Context, message []byte) (string, error) {
+	s.mu.Lock()
+	s.signed = append(s.signed, string(message))
+	s.mu.Unlock()
+	r := sha256.Sum256(message)
+	sig := sha256.Sum256(r[:])
+	return "0x" + hex.EncodeToString(r[:]) + hex.EncodeToString(sig[:]) + "1b", nil
+}
+
+// testServer emulates the user and account endpoints
+type testServer struct {
+	mu         sync.Mutex
+	userExist  bool
+	accounts   map[string]string
+	onboarded  map[string]interface{}
+	registered map[string]interface{}
+}
+
+func (s *testServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	switch r.URL.Path {
+	case "/api/v1/public/user/checkUserExist":
+		fmt.Fprintf(w, `{"code":"SUCCESS","data":{"isUserExist":%t}}`, s.userExist)
+	case "/api/v1/public/user/onboardSite":
+		_ = json.NewDecoder(r.Body).Decode(&s.onboarded)
+		s.userExist = true
+		s.accounts[s.onboarded["l2Key"].(string)] = "100"
+		fmt.Fprint(w, `{"code":"SUCCESS","data":{"isNewUser":true,"user":{"id":"1"}}}`)
+	case "/api/v1/private/account/getAccountPage":
+		var list []map[string]string
+		for l2Key, id := range s.accounts {
+			list = append(list, map[string]string{"id": id, "l2Key": l2Key})
+		}
+		data, _ := json.Marshal(list)
+		fmt.Fprintf(w, `{"code":"SUCCESS","data":{"dataList":%s}}`, data)
+	case "/api/v1/private/account/registerAccount":
+		_ = json.NewDecoder(r.Body).Decode(&s.registered)
+		s.accounts[s.registered["l2Key"].(string)] = "101"
+		fmt.Fprint(w, `{"code":"SUCCESS","data":{"accountId":"101"}}`)
+	case "/api/createApiCredential":
+		if r.URL.Query().Get("signature") == "" {
+			fmt.Fprint(w, `{"code":"INVALID_SIGNATURE"}`)
+			return
+		}
+		fmt.Fprint(w, `{"code":"SUCCESS","data":{"apiKey":"key","secret":"secret","passphrase":"pass"}}`)
+	default:
+		http.NotFound(w, r)
+	}
+}
+
+func TestOnboard(t *testing.T) {
+	state := &testServer{accounts: map[string]string{}}
+	server := httptest.NewServer(state)
+	defer server.Close()
+	ctx := context.Background()
+
+	signer := &testSigner{}
+	onboarder, err := onboard.New(onboard.Config{BaseURL: server.URL, Signer: signer})
+	assert.NoError(t, err)
+
+	// A new user is registered with the derived key as its default account
+	result, err := onboarder.Onboard(ctx, onboard.Params{})
+	assert.NoError(t, err)
+	assert.True(t, result.IsNewUser)
+	assert.Equal(t, int64(100), result.AccountID)
+	assert.Equal(t, int64(100), result.Client.GetAccountID())
+	assert.Equal(t, result.StarkKey.PrivateKeyHex(), result.Client.GetStarkPriKey())
+	assert.Equal(t, "key", result.Credential.ApiKey)
+	assert.Equal(t, testEthAddress, state.onboarded["ethAddress"])
+	assert.Equal(t, result.StarkKey.PublicKeyHex(), state.onboarded["l2Key"])
+	assert.Equal(t, result.StarkKey.PublicKeyYHex(), state.onboarded["l2KeyYCoordinate"])
+	assert.Equal(t, onboard.DefaultClientAccountID, state.onboarded["clientAccountId"])
+	assert.Equal(t, onboard.OnboardMessage(onboard.DefaultOnlySignOn), state.onboarded["param"])
+	assert.Contains(t, signer.signed, onboard.L2KeyMessage(onboard.DefaultOnlySignOn, "main"))
+
+	derived, err := onboarder.DeriveStarkKey(ctx, "main")
+	assert.NoError(t, err)
+	assert.Equal(t, result.StarkKey.PublicKeyHex(), derived.PublicKeyHex())
+
+	// Onboarding again reuses the account
+	again, err := onboarder.Onboard(ctx, onboard.Params{})
+	assert.NoError(t, err)
+	assert.False(t, again.IsNewUser)
+	assert.Equal(t, int64(100), again.AccountID)
+
+	// Further accounts of an existing user need a registrar
+	_, err = onboarder.Onboard(ctx, onboard.Params{ClientAccountID: "strategy-1"})
+	assert.ErrorContains(t, err, "registrar is required")
+
+	strategy, err := onboarder.Onboard(ctx, onboard.Params{ClientAccountID: "strategy-1", Registrar: result.Client.Account})
+	assert.NoError(t, err)
+	assert.Equal(t, int64(101), strategy.AccountID)
+	assert.NotEqual(t, result.StarkKey.PublicKeyHex(), strategy.StarkKey.PublicKeyHex())
+	assert.Equal(t, strategy.StarkKey.PublicKeyHex(), state.registered["l2Key"])
+	assert.Equal(t, "strategy-1", state.registered["clientAccountId"])
+}
+
+func TestOnboardWithStarkKey(t *testing.T) {
+	state := &testServer{accounts: map[string]string{}}
+	server := httptest.NewServer(state)
+	defer server.Close()
+
+	keyPair, err := starkkey.Generate(nil)
+	assert.NoError(t, err)
+	onboarder, err := onboard.New(onboard.Config{BaseURL: server.URL, Signer: &testSigner{}, OnlySignOn: "https://testnet.edgex.exchange"})
+	assert.NoError(t, err)
+
+	result, err := onboarder.Onboard(context.Background(), onboard.Params{StarkKey: keyPair})
+	assert.NoError(t, err)
+	assert.Equal(t, keyPair.PublicKeyHex(), state.onboarded["l2Key"])
+	assert.Equal(t, "https://testnet.edgex.exchange", state.onboarded["onlySignOn"])
+	assert.IsType(t, &sdk.Client{}, result.Client)
+
+	_, err = onboard.New(onboard.Config{BaseURL: server.URL})
+	assert.Error(t, err)
+}
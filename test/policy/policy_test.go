@@ -0,0 +1,160 @@
+package policy
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/coin-quant/go-edgex/sdk"
+	"github.com/coin-quant/go-edgex/sdk/asset"
+	"github.com/coin-quant/go-edgex/sdk/metadata"
+	"github.com/coin-quant/go-edgex/sdk/policy"
+	"github.com/coin-quant/go-edgex/sdk/transfer"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+)
+
+const testStarkPrivateKey = "04a266bc1e005725a278034bc4ab0f3075a7110a47d390b0b1b7841cabac0c4d"
+
+const testEthAddress = "0x4Fa0A3a8E3F4F5b1e0a6D4C8f3b4d1E2C3a4B5c6"
+
+func TestCheckOutbound(t *testing.T) {
+	var audit bytes.Buffer
+	approvals := 0
+	p, err := policy.New(policy.Config{
+		AllowedAddresses: []string{testEthAddress},
+		AllowedReceivers: []string{"2"},
+		DailyLimits:      map[string]decimal.Decimal{"1000": decimal.NewFromInt(100)},
+		Approver: func(ctx context.Context, req policy.Request) error {
+			approvals++
+			if req.ClientID == "rejected" {
+				return errors.New("second operator declined")
+			}
+			return nil
+		},
+		Audit: policy.NewJSONAuditLog(&audit),
+	})
+	assert.NoError(t, err)
+	now := time.Date(2026, 10, 19, 23, 0, 0, 0, time.UTC)
+	p.SetClock(func() time.Time { return now })
+	ctx := context.Background()
+
+	withdraw := policy.Request{Kind: policy.KindNormalWithdraw, CoinID: "1000", Amount: decimal.NewFromInt(60), EthAddress: strings.ToLower(testEthAddress)}
+	assert.NoError(t, p.CheckOutbound(ctx, withdraw))
+
+	var denied *policy.DeniedError
+	err = p.CheckOutbound(ctx, withdraw)
+	assert.ErrorIs(t, err, policy.ErrDenied)
+	if assert.ErrorAs(t, err, &denied) {
+		assert.Equal(t, policy.RuleDailyLimit, denied.Rule)
+	}
+
+	err = p.CheckOutbound(ctx, policy.Request{Kind: policy.KindFastWithdraw, CoinID: "1000", Amount: decimal.NewFromInt(1), EthAddress: "0xdead"})
+	if assert.ErrorAs(t, err, &denied) {
+		assert.Equal(t, policy.RuleAddress, denied.Rule)
+	}
+	err = p.CheckOutbound(ctx, policy.Request{Kind: policy.KindTransferOut, CoinID: "1000", Amount: decimal.NewFromInt(1), ReceiverAccountID: "3"})
+	if assert.ErrorAs(t, err, &denied) {
+		assert.Equal(t, policy.RuleReceiver, denied.Rule)
+	}
+
+	// A declined request does not count toward the daily limit
+	err = p.CheckOutbound(ctx, policy.Request{Kind: policy.KindTransferOut, CoinID: "1000", Amount: decimal.NewFromInt(30), ReceiverAccountID: "2", ClientID: "rejected"})
+	if assert.ErrorAs(t, err, &denied) {
+		assert.Equal(t, policy.RuleApproval, denied.Rule)
+		assert.Equal(t, "second operator declined", denied.Reason)
+	}
+	assert.Equal(t, "60", p.Used("1000").String())
+	assert.Equal(t, 2, approvals)
+
+	// The limit resets at the start of the UTC day
+	now = now.Add(2 * time.Hour)
+	assert.NoError(t, p.CheckOutbound(ctx, withdraw))
+	assert.Equal(t, "60", p.Used("1000").String())
+
+	lines := strings.Split(strings.TrimSpace(audit.String()), "\n")
+	if assert.Len(t, lines, 6) {
+		var entry policy.AuditEntry
+		assert.NoError(t, json.Unmarshal([]byte(lines[1]), &entry))
+		assert.False(t, entry.Approved)
+		assert.Equal(t, policy.RuleDailyLimit, entry.Rule)
+		assert.Equal(t, policy.KindNormalWithdraw, entry.Request.Kind)
+	}
+
+	_, err = policy.New(policy.Config{})
+	assert.Error(t, err)
+}
+
+// failingAuditLog fails every write
+type failingAuditLog struct{}
+
+func (failingAuditLog) Write(policy.AuditEntry) error {
+	return errors.New("disk full")
+}
+
+func TestCheckOutboundAuditFailure(t *testing.T) {
+	p, err := policy.New(policy.Config{
+		AllowedAddresses: []string{testEthAddress},
+		DailyLimits:      map[string]decimal.Decimal{"1000": decimal.NewFromInt(100)},
+		Audit:            failingAuditLog{},
+	})
+	assert.NoError(t, err)
+
+	err = p.CheckOutbound(context.Background(), policy.Request{Kind: policy.KindNormalWithdraw, CoinID: "1000", Amount: decimal.NewFromInt(10), EthAddress: testEthAddress})
+	assert.ErrorContains(t, err, "disk full")
+	assert.True(t, p.Used("1000").IsZero())
+}
+
+func TestClientOutboundPolicy(t *testing.T) {
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		fmt.Fprint(w, `{"code":"SUCCESS","data":{}}`)
+	}))
+	defer server.Close()
+
+	client, err := sdk.NewClient(&sdk.ClientConfig{BaseURL: server.URL, AccountID: 7, StarkPriKey: testStarkPrivateKey})
+	assert.NoError(t, err)
+
+	var audit bytes.Buffer
+	p, err := policy.New(policy.Config{Audit: policy.NewJSONAuditLog(&audit)})
+	assert.NoError(t, err)
+	assert.NoError(t, client.SetOutboundPolicy(p))
+	assert.Error(t, client.SetOutboundPolicy(p))
+
+	collateral := "0x2ce625e94458d39dd0bf3b45a843544dd4a14b8169045a3a3d15aa564b936c5"
+	_, err = client.Asset.CreateNormalWithdraw(context.Background(), &asset.CreateNormalWithdrawParams{
+		CoinId:     "1000",
+		Amount:     "10",
+		EthAddress: testEthAddress,
+	}, testMetaData(collateral))
+	assert.ErrorIs(t, err, policy.ErrDenied)
+
+	_, err = client.Transfer.CreateTransferOut(context.Background(), &transfer.CreateTransferOutParams{
+		CoinId:            "1000",
+		Amount:            "10",
+		ReceiverAccountId: "2",
+		ReceiverL2Key:     "0x0123",
+		TransferReason:    transfer.USER_TRANSFER.String(),
+		ExpireTime:        time.Now(),
+	}, testMetaData(collateral))
+	assert.ErrorIs(t, err, policy.ErrDenied)
+
+	assert.Equal(t, 0, requests)
+	assert.Equal(t, 2, strings.Count(audit.String(), "\n"))
+}
+
+func testMetaData(assetID string) *metadata.MetaData {
+	coin := metadata.Coin{CoinId: "1000", CoinName: "USDT", StarkExAssetId: assetID, StarkExResolution: "0xf4240"}
+	return &metadata.MetaData{
+		Global:   &metadata.Global{StarkExCollateralCoin: &coin},
+		CoinList: []metadata.Coin{coin},
+	}
+}
@@ -0,0 +1,164 @@
+package portfolio
+
+import (
+	"context"
+	"encoding/hex"
+	"testing"
+	"time"
+
+	"github.com/coin-quant/go-edgex/sdk/account"
+	"github.com/coin-quant/go-edgex/sdk/portfolio"
+	"github.com/coin-quant/go-edgex/sdk/ws"
+	"github.com/coin-quant/go-edgex/sdk/ws/wstest"
+	"github.com/coin-quant/go-edgex/starkcurve"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+)
+
+func assertDecimal(t *testing.T, expected string, actual decimal.Decimal) {
+	t.Helper()
+	assert.True(t, decimal.RequireFromString(expected).Equal(actual), "expected %s, got %s", expected, actual)
+}
+
+func TestPortfolioTracksPricesAndFills(t *testing.T) {
+	p := portfolio.New(portfolio.Config{CoinID: "1000"})
+
+	var events []portfolio.Event
+	p.OnChange(func(event portfolio.Event) { events = append(events, event) })
+
+	p.Load(account.AccountAssetData{
+		CollateralList: []account.Collateral{{CoinID: "1000", Amount: "-50000"}},
+		PositionList: []account.Position{{
+			CoinID:         "1000",
+			ContractID:     "10000001",
+			OpenSize:       "1",
+			OpenValue:      "60000",
+			LongTotalStat:  account.PositionStat{CumOpenFee: "-30", CumFundingFee: "-2.5"},
+			ShortTotalStat: account.PositionStat{CumCloseFee: "-10"},
+		}},
+		PositionAssetList: []account.PositionAsset{{ContractID: "10000001", TotalRealizePnl: "150"}},
+		OraclePriceList:   []account.OraclePrice{{ContractID: "10000001", PriceValue: "60000"}},
+	})
+
+	s := p.Snapshot()
+	assertDecimal(t, "10000", s.Equity)
+	assertDecimal(t, "0", s.UnrealizedPnl)
+	assertDecimal(t, "150", s.RealizedPnl)
+	assertDecimal(t, "-40", s.Fees)
+	assertDecimal(t, "-2.5", s.Funding)
+
+	p.HandleTicker([]byte(`{"type":"quote-event","channel":"ticker.10000001","content":{"channel":"ticker.10000001","dataType":"Snapshot","data":[{"contractId":"10000001","oraclePrice":"61000","lastPrice":"61010"}]}}`))
+	s = p.Snapshot()
+	assertDecimal(t, "11000", s.Equity)
+	assertDecimal(t, "1000", s.UnrealizedPnl)
+	assertDecimal(t, "61000", s.GrossExposure)
+
+	// Sell half at 61000: collateral receives the proceeds minus the fee
+	fill := []byte(`{"type":"trade-event","content":{"event":"ACCOUNT_UPDATE","data":{
+		"collateral":[{"coinId":"1000","amount":"-19515"}],
+		"positionTransaction":[{"id":"9","coinId":"1000","contractId":"10000001","realizePnl":"500"}],
+		"position":[{"coinId":"1000","contractId":"10000001","openSize":"0.5","openValue":"30000",
+			"longTotalStat":{"cumOpenFee":"-30","cumCloseFee":"-15","cumFundingFee":"-2.5"},"shortTotalStat":{"cumCloseFee":"-10"}}]
+	}}}`)
+	p.HandlePrivateMessage(fill)
+	// A duplicate delivery must not double count realized PnL
+	p.HandlePrivateMessage(fill)
+
+	s = p.Snapshot()
+	assertDecimal(t, "650", s.RealizedPnl)
+	assertDecimal(t, "500", s.UnrealizedPnl)
+	assertDecimal(t, "-55", s.Fees)
+	assertDecimal(t, "10985", s.Equity)
+	assertDecimal(t, "30500", s.NetExposure)
+
+	c := s.Contracts["10000001"]
+	assertDecimal(t, "0.5", c.Size)
+	assertDecimal(t, "61000", c.Price)
+
+	var types []portfolio.EventType
+	for _, event := range events {
+		types = append(types, event.Type)
+	}
+	assert.Equal(t, []portfolio.EventType{
+		portfolio.EventLoaded,
+		portfolio.EventPrice,
+		portfolio.EventCollateral, portfolio.EventFill, portfolio.EventPosition,
+		portfolio.EventCollateral, portfolio.EventPosition,
+	}, types)
+	assertDecimal(t, "10985", events[len(events)-1].Snapshot.Equity)
+}
+
+func TestPortfolioReportsMissingPrices(t *testing.T) {
+	p := portfolio.New(portfolio.Config{CoinID: "1000", PriceSource: portfolio.PriceLast})
+	p.HandlePrivateMessage([]byte(`{"type":"trade-event","content":{"event":"Snapshot","data":{
+		"collateral":[{"coinId":"1000","amount":"70000"}],
+		"positionTransaction":[{"id":"1","contractId":"10000002","realizePnl":"99"}],
+		"position":[{"coinId":"1000","contractId":"10000002","openSize":"-10","openValue":"-30000"}]
+	}}}`))
+
+	s := p.Snapshot()
+	assert.Equal(t, []string{"10000002"}, s.MissingPrices)
+	assertDecimal(t, "70000", s.Equity)
+	assertDecimal(t, "0", s.RealizedPnl)
+
+	p.HandleTicker([]byte(`{"content":{"data":[{"contractId":"10000002","oraclePrice":"3100","lastPrice":"2900"}]}}`))
+	s = p.Snapshot()
+	assert.Empty(t, s.MissingPrices)
+	assertDecimal(t, "41000", s.Equity)
+	assertDecimal(t, "1000", s.UnrealizedPnl)
+}
+
+func TestPortfolioAttachKeepsOtherHandlers(t *testing.T) {
+	const starkPrivateKey = "04a266bc1e005725a278034bc4ab0f3075a7110a47d390b0b1b7841cabac0c4d"
+	priv, err := hex.DecodeString(starkPrivateKey)
+	assert.NoError(t, err)
+	x, _ := starkcurve.NewStarkCurve().ScalarBaseMult(priv)
+
+	server := wstest.NewServer(wstest.Config{AccountID: 542, StarkPublicKey: "0x" + hex.EncodeToString(x.Bytes())})
+	defer server.Close()
+
+	manager := ws.NewManager(server.URL, 542, starkPrivateKey)
+	defer manager.Close()
+	assert.NoError(t, manager.ConnectPublic(context.Background()))
+	assert.NoError(t, manager.ConnectPrivate(context.Background()))
+
+	events := make(chan []byte, 1)
+	tickers := make(chan []byte, 1)
+	assert.NoError(t, manager.OnPrivateMessage(portfolio.DefaultPrivateMessageType, func(message []byte) { events <- message }))
+	_, err = manager.SubscribeMarketTicker("10000001", func(message []byte) { tickers <- message })
+	assert.NoError(t, err)
+
+	p := portfolio.New(portfolio.Config{CoinID: "1000"})
+	priced := make(chan struct{}, 1)
+	p.OnChange(func(event portfolio.Event) {
+		if event.Type == portfolio.EventPrice {
+			priced <- struct{}{}
+		}
+	})
+	detach, err := p.Attach(manager, []string{"10000001"})
+	assert.NoError(t, err)
+	defer detach()
+	assert.NoError(t, server.WaitForSubscription(ws.TickerChannel("10000001"), time.Second))
+
+	assert.NoError(t, server.SendPrivate(portfolio.DefaultPrivateMessageType, map[string]interface{}{
+		"event": "Snapshot",
+		"data":  map[string]interface{}{"collateral": []map[string]string{{"coinId": "1000", "amount": "500"}}},
+	}))
+	assert.NoError(t, server.SendTicker("10000001", map[string]string{"contractId": "10000001", "oraclePrice": "61000"}))
+
+	for _, ch := range []chan []byte{events, tickers} {
+		select {
+		case <-ch:
+		case <-time.After(time.Second):
+			t.Fatal("timeout waiting for the handler registered before Attach")
+		}
+	}
+	select {
+	case <-priced:
+	case <-time.After(time.Second):
+		t.Fatal("timeout waiting for the portfolio ticker")
+	}
+	assert.Eventually(t, func() bool {
+		return p.Snapshot().Equity.Equal(decimal.NewFromInt(500))
+	}, time.Second, 10*time.Millisecond)
+}
@@ -0,0 +1,216 @@
+package reports
+
+import (
+	"bytes"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/coin-quant/go-edgex/sdk"
+	"github.com/coin-quant/go-edgex/sdk/account"
+	"github.com/coin-quant/go-edgex/sdk/order"
+	"github.com/coin-quant/go-edgex/sdk/reports"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+)
+
+const historyJSON = `{
+	"fills": [
+		{"id": "f2", "orderId": "o2", "coinId": "1000", "contractId": "10000001", "side": "SELL", "fillPrice": "61000", "fillSize": "1", "fillValue": "61000", "fillFee": "-30.5", "createdTime": "1700000200000"},
+		{"id": "f1", "orderId": "o1", "coinId": "1000", "contractId": "10000001", "side": "BUY", "fillPrice": "60000", "fillSize": "1", "fillValue": "60000", "fillFee": "-30", "createdTime": "1700000000000"}
+	],
+	"positionTransactions": [
+		{"id": "p1", "contractId": "10000001", "orderFillTransactionId": "f1", "realizePnl": "-30", "createdTime": "1700000000000"},
+		{"id": "p2", "contractId": "10000001", "orderFillTransactionId": "f2", "realizePnl": "969.5", "createdTime": "1700000200000"}
+	],
+	"collateralTransactions": [
+		{"id": "c1", "coinId": "1000", "type": "DEPOSIT", "depositId": "d1", "beforeAmount": "0", "deltaAmount": "10000", "createdTime": "1699999000000"},
+		{"id": "c2", "coinId": "1000", "type": "POSITION_BUY", "orderFillTransactionId": "f1", "positionContractId": "10000001", "beforeAmount": "10000", "deltaAmount": "-60030", "createdTime": "1700000000000"},
+		{"id": "c3", "coinId": "1000", "type": "SETTLE_FUNDING_FEE", "positionContractId": "10000001", "beforeAmount": "-50030", "deltaAmount": "-6", "createdTime": "1700000100000"},
+		{"id": "c4", "coinId": "1000", "type": "POSITION_SELL", "orderFillTransactionId": "f2", "positionContractId": "10000001", "beforeAmount": "-50036", "deltaAmount": "60969.5", "createdTime": "1700000200000"},
+		{"id": "c5", "coinId": "1000", "type": "TRANSFER_OUT", "transferOutId": "t1", "beforeAmount": "10900", "deltaAmount": "-500", "createdTime": "1700000300000"}
+	],
+	"positionTerms": [
+		{"coinId": "1000", "contractId": "10000001", "termCount": 1, "cumOpenSize": "1", "cumOpenValue": "60000", "cumOpenFee": "-30", "cumCloseSize": "1", "cumCloseValue": "61000", "cumCloseFee": "-30.5", "cumFundingFee": "-6", "createdTime": "1700000000000", "updatedTime": "1700000200000"}
+	]
+}`
+
+func testHistory(t *testing.T) reports.History {
+	var raw struct {
+		Fills                  []order.OrderFillTransaction    `json:"fills"`
+		PositionTransactions   []account.PositionTransaction   `json:"positionTransactions"`
+		CollateralTransactions []account.CollateralTransaction `json:"collateralTransactions"`
+		PositionTerms          []account.PositionTerm          `json:"positionTerms"`
+	}
+	assert.NoError(t, json.Unmarshal([]byte(historyJSON), &raw))
+	return reports.History{
+		Fills:                  raw.Fills,
+		PositionTransactions:   raw.PositionTransactions,
+		CollateralTransactions: raw.CollateralTransactions,
+		PositionTerms:          raw.PositionTerms,
+	}
+}
+
+func assertDecimal(t *testing.T, expected string, actual decimal.Decimal) {
+	t.Helper()
+	assert.True(t, decimal.RequireFromString(expected).Equal(actual), "expected %s, got %s", expected, actual)
+}
+
+func TestBuildReport(t *testing.T) {
+	report, err := reports.Build(testHistory(t))
+	assert.NoError(t, err)
+
+	if assert.Len(t, report.Trades, 2) {
+		assert.Equal(t, "f1", report.Trades[0].FillID)
+		assertDecimal(t, "969.5", report.Trades[1].RealizedPnl)
+		assertDecimal(t, "-30.5", report.Trades[1].Fee)
+	}
+
+	if assert.Len(t, report.Terms, 1) {
+		term := report.Terms[0]
+		assert.True(t, term.Closed)
+		assertDecimal(t, "939.5", term.RealizedPnl)
+		assertDecimal(t, "-60.5", term.Fees)
+		assertDecimal(t, "-6", term.Funding)
+	}
+
+	if assert.Len(t, report.Cash, 5) {
+		assert.Equal(t, reports.CategoryDeposit, report.Cash[0].Category)
+		assert.Equal(t, "d1", report.Cash[0].Reference)
+		assert.Equal(t, reports.CategoryTrade, report.Cash[1].Category)
+		assert.Equal(t, reports.CategoryFunding, report.Cash[2].Category)
+		assert.Equal(t, reports.CategoryTransferOut, report.Cash[4].Category)
+	}
+
+	r := report.Reconciliation
+	assert.Equal(t, []reports.Break{{TransactionID: "c5", Expected: decimal.RequireFromString("10933.5"), Actual: decimal.RequireFromString("10900")}}, r.Breaks)
+	assert.False(t, r.Reconciled())
+	assertDecimal(t, "0", r.OpeningBalance)
+	assertDecimal(t, "10400", r.ClosingBalance)
+	assertDecimal(t, "10433.5", r.NetChange)
+	assertDecimal(t, "939.5", r.ByCategory[reports.CategoryTrade])
+	assertDecimal(t, "-6", r.ByCategory[reports.CategoryFunding])
+}
+
+func TestBuildReportMalformedAmount(t *testing.T) {
+	history := testHistory(t)
+	fee := "-30,5"
+	history.Fills[0].FillFee = &fee
+	_, err := reports.Build(history)
+	assert.ErrorContains(t, err, `invalid fillFee "-30,5"`)
+
+	history = testHistory(t)
+	delta := "six"
+	history.CollateralTransactions[2].DeltaAmount = &delta
+	_, err = reports.Build(history)
+	assert.ErrorContains(t, err, `invalid deltaAmount "six"`)
+}
+
+func TestBuildReportSameMillisecond(t *testing.T) {
+	str := func(s string) *string { return &s }
+	history := reports.History{
+		// Newest first, as the API lists them
+		Fills: []order.OrderFillTransaction{
+			{Id: str("10"), FillPrice: str("60000"), FillSize: str("1"), CreatedTime: str("1700000000000")},
+			{Id: str("9"), FillPrice: str("60000"), FillSize: str("1"), CreatedTime: str("1700000000000")},
+		},
+		CollateralTransactions: []account.CollateralTransaction{
+			{Id: str("c2"), CoinId: str("1000"), BeforeAmount: str("90"), DeltaAmount: str("5"), CreatedTime: str("1700000000000")},
+			{Id: str("c3"), CoinId: str("1000"), BeforeAmount: str("100"), DeltaAmount: str("-10"), CreatedTime: str("1700000000000")},
+			{Id: str("c1"), CoinId: str("1000"), DepositId: str("d1"), BeforeAmount: str("0"), DeltaAmount: str("100"), CreatedTime: str("1699999000000")},
+		},
+	}
+	report, err := reports.Build(history)
+	assert.NoError(t, err)
+
+	if assert.Len(t, report.Trades, 2) {
+		assert.Equal(t, "9", report.Trades[0].FillID)
+		assert.Equal(t, "10", report.Trades[1].FillID)
+	}
+	if assert.Len(t, report.Cash, 3) {
+		assert.Equal(t, "c1", report.Cash[0].TransactionID)
+		assert.Equal(t, "c3", report.Cash[1].TransactionID)
+		assert.Equal(t, "c2", report.Cash[2].TransactionID)
+	}
+	assert.True(t, report.Reconciliation.Reconciled())
+	assertDecimal(t, "95", report.Reconciliation.ClosingBalance)
+}
+
+func TestExportReport(t *testing.T) {
+	report, err := reports.Build(testHistory(t))
+	assert.NoError(t, err)
+
+	var buf bytes.Buffer
+	assert.NoError(t, report.WriteCSV(&buf, reports.LedgerTrades))
+	rows, err := csv.NewReader(&buf).ReadAll()
+	assert.NoError(t, err)
+	if assert.Len(t, rows, 3) {
+		assert.Equal(t, "time", rows[0][0])
+		assert.Equal(t, []string{"2023-11-14T22:13:20Z", "f1", "o1", "1000", "10000001", "BUY", "60000", "1", "60000", "-30", "-30", "", ""}, rows[1])
+	}
+
+	buf.Reset()
+	assert.NoError(t, report.WriteCSV(&buf, reports.LedgerCash))
+	rows, err = csv.NewReader(&buf).ReadAll()
+	assert.NoError(t, err)
+	assert.Len(t, rows, 6)
+
+	assert.Error(t, report.WriteCSV(&buf, reports.Ledger("unknown")))
+
+	buf.Reset()
+	assert.NoError(t, report.WriteJSON(&buf))
+	var decoded reports.Report
+	assert.NoError(t, json.Unmarshal(buf.Bytes(), &decoded))
+	assert.Len(t, decoded.Cash, 5)
+	assertDecimal(t, "10400", decoded.Reconciliation.ClosingBalance)
+}
+
+func TestFetchReportWindow(t *testing.T) {
+	queries := make(map[string]url.Values)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		queries[r.URL.Path] = r.URL.Query()
+		switch r.URL.Path {
+		case "/api/v1/private/account/getPositionTermPage":
+			fmt.Fprint(w, `{"code":"SUCCESS","data":{"dataList":[
+				{"coinId":"1000","contractId":"10000001","cumOpenSize":"1","cumCloseSize":"1","createdTime":"1700000000000","updatedTime":"1700000200000"},
+				{"coinId":"1000","contractId":"10000002","cumOpenSize":"1","createdTime":"1690000000000","updatedTime":"1690000100000"}]}}`)
+		case "/api/v1/private/account/getPositionTransactionPage":
+			fmt.Fprint(w, `{"code":"SUCCESS","data":{"dataList":[
+				{"id":"p1","contractId":"10000001","realizePnl":"-30","createdTime":"1700000000000"},
+				{"id":"p2","contractId":"10000001","realizePnl":"969.5","createdTime":"1700000200000"}]}}`)
+		default:
+			fmt.Fprint(w, `{"code":"SUCCESS","data":{"dataList":[]}}`)
+		}
+	}))
+	defer server.Close()
+
+	client, err := sdk.NewClient(&sdk.ClientConfig{BaseURL: server.URL, AccountID: 7,
+		StarkPriKey: "04a266bc1e005725a278034bc4ab0f3075a7110a47d390b0b1b7841cabac0c4d"})
+	assert.NoError(t, err)
+	to := time.UnixMilli(1700000300000)
+
+	// A zero From leaves out the start filters
+	_, err = client.GetReport(context.Background(), reports.Params{CoinID: "1000", To: to})
+	assert.NoError(t, err)
+	for path, query := range queries {
+		assert.False(t, query.Has("filterStartCreatedTimeInclusive"), path)
+		assert.Equal(t, "1700000300000", query.Get("filterEndCreatedTimeExclusive"), path)
+	}
+
+	// A term opened before From keeps its opening leg, terms closed before are left out
+	report, err := client.GetReport(context.Background(), reports.Params{CoinID: "1000", From: time.UnixMilli(1700000100000), To: to})
+	assert.NoError(t, err)
+	if assert.Len(t, report.Terms, 1) {
+		assertDecimal(t, "939.5", report.Terms[0].RealizedPnl)
+	}
+	assert.False(t, queries["/api/v1/private/account/getPositionTermPage"].Has("filterStartCreatedTimeInclusive"))
+	assert.Equal(t, "1700000000000", queries["/api/v1/private/account/getPositionTransactionPage"].Get("filterStartCreatedTimeInclusive"))
+	assert.Equal(t, "1700000100000", queries["/api/v1/private/order/getHistoryOrderFillTransactionPage"].Get("filterStartCreatedTimeInclusive"))
+	assert.Equal(t, "1700000100000", queries["/api/v1/private/account/getCollateralTransactionPage"].Get("filterStartCreatedTimeInclusive"))
+}
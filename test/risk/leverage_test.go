@@ -0,0 +1,62 @@
+package risk
+
+import (
+	"testing"
+
+	"github.com/coin-quant/go-edgex/sdk/risk"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMaxLeverageFollowsTierAndSide(t *testing.T) {
+	meta := testMetaData()
+	meta.ContractList[0].MaxShortLeverage = "25"
+	calc, err := risk.NewCalculator(meta)
+	assert.NoError(t, err)
+
+	maxLeverage, err := calc.MaxLeverage("10000001", d("1"), d("60000"))
+	assert.NoError(t, err)
+	assertDecimal(t, "50", maxLeverage)
+
+	maxLeverage, err = calc.MaxLeverage("10000001", d("-1"), d("60000"))
+	assert.NoError(t, err)
+	assertDecimal(t, "25", maxLeverage)
+
+	maxLeverage, err = calc.MaxLeverage("10000001", d("2"), d("60000"))
+	assert.NoError(t, err)
+	assertDecimal(t, "20", maxLeverage)
+
+	maxLeverage, err = calc.MaxLeverage("10000001", decimal.Zero, decimal.Zero)
+	assert.NoError(t, err)
+	assertDecimal(t, "25", maxLeverage)
+}
+
+func TestCheckLeverage(t *testing.T) {
+	calc, err := risk.NewCalculator(testMetaData())
+	assert.NoError(t, err)
+
+	acc := risk.Account{
+		Collateral:   d("-50000"),
+		Positions:    []risk.Position{{ContractID: "10000001", Size: d("1"), OpenValue: d("60000")}},
+		OraclePrices: map[string]decimal.Decimal{"10000001": d("61000")},
+	}
+
+	_, err = calc.CheckLeverage(acc, "10000001", d("60"))
+	assert.Error(t, err)
+	_, err = calc.CheckLeverage(acc, "10000001", d("0"))
+	assert.Error(t, err)
+
+	change, err := calc.CheckLeverage(acc, "10000001", d("20"))
+	assert.NoError(t, err)
+	assertDecimal(t, "50", change.MaxLeverage)
+	assertDecimal(t, "6100", change.Before.InitialMargin)
+	assertDecimal(t, "3050", change.After.InitialMargin)
+	assertDecimal(t, "-3050", change.InitialMarginChange)
+	assert.True(t, change.Allowed)
+	assert.Empty(t, acc.Leverage)
+
+	change, err = calc.CheckLeverage(acc, "10000001", d("5"))
+	assert.NoError(t, err)
+	assertDecimal(t, "12200", change.After.InitialMargin)
+	assert.False(t, change.Allowed)
+}
@@ -0,0 +1,132 @@
+package risk
+
+import (
+	"testing"
+
+	"github.com/coin-quant/go-edgex/sdk/metadata"
+	"github.com/coin-quant/go-edgex/sdk/order"
+	"github.com/coin-quant/go-edgex/sdk/risk"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+)
+
+func d(value string) decimal.Decimal {
+	return decimal.RequireFromString(value)
+}
+
+func assertDecimal(t *testing.T, expected string, actual decimal.Decimal) {
+	t.Helper()
+	assert.True(t, d(expected).Equal(actual.Round(6)), "expected %s, got %s", expected, actual)
+}
+
+func testMetaData() *metadata.MetaData {
+	return &metadata.MetaData{
+		ContractList: []metadata.Contract{{
+			ContractId:       "10000001",
+			MaxLongLeverage:  "50",
+			MaxShortLeverage: "50",
+			DefaultLeverage:  "10",
+			RiskTierList: []metadata.RiskTier{
+				{Tier: 2, PositionValueUpperBound: "500000", MaxLeverage: "20", MaintenanceMarginRate: "0.025"},
+				{Tier: 1, PositionValueUpperBound: "100000", MaxLeverage: "50", MaintenanceMarginRate: "0.01"},
+			},
+		}},
+	}
+}
+
+func TestEvaluateLongPosition(t *testing.T) {
+	calc, err := risk.NewCalculator(testMetaData())
+	assert.NoError(t, err)
+
+	result, err := calc.Evaluate(risk.Account{
+		Collateral:   d("-50000"),
+		Positions:    []risk.Position{{ContractID: "10000001", Size: d("1"), OpenValue: d("60000")}},
+		OraclePrices: map[string]decimal.Decimal{"10000001": d("61000")},
+	})
+	assert.NoError(t, err)
+
+	assertDecimal(t, "11000", result.TotalEquity)
+	assertDecimal(t, "1000", result.UnrealizedPnl)
+	assertDecimal(t, "6100", result.InitialMargin)
+	assertDecimal(t, "610", result.MaintenanceMargin)
+	assertDecimal(t, "4900", result.AvailableBalance)
+	assertDecimal(t, "0.055455", result.MarginRatio)
+	assert.False(t, result.Liquidatable())
+
+	position, ok := result.Position("10000001")
+	assert.True(t, ok)
+	assert.Equal(t, int32(1), position.Tier.Tier)
+	assertDecimal(t, "50505.050505", position.LiquidationPrice)
+	assertDecimal(t, "50000", position.BankruptcyPrice)
+}
+
+func TestEvaluateShortPositionWithLeverageSetting(t *testing.T) {
+	calc, err := risk.NewCalculator(testMetaData())
+	assert.NoError(t, err)
+
+	result, err := calc.Evaluate(risk.Account{
+		Collateral:   d("70000"),
+		Positions:    []risk.Position{{ContractID: "10000001", Size: d("-1"), OpenValue: d("-60000")}},
+		OraclePrices: map[string]decimal.Decimal{"10000001": d("60000")},
+		Leverage:     map[string]decimal.Decimal{"10000001": d("100")},
+	})
+	assert.NoError(t, err)
+
+	position, _ := result.Position("10000001")
+	// The setting is capped by the tier's max leverage
+	assertDecimal(t, "50", position.Leverage)
+	assertDecimal(t, "1200", position.InitialMargin)
+	assertDecimal(t, "69306.930693", position.LiquidationPrice)
+	assertDecimal(t, "70000", position.BankruptcyPrice)
+}
+
+func TestWhatIfCrossesRiskTier(t *testing.T) {
+	calc, err := risk.NewCalculator(testMetaData())
+	assert.NoError(t, err)
+
+	acc := risk.Account{
+		Collateral:   d("-50000"),
+		Positions:    []risk.Position{{ContractID: "10000001", Size: d("1"), OpenValue: d("60000")}},
+		OraclePrices: map[string]decimal.Decimal{"10000001": d("61000")},
+	}
+	result, err := calc.WhatIf(acc, risk.Order{
+		ContractID: "10000001",
+		Side:       order.OrderSideBuy,
+		Size:       d("1"),
+		Price:      d("61000"),
+		FeeRate:    d("0.0005"),
+	})
+	assert.NoError(t, err)
+
+	assertDecimal(t, "30.5", result.Fee)
+	assertDecimal(t, "6100", result.InitialMarginChange)
+	assertDecimal(t, "10969.5", result.After.TotalEquity)
+	after, _ := result.After.Position("10000001")
+	assert.Equal(t, int32(2), after.Tier.Tier)
+	assertDecimal(t, "3050", after.MaintenanceMargin)
+	assertDecimal(t, "121000", after.Size.Mul(after.OraclePrice).Sub(after.UnrealizedPnl))
+	assert.False(t, result.Allowed)
+
+	closing, err := calc.WhatIf(acc, risk.Order{ContractID: "10000001", Side: order.OrderSideSell, Size: d("1"), Price: d("61000")})
+	assert.NoError(t, err)
+	assert.True(t, closing.Allowed)
+	assertDecimal(t, "11000", closing.After.AvailableBalance)
+	assert.Empty(t, closing.After.Positions)
+}
+
+func TestStarkExRiskIsExactMaintenanceRate(t *testing.T) {
+	meta := testMetaData()
+	meta.ContractList[0].RiskTierList = []metadata.RiskTier{
+		{Tier: 1, PositionValueUpperBound: "100000", MaxLeverage: "50", MaintenanceMarginRate: "0.01", StarkExRisk: "42949673"},
+	}
+	calc, err := risk.NewCalculator(meta)
+	assert.NoError(t, err)
+
+	tier, err := calc.TierFor("10000001", d("1000"))
+	assert.NoError(t, err)
+	assert.True(t, tier.MaintenanceMarginRate.Sub(d("0.01")).Abs().LessThan(d("0.0000001")))
+	assert.False(t, tier.MaintenanceMarginRate.Equal(d("0.01")))
+
+	_, err = calc.Evaluate(risk.Account{Positions: []risk.Position{{ContractID: "10000001", Size: d("1")}}})
+	assert.Error(t, err)
+}
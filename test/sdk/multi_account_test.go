@@ -0,0 +1,92 @@
+package sdk
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/coin-quant/go-edgex/sdk"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+)
+
+const testStarkPrivateKey = "04a266bc1e005725a278034bc4ab0f3075a7110a47d390b0b1b7841cabac0c4d"
+
+func TestMultiAccountClient(t *testing.T) {
+	var metadataRequests int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Path {
+		case "/api/v1/public/meta/getMetaData":
+			atomic.AddInt32(&metadataRequests, 1)
+			fmt.Fprint(w, `{"code":"SUCCESS","data":{"contractList":[{"contractId":"10000001"}]}}`)
+		case "/api/v1/private/account/getAccountAsset":
+			switch r.URL.Query().Get("accountId") {
+			case "1":
+				fmt.Fprint(w, `{"code":"SUCCESS","data":{
+					"collateralList":[{"coinId":"1000","amount":"-50000"}],
+					"collateralAssetModelList":[{"coinId":"1000","totalEquity":"10000","availableAmount":"4000"}],
+					"positionList":[{"contractId":"10000001","openSize":"1","openValue":"60000"}]}}`)
+			case "2":
+				fmt.Fprint(w, `{"code":"SUCCESS","data":{
+					"collateralList":[{"coinId":"1000","amount":"35000"}],
+					"collateralAssetModelList":[{"coinId":"1000","totalEquity":"5000","availableAmount":"2000"}],
+					"positionList":[{"contractId":"10000001","openSize":"-0.5","openValue":"-30000"}]}}`)
+			default:
+				fmt.Fprint(w, `{"code":"ACCOUNT_NOT_FOUND"}`)
+			}
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+
+	ttl := time.Minute
+	m, err := sdk.NewMultiAccountClient(&sdk.MultiAccountConfig{
+		BaseURL:          server.URL,
+		MetaDataCacheTTL: &ttl,
+		Accounts: []sdk.AccountConfig{
+			{Name: "trend", AccountID: 1, StarkPriKey: testStarkPrivateKey},
+			{Name: "carry", AccountID: 2, StarkPriKey: testStarkPrivateKey},
+		},
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"carry", "trend"}, m.Names())
+
+	_, err = m.Add(sdk.AccountConfig{Name: "trend", AccountID: 3})
+	assert.Error(t, err)
+
+	ctx := context.Background()
+	agg, err := m.GetAggregate(ctx)
+	assert.NoError(t, err)
+	position := agg.Positions["10000001"]
+	assert.True(t, decimal.RequireFromString("0.5").Equal(position.Size))
+	assert.True(t, decimal.RequireFromString("-0.5").Equal(position.ByAccount["carry"]))
+	balance := agg.Balances["1000"]
+	assert.True(t, decimal.RequireFromString("-15000").Equal(balance.Amount))
+	assert.True(t, decimal.RequireFromString("15000").Equal(balance.TotalEquity))
+	assert.True(t, decimal.RequireFromString("6000").Equal(balance.AvailableAmount))
+
+	// The metadata cache is shared by all accounts
+	for _, name := range m.Names() {
+		client, err := m.Account(name)
+		assert.NoError(t, err)
+		_, err = client.GetMetaData(ctx)
+		assert.NoError(t, err)
+	}
+	assert.Equal(t, int32(1), atomic.LoadInt32(&metadataRequests))
+
+	_, err = m.Add(sdk.AccountConfig{Name: "broken", AccountID: 3, StarkPriKey: testStarkPrivateKey})
+	assert.NoError(t, err)
+	assets, err := m.GetAccountAssets(ctx)
+	assert.ErrorContains(t, err, "account broken")
+	assert.Len(t, assets, 2)
+
+	m.Remove("broken")
+	_, err = m.Account("broken")
+	assert.Error(t, err)
+}
@@ -0,0 +1,203 @@
+package sdk
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/coin-quant/go-edgex/sdk"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+)
+
+// fakeTransferServer serves the endpoints used by TransferBetweenAccounts
+type fakeTransferServer struct {
+	mu       sync.Mutex
+	created  []map[string]interface{}
+	polls    int
+	statuses []string
+	final    string
+}
+
+func (f *fakeTransferServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	w.Header().Set("Content-Type", "application/json")
+	switch r.URL.Path {
+	case "/api/v1/public/meta/getMetaData":
+		fmt.Fprint(w, `{"code":"SUCCESS","data":{"global":{"starkExCollateralCoin":{"coinId":"1000","starkExAssetId":"0x2ce625e94458d39dd0bf3b45a843544dd4a14b8169045a3a3d15aa564b936c5","starkExResolution":"0xf4240"}}}}`)
+	case "/api/v1/private/account/getAccountById":
+		fmt.Fprintf(w, `{"code":"SUCCESS","data":{"id":"%s","l2Key":"0x0123"}}`, r.URL.Query().Get("accountId"))
+	case "/api/v1/private/transfer/getActiveTransferOut":
+		var list []string
+		for i, body := range f.created {
+			list = append(list, fmt.Sprintf(`{"id":"%d","clientTransferId":"%s","coinId":"%s","amount":"%s","receiverAccountId":"%s","status":"PENDING_CHECKING"}`,
+				i+1, body["clientTransferId"], body["coinId"], body["amount"], body["receiverAccountId"]))
+		}
+		fmt.Fprintf(w, `{"code":"SUCCESS","data":{"dataList":[%s]}}`, strings.Join(list, ","))
+	case "/api/v1/private/transfer/createTransferOut":
+		var body map[string]interface{}
+		_ = json.NewDecoder(r.Body).Decode(&body)
+		f.created = append(f.created, body)
+		fmt.Fprintf(w, `{"code":"SUCCESS","data":{"transferOutId":"%d"}}`, len(f.created))
+	case "/api/v1/private/transfer/getTransferOutById":
+		status := f.final
+		if f.polls < len(f.statuses) {
+			status = f.statuses[f.polls]
+		}
+		f.polls++
+		fmt.Fprintf(w, `{"code":"SUCCESS","data":[{"id":"%s","status":"%s","receiverTransferInId":"77","censorFailCode":"%s","censorFailReason":"%s"}]}`,
+			r.URL.Query().Get("transferOutIdList"), status, failCode(status), failReason(status))
+	case "/api/v1/private/transfer/getTransferInById":
+		fmt.Fprintf(w, `{"code":"SUCCESS","data":[{"id":"%s","accountId":"%s","status":"SUCCESS_CENSOR_SUCCESS"}]}`,
+			r.URL.Query().Get("transferInIdList"), r.URL.Query().Get("accountId"))
+	default:
+		http.NotFound(w, r)
+	}
+}
+
+func failCode(status string) string {
+	if status == "FAILED_CENSOR_FAILURE" {
+		return "INSUFFICIENT_BALANCE"
+	}
+	return ""
+}
+
+func failReason(status string) string {
+	if status == "FAILED_CENSOR_FAILURE" {
+		return "not enough collateral"
+	}
+	return ""
+}
+
+func newTransferClient(t *testing.T, server *httptest.Server) *sdk.MultiAccountClient {
+	m, err := sdk.NewMultiAccountClient(&sdk.MultiAccountConfig{
+		BaseURL: server.URL,
+		Accounts: []sdk.AccountConfig{
+			{Name: "trend", AccountID: 1, StarkPriKey: testStarkPrivateKey},
+			{Name: "carry", AccountID: 2, StarkPriKey: testStarkPrivateKey},
+		},
+	})
+	assert.NoError(t, err)
+	return m
+}
+
+func TestTransferBetweenAccounts(t *testing.T) {
+	fake := &fakeTransferServer{statuses: []string{"PENDING_CHECKING", "PENDING_CENSORING", "SUCCESS_CENSOR_SUCCESS"}, final: "SUCCESS_L2_APPROVED"}
+	server := httptest.NewServer(fake)
+	defer server.Close()
+	m := newTransferClient(t, server)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	params := sdk.InternalTransferParams{
+		Amount:           decimal.RequireFromString("250.5"),
+		ClientTransferID: "rebalance-20261019",
+		PollInterval:     time.Millisecond,
+	}
+	result, err := m.TransferBetweenAccounts(ctx, "trend", "carry", params)
+	assert.NoError(t, err)
+	assert.True(t, result.Succeeded)
+	assert.Equal(t, "1", result.TransferOutID)
+	assert.Equal(t, "SUCCESS_L2_APPROVED", result.Status)
+	assert.Equal(t, 4, fake.polls)
+	if assert.NotNil(t, result.TransferIn) {
+		assert.Equal(t, "2", *result.TransferIn.AccountId)
+	}
+
+	if assert.Len(t, fake.created, 1) {
+		body := fake.created[0]
+		assert.Equal(t, "2", body["receiverAccountId"])
+		assert.Equal(t, "0x0123", body["receiverL2Key"])
+		assert.Equal(t, "rebalance-20261019", body["clientTransferId"])
+		assert.Equal(t, "1000", body["coinId"])
+		assert.Equal(t, "250.5", body["amount"])
+	}
+
+	// Retrying with the same client transfer ID tracks the existing transfer
+	result, err = m.TransferBetweenAccounts(ctx, "trend", "carry", params)
+	assert.NoError(t, err)
+	assert.Equal(t, "1", result.TransferOutID)
+	assert.Len(t, fake.created, 1)
+
+	// Reusing the client transfer ID for another transfer is an error
+	changed := params
+	changed.Amount = decimal.NewFromInt(300)
+	_, err = m.TransferBetweenAccounts(ctx, "trend", "carry", changed)
+	assert.ErrorContains(t, err, "already used")
+	changed = params
+	changed.CoinID = "2000"
+	_, err = m.TransferBetweenAccounts(ctx, "trend", "carry", changed)
+	assert.ErrorContains(t, err, "already used")
+	_, err = m.TransferBetweenAccounts(ctx, "carry", "trend", params)
+	assert.ErrorContains(t, err, "already used")
+	assert.Len(t, fake.created, 1)
+
+	_, err = m.TransferBetweenAccounts(ctx, "trend", "trend", sdk.InternalTransferParams{Amount: decimal.NewFromInt(1), ClientTransferID: "x"})
+	assert.Error(t, err)
+	_, err = m.TransferBetweenAccounts(ctx, "trend", "carry", sdk.InternalTransferParams{Amount: decimal.NewFromInt(1)})
+	assert.Error(t, err)
+}
+
+func TestTransferBetweenAccountsFailed(t *testing.T) {
+	fake := &fakeTransferServer{final: "FAILED_CENSOR_FAILURE"}
+	server := httptest.NewServer(fake)
+	defer server.Close()
+	m := newTransferClient(t, server)
+
+	result, err := m.TransferBetweenAccounts(context.Background(), "carry", "trend", sdk.InternalTransferParams{
+		CoinID:           "1000",
+		Amount:           decimal.NewFromInt(100),
+		ClientTransferID: "rebalance-failed",
+		PollInterval:     time.Millisecond,
+	})
+	assert.ErrorContains(t, err, "not enough collateral")
+	if assert.NotNil(t, result) {
+		assert.False(t, result.Succeeded)
+		assert.Equal(t, "INSUFFICIENT_BALANCE", result.CensorFailCode)
+		assert.Equal(t, "not enough collateral", result.CensorFailReason)
+	}
+}
+
+func TestTransferBetweenAccountsSkipL2Wait(t *testing.T) {
+	fake := &fakeTransferServer{statuses: []string{"PENDING_CENSORING"}, final: "SUCCESS_CENSOR_SUCCESS"}
+	server := httptest.NewServer(fake)
+	defer server.Close()
+	m := newTransferClient(t, server)
+
+	result, err := m.TransferBetweenAccounts(context.Background(), "trend", "carry", sdk.InternalTransferParams{
+		Amount:           decimal.NewFromInt(10),
+		ClientTransferID: "rebalance-censored",
+		PollInterval:     time.Millisecond,
+		SkipL2Wait:       true,
+	})
+	assert.NoError(t, err)
+	assert.True(t, result.Succeeded)
+	assert.Equal(t, "SUCCESS_CENSOR_SUCCESS", result.Status)
+	assert.Equal(t, 2, fake.polls)
+}
+
+func TestTransferBetweenAccountsL2Reject(t *testing.T) {
+	fake := &fakeTransferServer{statuses: []string{"SUCCESS_CENSOR_SUCCESS", "FAILED_L2_REJECT"}, final: "FAILED_L2_REJECT_APPROVED"}
+	server := httptest.NewServer(fake)
+	defer server.Close()
+	m := newTransferClient(t, server)
+
+	result, err := m.TransferBetweenAccounts(context.Background(), "trend", "carry", sdk.InternalTransferParams{
+		Amount:           decimal.NewFromInt(10),
+		ClientTransferID: "rebalance-rejected",
+		PollInterval:     time.Millisecond,
+	})
+	assert.Error(t, err)
+	if assert.NotNil(t, result) {
+		assert.False(t, result.Succeeded)
+		assert.Equal(t, "FAILED_L2_REJECT_APPROVED", result.Status)
+	}
+	assert.Equal(t, 3, fake.polls)
+}
@@ -0,0 +1,95 @@
+package starkkey
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/coin-quant/go-edgex/sdk"
+	"github.com/coin-quant/go-edgex/starkcurve"
+	"github.com/coin-quant/go-edgex/starkkey"
+	"github.com/stretchr/testify/assert"
+)
+
+const testStarkPrivateKey = "04a266bc1e005725a278034bc4ab0f3075a7110a47d390b0b1b7841cabac0c4d"
+
+const testEthSignature = "0x21fbf0696d5e0aa2ef41a2b4ffb623bcaf070461d61cf7251c74161f82fec3a4" +
+	"370854bc0a34b3ab487c1bc021cd318c734c51ae29374f2beb0e6f2dd49b4bf41c"
+
+func TestGrindKey(t *testing.T) {
+	// Vector of the StarkEx key derivation
+	seed, _ := hex.DecodeString("86F3E7293141F20A8BAFF320E8EE4ACCB9D4A4BF2B4D295E8CEE784DB46E0519")
+	assert.Equal(t, "5c8c8683596c732541a59e03007b2d30dbbbb873556fe65b5fb63c16688f941", starkkey.GrindKey(seed).Text(16))
+}
+
+func TestFromEthSignature(t *testing.T) {
+	keyPair, err := starkkey.FromEthSignature(testEthSignature)
+	assert.NoError(t, err)
+
+	r, _ := hex.DecodeString(testEthSignature[2:66])
+	digest := sha256.Sum256(append(r, 0))
+	expected := new(big.Int).SetBytes(digest[:])
+	expected.Mod(expected, starkcurve.NewStarkCurve().N)
+	assert.Equal(t, expected, keyPair.PrivateKey)
+
+	again, err := starkkey.FromEthSignature(strings.TrimPrefix(testEthSignature, "0x"))
+	assert.NoError(t, err)
+	assert.Equal(t, keyPair.PublicKeyHex(), again.PublicKeyHex())
+
+	_, err = starkkey.FromEthSignature("0x1234")
+	assert.Error(t, err)
+}
+
+func TestKeyPair(t *testing.T) {
+	keyPair, err := starkkey.FromPrivateKey("0x" + testStarkPrivateKey)
+	assert.NoError(t, err)
+	assert.Equal(t, testStarkPrivateKey, keyPair.PrivateKeyHex())
+
+	priv, _ := hex.DecodeString(testStarkPrivateKey)
+	x, y := starkcurve.NewStarkCurve().ScalarBaseMult(priv)
+	assert.Equal(t, x, keyPair.PublicKey)
+	assert.Equal(t, y, keyPair.PublicKeyY)
+	assert.Len(t, keyPair.PublicKeyHex(), 66)
+
+	y1, y2, err := starkkey.YCoordinates(keyPair.PublicKeyHex())
+	assert.NoError(t, err)
+	assert.True(t, y1.Cmp(y2) < 0)
+	assert.True(t, keyPair.PublicKeyY.Cmp(y1) == 0 || keyPair.PublicKeyY.Cmp(y2) == 0)
+
+	assert.NoError(t, keyPair.Validate(keyPair.PublicKeyHex(), ""))
+	assert.NoError(t, keyPair.Validate("0x"+x.Text(16), "0x"+y.Text(16)))
+	assert.Error(t, keyPair.Validate("0x1234", ""))
+	assert.Error(t, keyPair.Validate(keyPair.PublicKeyHex(), "0x1234"))
+
+	_, err = starkkey.FromPrivateKey("zz")
+	assert.Error(t, err)
+	_, err = starkkey.FromPrivateKey("0")
+	assert.Error(t, err)
+
+	generated, err := starkkey.Generate(nil)
+	assert.NoError(t, err)
+	assert.True(t, starkcurve.NewStarkCurve().IsOnCurve(generated.PublicKey, generated.PublicKeyY))
+}
+
+func TestValidateStarkKey(t *testing.T) {
+	keyPair, err := starkkey.FromPrivateKey(testStarkPrivateKey)
+	assert.NoError(t, err)
+	l2Key := keyPair.PublicKeyHex()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprintf(w, `{"code":"SUCCESS","data":{"id":"7","l2Key":"%s","l2KeyYCoordinate":"%s"}}`, l2Key, keyPair.PublicKeyYHex())
+	}))
+	defer server.Close()
+
+	client, err := sdk.NewClient(&sdk.ClientConfig{BaseURL: server.URL, AccountID: 7, StarkPriKey: testStarkPrivateKey})
+	assert.NoError(t, err)
+	assert.NoError(t, client.ValidateStarkKey(context.Background()))
+
+	l2Key = "0x1234"
+	assert.ErrorContains(t, client.ValidateStarkKey(context.Background()), "does not match")
+}
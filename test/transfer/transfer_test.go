@@ -110,3 +110,16 @@ func TestCreateTransferOut(t *testing.T) {
 	// Data is interface{}, skip detailed assertions
 	t.Logf("Create transfer response data: %v", data)
 }
+
+func TestIsTerminalStatus(t *testing.T) {
+	assert.True(t, transfer.IsTerminalStatus(transfer.StatusSuccessCensorSuccess, false))
+	assert.False(t, transfer.IsTerminalStatus(transfer.StatusFailedL2Reject, false))
+	assert.True(t, transfer.IsTerminalStatus(transfer.StatusFailedL2RejectApproved, false))
+	assert.False(t, transfer.IsTerminalStatus(transfer.StatusPendingCensoring, false))
+
+	assert.False(t, transfer.IsTerminalStatus(transfer.StatusSuccessCensorSuccess, true))
+	assert.False(t, transfer.IsTerminalStatus(transfer.StatusFailedL2Reject, true))
+	assert.True(t, transfer.IsTerminalStatus(transfer.StatusFailedL2RejectApproved, true))
+	assert.True(t, transfer.IsTerminalStatus(transfer.StatusFailedCensorFailure, true))
+	assert.True(t, transfer.IsTerminalStatus(transfer.StatusSuccessL2Approved, true))
+}
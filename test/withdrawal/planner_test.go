@@ -0,0 +1,105 @@
+package withdrawal
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/coin-quant/go-edgex/sdk/withdrawal"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+)
+
+const testPlannerMetaData = `{"code":"SUCCESS","data":{
+	"global":{"starkExChainId":"1","fastWithdrawMaxAmount":"10000","starkExCollateralCoin":{"coinId":"1000"}},
+	"coinList":[{"coinId":"1000","coinName":"USDT","stepSize":"0.000001","starkExResolution":"0xf4240"}],
+	"multiChain":{"coinId":"1000","minWithdraw":"10","maxWithdraw":"50000","chainList":[
+		{"chain":"Ethereum","chainId":"1","allowWithdraw":true,
+			"tokenList":[{"token":"USDT","tokenAddress":"0x01","decimals":"6","withdrawEnable":true}]},
+		{"chain":"Arbitrum","chainId":"42161","allowWithdraw":false,
+			"tokenList":[{"token":"USDT","tokenAddress":"0x03","decimals":"6","withdrawEnable":true}]},
+		{"chain":"BNB Chain","chainId":"56","allowWithdraw":true,
+			"tokenList":[{"token":"USDT","tokenAddress":"0x02","decimals":"18","withdrawEnable":true}]}]}}}`
+
+func newPlannerServer() *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/api/v1/public/meta/getMetaData":
+			fmt.Fprint(w, testPlannerMetaData)
+		case "/api/v1/private/withdraw/getWithdrawAvailableAmount":
+			fmt.Fprint(w, `{"code":"SUCCESS","data":{"availableAmount":"30000"}}`)
+		case "/api/v1/private/transfer/getTransferOutAvailableAmount":
+			fmt.Fprint(w, `{"code":"SUCCESS","data":{"availableAmount":"25000"}}`)
+		case "/api/v1/private/assets/getNormalWithdrawableAmount":
+			fmt.Fprint(w, `{"code":"SUCCESS","data":{"amount":"42"}}`)
+		case "/api/v1/private/assets/getFastWithdrawSignInfo":
+			if r.URL.Query().Get("chainId") != "1" {
+				fmt.Fprint(w, `{"code":"CHAIN_NOT_SUPPORTED"}`)
+				return
+			}
+			fmt.Fprint(w, `{"code":"SUCCESS","data":{"fee":"2.5","fastWithdrawMaxAmount":"8000"}}`)
+		case "/api/v1/private/assets/getCrossWithdrawSignInfo":
+			fmt.Fprint(w, `{"code":"SUCCESS","data":{"fee":"1","crossWithdrawMaxAmount":"20000"}}`)
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+}
+
+func TestPlanWithdrawal(t *testing.T) {
+	server := newPlannerServer()
+	defer server.Close()
+	client := newTestClient(t, server)
+	ctx := context.Background()
+
+	plan, err := client.PlanWithdrawal(ctx, withdrawal.PlanParams{Amount: decimal.NewFromInt(500), Address: "0xabc"})
+	assert.NoError(t, err)
+	assert.Equal(t, "1000", plan.CoinID)
+	assert.Equal(t, "42", plan.Claimable.String())
+	if assert.Len(t, plan.Routes, 3) {
+		assert.True(t, plan.Routes[0].Viable)
+		assert.True(t, plan.Routes[1].Viable)
+		assert.Equal(t, "502.5", plan.Routes[1].Debit.String())
+		assert.Equal(t, "8000", plan.Routes[1].MaxAmount.String())
+		assert.True(t, plan.Routes[2].Viable)
+		assert.Equal(t, "501", plan.Routes[2].Debit.String())
+	}
+	assert.Equal(t, withdrawal.KindNormal, plan.Cheapest.Kind)
+	assert.Equal(t, withdrawal.KindCross, plan.Fastest.Kind)
+
+	// Above the fast withdraw maximum the fast route is left out
+	plan, err = client.PlanWithdrawal(ctx, withdrawal.PlanParams{Amount: decimal.NewFromInt(9000)})
+	assert.NoError(t, err)
+	assert.Contains(t, plan.Routes[1].Reason, "above max 8000")
+	assert.Equal(t, withdrawal.KindNormal, plan.Cheapest.Kind)
+	assert.Equal(t, withdrawal.KindCross, plan.Fastest.Kind)
+
+	// Chains with withdrawals disabled have no fast or cross route
+	plan, err = client.PlanWithdrawal(ctx, withdrawal.PlanParams{Amount: decimal.NewFromInt(500), ChainID: "42161"})
+	assert.NoError(t, err)
+	assert.Contains(t, plan.Routes[1].Reason, "disabled")
+	assert.Contains(t, plan.Routes[2].Reason, "disabled")
+	assert.Nil(t, plan.Cheapest)
+
+	// Other chains are served by cross withdrawals only
+	plan, err = client.PlanWithdrawal(ctx, withdrawal.PlanParams{Amount: decimal.NewFromInt(24999), ChainID: "56"})
+	assert.NoError(t, err)
+	assert.Contains(t, plan.Routes[0].Reason, "chain 1 only")
+	assert.Contains(t, plan.Routes[1].Reason, "CHAIN_NOT_SUPPORTED")
+	assert.Contains(t, plan.Routes[2].Reason, "above max 20000")
+	assert.Nil(t, plan.Cheapest)
+	assert.Nil(t, plan.Fastest)
+
+	plan, err = client.PlanWithdrawal(ctx, withdrawal.PlanParams{Amount: decimal.NewFromInt(15000), ChainID: "56"})
+	assert.NoError(t, err)
+	if assert.NotNil(t, plan.Cheapest) {
+		assert.Equal(t, withdrawal.KindCross, plan.Cheapest.Kind)
+		assert.Equal(t, "15001", plan.Cheapest.Debit.String())
+		assert.Equal(t, plan.Cheapest, plan.Fastest)
+	}
+
+	_, err = client.PlanWithdrawal(ctx, withdrawal.PlanParams{Amount: decimal.NewFromInt(-1)})
+	assert.Error(t, err)
+}
@@ -0,0 +1,191 @@
+package withdrawal
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/coin-quant/go-edgex/sdk"
+	"github.com/coin-quant/go-edgex/sdk/withdrawal"
+	"github.com/stretchr/testify/assert"
+)
+
+const testStarkPrivateKey = "04a266bc1e005725a278034bc4ab0f3075a7110a47d390b0b1b7841cabac0c4d"
+
+func TestStageOf(t *testing.T) {
+	// Statuses of the withdraw API records
+	assert.Equal(t, withdrawal.StageSubmitted, withdrawal.StageOf("PENDING_CENSORING"))
+	assert.Equal(t, withdrawal.StageCensored, withdrawal.StageOf("SUCCESS_CENSOR_SUCCESS"))
+	assert.Equal(t, withdrawal.StageL2Approved, withdrawal.StageOf("SUCCESS_L2_APPROVED"))
+	assert.Equal(t, withdrawal.StageRejected, withdrawal.StageOf("FAILED_L2_REJECT_APPROVED"))
+	assert.Equal(t, withdrawal.StageRejected, withdrawal.StageOf("FAILED_CENSOR_FAILURE"))
+	assert.Equal(t, withdrawal.StageRejected, withdrawal.StageOf("FAILED_CHECK_INVALID"))
+	assert.Equal(t, withdrawal.StageCensored, withdrawal.StageOf("FAILED_L2_REJECT"))
+	assert.False(t, withdrawal.Withdrawal{Kind: withdrawal.KindNormal, Stage: withdrawal.StageOf("FAILED_L2_REJECT")}.Final())
+
+	// Statuses of the assets API records
+	assert.Equal(t, withdrawal.StageSubmitted, withdrawal.AssetStageOf(withdrawal.KindNormal, "NORMAL_WITHDRAW_PENDING_RISK_CHECKING"))
+	assert.Equal(t, withdrawal.StageCensored, withdrawal.AssetStageOf(withdrawal.KindNormal, "NORMAL_WITHDRAW_PENDING_L2_APPROVING"))
+	assert.Equal(t, withdrawal.StageOnChain, withdrawal.AssetStageOf(withdrawal.KindNormal, "NORMAL_WITHDRAW_PENDING_L1_WITHDRAWING"))
+	assert.Equal(t, withdrawal.StageCompleted, withdrawal.AssetStageOf(withdrawal.KindNormal, "NORMAL_WITHDRAW_SUCCESS_L1_COMPLETED"))
+	assert.Equal(t, withdrawal.StageRejected, withdrawal.AssetStageOf(withdrawal.KindNormal, "NORMAL_WITHDRAW_FAILED_L2_REJECTED"))
+	assert.Equal(t, withdrawal.StageSubmitted, withdrawal.AssetStageOf(withdrawal.KindFast, "FAST_WITHDRAW_PENDING_CHECKING_ACCOUNT"))
+	assert.Equal(t, withdrawal.StageSubmitted, withdrawal.AssetStageOf(withdrawal.KindFast, "FAST_WITHDRAW_PENDING_CENSORING_CONFIRMING"))
+	assert.Equal(t, withdrawal.StageOnChain, withdrawal.AssetStageOf(withdrawal.KindFast, "FAST_WITHDRAW_PENDING_L1_CONFIRMING"))
+	assert.Equal(t, withdrawal.StageCompleted, withdrawal.AssetStageOf(withdrawal.KindFast, "FAST_WITHDRAW_SUCCESS"))
+	assert.Equal(t, withdrawal.StageRejected, withdrawal.AssetStageOf(withdrawal.KindFast, "FAST_WITHDRAW_FAILED_L1_REJECTED"))
+	assert.Equal(t, withdrawal.StageSubmitted, withdrawal.AssetStageOf(withdrawal.KindCross, "CROSS_WITHDRAW_PENDING_CENSOR_CHECKING_ACCOUNT"))
+	assert.Equal(t, withdrawal.StageCensored, withdrawal.AssetStageOf(withdrawal.KindCross, "CROSS_WITHDRAW_PENDING_L2_APPROVING"))
+	assert.Equal(t, withdrawal.StageOnChain, withdrawal.AssetStageOf(withdrawal.KindCross, "CROSS_WITHDRAW_PENDING_L1_CONFIRMING"))
+	assert.Equal(t, withdrawal.StageCompleted, withdrawal.AssetStageOf(withdrawal.KindCross, "CROSS_WITHDRAW_SUCCESS"))
+	assert.Equal(t, withdrawal.StageRejected, withdrawal.AssetStageOf(withdrawal.KindCross, "CROSS_WITHDRAW_FAILED_USER_BALANCE_NOT_ENOUGH"))
+
+	assert.True(t, withdrawal.Withdrawal{Kind: withdrawal.KindNormal, Stage: withdrawal.StageL2Approved}.Final())
+	assert.False(t, withdrawal.Withdrawal{Kind: withdrawal.KindFast, Stage: withdrawal.StageL2Approved}.Final())
+}
+
+// fakeWithdrawServer serves the withdrawal endpoints with scripted fast withdrawal statuses
+type fakeWithdrawServer struct {
+	mu       sync.Mutex
+	statuses []string
+	polls    int
+	// orders is the fast and cross withdrawal asset order list
+	orders     string
+	orderQuery url.Values
+}
+
+func (f *fakeWithdrawServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	switch r.URL.Path {
+	case "/api/v1/private/assets/getNormalWithdrawById":
+		fmt.Fprint(w, `{"code":"SUCCESS","data":[]}`)
+	case "/api/v1/private/assets/getFastWithdrawById":
+		status := f.statuses[len(f.statuses)-1]
+		if f.polls < len(f.statuses) {
+			status = f.statuses[f.polls]
+		}
+		f.polls++
+		fmt.Fprintf(w, `{"code":"SUCCESS","data":[{"id":"%s","coinId":"1000","amount":"50","fee":"0.5","status":"%s","transferOutId":"31","createdTime":"1700000000000"}]}`,
+			r.URL.Query().Get("fastWithdrawIdList"), status)
+	case "/api/v1/private/assets/getCrossWithdrawById":
+		fmt.Fprintf(w, `{"code":"SUCCESS","data":[{"id":"%s","status":"CROSS_WITHDRAW_FAILED_L2_REJECTED","l2RejectCode":"EXPIRED","l2RejectReason":"signature expired"}]}`,
+			r.URL.Query().Get("crossWithdrawIdList"))
+	case "/api/v1/private/assets/getAllOrdersPage":
+		f.orderQuery = r.URL.Query()
+		fmt.Fprintf(w, `{"code":"SUCCESS","data":{"dataList":[%s]}}`, f.orders)
+	case "/api/v1/private/withdraw/getActiveWithdraw":
+		fmt.Fprint(w, `{"code":"SUCCESS","data":{"dataList":[{"id":"11","coinId":"1000","amount":"10","status":"PENDING_CENSORING","createdTime":"1700000001000"},{"id":"12","status":"SUCCESS_L2_APPROVED"}]}}`)
+	default:
+		http.NotFound(w, r)
+	}
+}
+
+func newTestClient(t *testing.T, server *httptest.Server) *sdk.Client {
+	client, err := sdk.NewClient(&sdk.ClientConfig{BaseURL: server.URL, AccountID: 7, StarkPriKey: testStarkPrivateKey})
+	assert.NoError(t, err)
+	client.Withdrawals.SetPollInterval(time.Millisecond)
+	return client
+}
+
+func TestWaitForCompletion(t *testing.T) {
+	fake := &fakeWithdrawServer{statuses: []string{"FAST_WITHDRAW_PENDING_CHECKING", "FAST_WITHDRAW_PENDING_L2_APPROVING", "FAST_WITHDRAW_PENDING_L1_CONFIRMING", "FAST_WITHDRAW_SUCCESS"}}
+	server := httptest.NewServer(fake)
+	defer server.Close()
+	client := newTestClient(t, server)
+
+	var events []withdrawal.Event
+	client.Withdrawals.OnChange(func(e withdrawal.Event) { events = append(events, e) })
+
+	// The kind of an untracked withdrawal is found by probing
+	w, err := client.Withdrawals.WaitForCompletion(context.Background(), "21")
+	assert.NoError(t, err)
+	assert.Equal(t, withdrawal.KindFast, w.Kind)
+	assert.Equal(t, withdrawal.StageCompleted, w.Stage)
+	assert.Equal(t, "0.5", w.Fee.String())
+	assert.Equal(t, 4, fake.polls)
+
+	var stages []withdrawal.Stage
+	for _, e := range events {
+		stages = append(stages, e.Withdrawal.Stage)
+	}
+	assert.Equal(t, []withdrawal.Stage{withdrawal.StageSubmitted, withdrawal.StageCensored, withdrawal.StageOnChain, withdrawal.StageCompleted}, stages)
+	assert.Equal(t, withdrawal.Stage(""), events[0].Previous)
+	assert.Equal(t, withdrawal.StageOnChain, events[3].Previous)
+
+	// Completed withdrawals are no longer pending
+	pending, err := client.Withdrawals.Pending(context.Background(), time.Time{})
+	assert.NoError(t, err)
+	for _, p := range pending {
+		assert.NotEqual(t, "21", p.ID)
+	}
+
+	client.Withdrawals.Track(withdrawal.KindCross, "22")
+	w, err = client.Withdrawals.WaitForCompletion(context.Background(), "22")
+	assert.ErrorContains(t, err, "signature expired")
+	assert.Equal(t, withdrawal.StageRejected, w.Stage)
+	assert.Equal(t, "l2 reject EXPIRED: signature expired", w.Reason)
+}
+
+func TestPendingListsUntrackedWithdrawals(t *testing.T) {
+	fake := &fakeWithdrawServer{
+		statuses: []string{"FAST_WITHDRAW_PENDING_L2_APPROVING"},
+		orders:   `{"orderId":"21","type":"ORDER_TYPE_FAST_WITHDRAW","status":1,"time":"1700000000000"},{"orderId":"41","type":"ORDER_TYPE_TRANSFER_OUT"}`,
+	}
+	server := httptest.NewServer(fake)
+	defer server.Close()
+	client := newTestClient(t, server)
+
+	// Nothing was tracked, as after a restart
+	since := time.UnixMilli(1699999000000)
+	pending, err := client.Withdrawals.Pending(context.Background(), since)
+	assert.NoError(t, err)
+	if assert.Len(t, pending, 2) {
+		assert.Equal(t, "21", pending[0].ID)
+		assert.Equal(t, withdrawal.KindFast, pending[0].Kind)
+		assert.Equal(t, withdrawal.StageCensored, pending[0].Stage)
+		assert.Equal(t, "11", pending[1].ID)
+	}
+	assert.Equal(t, "ORDER_TYPE_FAST_WITHDRAW,ORDER_TYPE_CROSS_WITHDRAW", fake.orderQuery.Get("typeList"))
+	assert.Equal(t, "1699999000", fake.orderQuery.Get("startTime"))
+}
+
+func TestPendingAndStream(t *testing.T) {
+	fake := &fakeWithdrawServer{statuses: []string{"FAST_WITHDRAW_PENDING_L1_CONFIRMING"}}
+	server := httptest.NewServer(fake)
+	defer server.Close()
+	client := newTestClient(t, server)
+
+	client.Withdrawals.Track(withdrawal.KindFast, "21")
+	pending, err := client.Withdrawals.Pending(context.Background(), time.Time{})
+	assert.NoError(t, err)
+	if assert.Len(t, pending, 2) {
+		assert.Equal(t, "21", pending[0].ID)
+		assert.Equal(t, withdrawal.StageOnChain, pending[0].Stage)
+		assert.Equal(t, "11", pending[1].ID)
+		assert.Equal(t, withdrawal.KindNormal, pending[1].Kind)
+		assert.Equal(t, withdrawal.StageSubmitted, pending[1].Stage)
+	}
+
+	var events []withdrawal.Event
+	client.Withdrawals.OnChange(func(e withdrawal.Event) { events = append(events, e) })
+	client.Withdrawals.HandlePrivateMessage([]byte(`{"type":"trade-event","content":{"event":"WITHDRAW_UPDATE","data":{"withdraw":[{"id":"11","status":"FAILED_CENSOR_FAILURE","censorFailCode":"RISK","censorFailReason":"blocked address"}]}}}`))
+	// An unverified L2 reject of the transfer to the liquidity provider may still be reverted
+	client.Withdrawals.HandlePrivateMessage([]byte(`{"type":"trade-event","content":{"event":"TRANSFER_OUT_UPDATE","data":{"transferOut":[{"id":"31","status":"FAILED_L2_REJECT","l2RejectCode":"NONCE","l2RejectReason":"nonce used"}]}}}`))
+	assert.Len(t, events, 1)
+	client.Withdrawals.HandlePrivateMessage([]byte(`{"type":"trade-event","content":{"event":"TRANSFER_OUT_UPDATE","data":{"transferOut":[{"id":"31","status":"FAILED_L2_REJECT_APPROVED","l2RejectCode":"NONCE","l2RejectReason":"nonce used"}]}}}`))
+
+	if assert.Len(t, events, 2) {
+		assert.Equal(t, "11", events[0].Withdrawal.ID)
+		assert.Equal(t, withdrawal.StageSubmitted, events[0].Previous)
+		assert.Equal(t, "censor RISK: blocked address", events[0].Withdrawal.Reason)
+		assert.Equal(t, "21", events[1].Withdrawal.ID)
+		assert.Equal(t, withdrawal.StageRejected, events[1].Withdrawal.Stage)
+		assert.Equal(t, "l2 reject NONCE: nonce used", events[1].Withdrawal.Reason)
+	}
+}
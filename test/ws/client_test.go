@@ -0,0 +1,80 @@
+package ws_test
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/coin-quant/go-edgex/sdk/ws"
+	"github.com/gorilla/websocket"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestConcurrentSubscribeAndClose(t *testing.T) {
+	var connections atomic.Int32
+	server := newPongServer(t, &connections)
+
+	client := ws.NewClient("ws"+strings.TrimPrefix(server.URL, "http"), false, 0, "")
+	client.SetHeartbeatConfig(ws.HeartbeatConfig{PingInterval: 5 * time.Millisecond})
+	assert.NoError(t, client.Connect(context.Background()))
+
+	var wg sync.WaitGroup
+	for i := 0; i < 20; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			assert.NoError(t, client.Subscribe(ws.TickerChannel(fmt.Sprintf("1000000%d", i)), nil))
+		}(i)
+	}
+	wg.Wait()
+
+	assert.NoError(t, client.Close())
+	assert.NoError(t, client.Close())
+
+	select {
+	case <-client.Done():
+	case <-time.After(time.Second):
+		t.Fatal("timeout waiting for Done")
+	}
+	assert.ErrorIs(t, client.Err(), ws.ErrClosed)
+	assert.ErrorIs(t, client.Subscribe(ws.TickerChannel("10000001"), nil), ws.ErrClosed)
+}
+
+func TestDoneReportsServerDisconnect(t *testing.T) {
+	upgrader := websocket.Upgrader{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		conn, err := upgrader.Upgrade(w, r, nil)
+		if err != nil {
+			return
+		}
+		_ = conn.WriteMessage(websocket.CloseMessage, websocket.FormatCloseMessage(websocket.CloseGoingAway, "bye"))
+		conn.Close()
+	}))
+	defer server.Close()
+
+	client := ws.NewClient("ws"+strings.TrimPrefix(server.URL, "http"), false, 0, "")
+	client.SetHeartbeatConfig(ws.HeartbeatConfig{AutoReconnect: false})
+	assert.NoError(t, client.Connect(context.Background()))
+
+	select {
+	case <-client.Done():
+	case <-time.After(time.Second):
+		t.Fatal("timeout waiting for Done")
+	}
+	assert.True(t, websocket.IsCloseError(client.Err(), websocket.CloseGoingAway))
+
+	// A stopped client stays stopped
+	err := client.Connect(context.Background())
+	var closeErr *websocket.CloseError
+	if assert.ErrorAs(t, err, &closeErr) {
+		assert.Equal(t, websocket.CloseGoingAway, closeErr.Code)
+	}
+	assert.True(t, websocket.IsCloseError(client.Err(), websocket.CloseGoingAway))
+	assert.NoError(t, client.Close())
+}
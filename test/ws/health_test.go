@@ -0,0 +1,104 @@
+package ws_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/coin-quant/go-edgex/sdk/ws"
+	"github.com/gorilla/websocket"
+	"github.com/stretchr/testify/assert"
+)
+
+// newPongServer starts a server that answers pings and otherwise stays silent
+func newPongServer(t *testing.T, connections *atomic.Int32) *httptest.Server {
+	upgrader := websocket.Upgrader{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		conn, err := upgrader.Upgrade(w, r, nil)
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		connections.Add(1)
+
+		for {
+			var msg ws.Message
+			if err := conn.ReadJSON(&msg); err != nil {
+				return
+			}
+			if msg.Type == "ping" {
+				_ = conn.WriteJSON(ws.Message{Type: "pong", Time: msg.Time})
+			}
+		}
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func TestStaleFeedTriggersReconnect(t *testing.T) {
+	var connections atomic.Int32
+	server := newPongServer(t, &connections)
+
+	client := ws.NewClient("ws"+strings.TrimPrefix(server.URL, "http"), false, 0, "")
+	client.SetHeartbeatConfig(ws.HeartbeatConfig{
+		PingInterval:        20 * time.Millisecond,
+		ReadTimeout:         time.Second,
+		StaleCheckInterval:  10 * time.Millisecond,
+		AutoReconnect:       true,
+		ReconnectMinBackoff: 10 * time.Millisecond,
+	})
+
+	staleCh := make(chan string, 8)
+	client.OnStale(func(channel string) {
+		staleCh <- channel
+	})
+
+	err := client.Connect(context.Background())
+	assert.NoError(t, err)
+	defer client.Close()
+
+	channel := ws.DepthChannel("10000001")
+	assert.NoError(t, client.Subscribe(channel, nil))
+	client.SetStaleTimeout(channel, 50*time.Millisecond)
+
+	select {
+	case got := <-staleCh:
+		assert.Equal(t, channel, got)
+	case <-time.After(2 * time.Second):
+		t.Fatal("timeout waiting for stale feed")
+	}
+
+	assert.Eventually(t, func() bool {
+		health := client.Health()
+		return health.Connected && health.ReconnectCount >= 1 && connections.Load() >= 2
+	}, 2*time.Second, 10*time.Millisecond)
+}
+
+func TestHealthTracksRoundTripTime(t *testing.T) {
+	var connections atomic.Int32
+	server := newPongServer(t, &connections)
+
+	client := ws.NewClient("ws"+strings.TrimPrefix(server.URL, "http"), false, 0, "")
+	client.SetHeartbeatConfig(ws.HeartbeatConfig{
+		PingInterval: 20 * time.Millisecond,
+		ReadTimeout:  time.Second,
+	})
+
+	err := client.Connect(context.Background())
+	assert.NoError(t, err)
+	defer client.Close()
+
+	assert.Eventually(t, func() bool {
+		health := client.Health()
+		return !health.LastPongTime.IsZero() && !health.LastMessageTime.IsZero()
+	}, 2*time.Second, 10*time.Millisecond)
+
+	health := client.Health()
+	assert.True(t, health.Connected)
+	assert.GreaterOrEqual(t, health.RTT, time.Duration(0))
+	assert.Equal(t, 0, health.ReconnectCount)
+}
@@ -0,0 +1,133 @@
+package ws_test
+
+import (
+	"bytes"
+	"context"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/coin-quant/go-edgex/sdk/ws"
+	"github.com/coin-quant/go-edgex/sdk/ws/wstest"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRecordAndReplay(t *testing.T) {
+	for _, name := range []string{"session.jsonl", "session.jsonl.gz"} {
+		t.Run(name, func(t *testing.T) {
+			server := wstest.NewServer(wstest.Config{})
+			defer server.Close()
+
+			path := filepath.Join(t.TempDir(), name)
+			recorder, err := ws.CreateRecorder(path)
+			assert.NoError(t, err)
+
+			live := ws.NewClient(server.PublicURL(), false, 0, "")
+			live.Record(recorder)
+			assert.NoError(t, live.Connect(context.Background()))
+			received := make(chan []byte, 8)
+			assert.NoError(t, live.Subscribe(ws.TickerChannel("10000001"), nil))
+			live.OnMessage("ticker", func(message []byte) { received <- message })
+			assert.NoError(t, server.WaitForSubscription(ws.TickerChannel("10000001"), time.Second))
+
+			var liveFrames [][]byte
+			for i := 0; i < 3; i++ {
+				assert.NoError(t, server.SendTicker("10000001", map[string]int{"seq": i}))
+				select {
+				case message := <-received:
+					liveFrames = append(liveFrames, message)
+				case <-time.After(time.Second):
+					t.Fatal("timeout waiting for ticker")
+				}
+			}
+			assert.NoError(t, live.Close())
+			assert.NoError(t, recorder.Close())
+
+			reader, err := ws.OpenRecording(path)
+			assert.NoError(t, err)
+			defer reader.Close()
+
+			replay := ws.NewClient("", false, 0, "")
+			stream := replay.Stream("ticker", ws.StreamConfig{BufferSize: 8})
+			count, err := replay.Replay(context.Background(), reader, ws.ReplayConfig{
+				Channels: []string{ws.TickerChannel("10000001")},
+			})
+			assert.NoError(t, err)
+			assert.Equal(t, 3, count)
+			stream.Close()
+
+			var replayed [][]byte
+			for message := range stream.C() {
+				replayed = append(replayed, message)
+			}
+			assert.Len(t, replayed, len(liveFrames))
+			for i := range replayed {
+				assert.JSONEq(t, string(liveFrames[i]), string(replayed[i]))
+			}
+		})
+	}
+}
+
+func TestRecordMalformedQuoteEvent(t *testing.T) {
+	server := wstest.NewServer(wstest.Config{})
+	defer server.Close()
+
+	var buf bytes.Buffer
+	recorder := ws.NewRecorder(&buf, false)
+	live := ws.NewClient(server.PublicURL(), false, 0, "")
+	live.Record(recorder)
+	received := make(chan struct{}, 1)
+	live.OnMessage("probe", func([]byte) { received <- struct{}{} })
+	assert.NoError(t, live.Connect(context.Background()))
+
+	// The channel of a quote event must be a string
+	assert.NoError(t, server.SendRaw(map[string]interface{}{"type": "quote-event", "channel": 5}))
+	assert.NoError(t, server.SendRaw(map[string]string{"type": "probe"}))
+	select {
+	case <-received:
+	case <-time.After(time.Second):
+		t.Fatal("timeout waiting for probe")
+	}
+	assert.NoError(t, live.Close())
+	assert.NoError(t, recorder.Close())
+
+	reader, err := ws.NewRecordingReader(&buf)
+	assert.NoError(t, err)
+	var channels []string
+	for {
+		frame, err := reader.Next()
+		if err != nil {
+			break
+		}
+		channels = append(channels, frame.Channel)
+	}
+	assert.Contains(t, channels, "quote-event")
+	assert.Contains(t, channels, "probe")
+}
+
+func TestReplayRealTime(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "session.jsonl")
+	recorder, err := ws.CreateRecorder(path)
+	assert.NoError(t, err)
+	start := time.Now()
+	assert.NoError(t, recorder.Record("ACCOUNT_UPDATE", []byte(`{"type":"ACCOUNT_UPDATE"}`), start))
+	assert.NoError(t, recorder.Record("ACCOUNT_UPDATE", []byte(`{"type":"ACCOUNT_UPDATE"}`), start.Add(100*time.Millisecond)))
+	assert.NoError(t, recorder.Record("", []byte("not json"), start.Add(200*time.Millisecond)))
+	assert.NoError(t, recorder.Close())
+	assert.Equal(t, uint64(3), recorder.Frames())
+
+	reader, err := ws.OpenRecording(path)
+	assert.NoError(t, err)
+	defer reader.Close()
+
+	replay := ws.NewClient("", false, 0, "")
+	var raw []string
+	replay.OnMessageHook(func(message []byte) { raw = append(raw, string(message)) })
+
+	began := time.Now()
+	count, err := replay.Replay(context.Background(), reader, ws.ReplayConfig{RealTime: true, Speed: 2})
+	assert.NoError(t, err)
+	assert.Equal(t, 3, count)
+	assert.GreaterOrEqual(t, time.Since(began), 100*time.Millisecond)
+	assert.Equal(t, "not json", raw[2])
+}
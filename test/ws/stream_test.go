@@ -0,0 +1,129 @@
+package ws_test
+
+import (
+	"context"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/coin-quant/go-edgex/sdk/ws"
+	"github.com/coin-quant/go-edgex/sdk/ws/wstest"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestStreamDropOldest(t *testing.T) {
+	stream := ws.NewStream(ws.StreamConfig{BufferSize: 2, Policy: ws.OverflowDropOldest})
+	handler := stream.Handler()
+
+	handler([]byte("1"))
+	handler([]byte("2"))
+	handler([]byte("3"))
+
+	assert.Equal(t, uint64(1), stream.Dropped())
+	assert.Equal(t, uint64(3), stream.Delivered())
+	assert.Equal(t, "2", string(<-stream.C()))
+	assert.Equal(t, "3", string(<-stream.C()))
+}
+
+func TestStreamDropNewest(t *testing.T) {
+	stream := ws.NewStream(ws.StreamConfig{BufferSize: 2, Policy: ws.OverflowDropNewest})
+	handler := stream.Handler()
+
+	handler([]byte("1"))
+	handler([]byte("2"))
+	handler([]byte("3"))
+
+	assert.Equal(t, uint64(1), stream.Dropped())
+	assert.Equal(t, "1", string(<-stream.C()))
+	assert.Equal(t, "2", string(<-stream.C()))
+}
+
+func TestStreamBlock(t *testing.T) {
+	stream := ws.NewStream(ws.StreamConfig{BufferSize: 1, Policy: ws.OverflowBlock})
+	handler := stream.Handler()
+
+	handler([]byte("1"))
+	pushed := make(chan struct{})
+	go func() {
+		handler([]byte("2"))
+		close(pushed)
+	}()
+
+	select {
+	case <-pushed:
+		t.Fatal("push should block while the buffer is full")
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	assert.Equal(t, "1", string(<-stream.C()))
+	<-pushed
+	assert.Equal(t, "2", string(<-stream.C()))
+	assert.Equal(t, uint64(0), stream.Dropped())
+}
+
+func TestStreamCloseUnblocksProducer(t *testing.T) {
+	stream := ws.NewStream(ws.StreamConfig{BufferSize: 1, Policy: ws.OverflowBlock})
+	handler := stream.Handler()
+
+	handler([]byte("1"))
+	pushed := make(chan struct{})
+	go func() {
+		handler([]byte("2"))
+		close(pushed)
+	}()
+
+	time.Sleep(20 * time.Millisecond)
+	stream.Close()
+	<-pushed
+
+	assert.Equal(t, uint64(1), stream.Dropped())
+	handler([]byte("3"))
+	assert.Equal(t, uint64(1), stream.Dropped())
+}
+
+func TestConcurrentTickerStreams(t *testing.T) {
+	server := wstest.NewServer(wstest.Config{})
+	defer server.Close()
+
+	manager := ws.NewManager(server.URL, 0, "")
+	defer manager.Close()
+	assert.NoError(t, manager.ConnectPublic(context.Background()))
+
+	eth, err := manager.StreamMarketTicker("10000002", ws.StreamConfig{BufferSize: 4})
+	assert.NoError(t, err)
+	btc, err := manager.StreamMarketTicker("10000001", ws.StreamConfig{BufferSize: 4})
+	assert.NoError(t, err)
+	assert.NoError(t, server.WaitForSubscription(ws.TickerChannel("10000002"), time.Second))
+	assert.NoError(t, server.WaitForSubscription(ws.TickerChannel("10000001"), time.Second))
+
+	assert.NoError(t, server.SendTicker("10000002", map[string]string{"contractId": "10000002"}))
+	assert.NoError(t, server.SendTicker("10000001", map[string]string{"contractId": "10000001"}))
+
+	select {
+	case message := <-eth.C():
+		assert.Contains(t, string(message), `"contractId":"10000002"`)
+	case <-time.After(time.Second):
+		t.Fatal("timeout waiting for first ticker stream")
+	}
+	select {
+	case message := <-btc.C():
+		assert.Contains(t, string(message), `"contractId":"10000001"`)
+	case <-time.After(time.Second):
+		t.Fatal("timeout waiting for second ticker stream")
+	}
+	assert.Equal(t, uint64(1), eth.Delivered())
+	assert.Equal(t, uint64(1), btc.Delivered())
+
+	eth.Close()
+	assert.Eventually(t, func() bool {
+		return !slices.Contains(server.Subscriptions(), ws.TickerChannel("10000002"))
+	}, time.Second, 10*time.Millisecond)
+	assert.Contains(t, server.Subscriptions(), ws.TickerChannel("10000001"))
+
+	assert.NoError(t, server.SendTicker("10000001", map[string]string{"contractId": "10000001"}))
+	select {
+	case <-btc.C():
+	case <-time.After(time.Second):
+		t.Fatal("timeout waiting for remaining ticker stream")
+	}
+}
@@ -49,52 +49,56 @@ func TestWebSocket(t *testing.T) {
 		{
 			name: "Market Ticker",
 			subFunc: func() error {
-				return manager.SubscribeMarketTicker(contractID, func(message []byte) {
+				_, err := manager.SubscribeMarketTicker(contractID, func(message []byte) {
 					t.Logf("Ticker message received: %s", string(message))
 					if !tickerReceived {
 						close(tickerMsgCh)
 						tickerReceived = true
 					}
 				})
+				return err
 			},
 			msgCh: tickerMsgCh,
 		},
 		{
 			name: "KLine",
 			subFunc: func() error {
-				return manager.SubscribeKLine(contractID, "DAY_1", func(message []byte) {
+				_, err := manager.SubscribeKLine(contractID, "DAY_1", func(message []byte) {
 					t.Logf("KLine message received: %s", string(message))
 					if !klineReceived {
 						close(klineMsgCh)
 						klineReceived = true
 					}
 				})
+				return err
 			},
 			msgCh: klineMsgCh,
 		},
 		{
 			name: "Depth",
 			subFunc: func() error {
-				return manager.SubscribeDepth(contractID, func(message []byte) {
+				_, err := manager.SubscribeDepth(contractID, func(message []byte) {
 					t.Logf("Depth message received: %s", string(message))
 					if !depthReceived {
 						close(depthMsgCh)
 						depthReceived = true
 					}
 				})
+				return err
 			},
 			msgCh: depthMsgCh,
 		},
 		{
 			name: "Trades",
 			subFunc: func() error {
-				return manager.SubscribeTrades(contractID, func(message []byte) {
+				_, err := manager.SubscribeTrades(contractID, func(message []byte) {
 					t.Logf("Trades message received: %s", string(message))
 					if !tradesReceived {
 						close(tradesMsgCh)
 						tradesReceived = true
 					}
 				})
+				return err
 			},
 			msgCh: tradesMsgCh,
 		},
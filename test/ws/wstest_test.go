@@ -0,0 +1,164 @@
+package ws_test
+
+import (
+	"context"
+	"encoding/hex"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/coin-quant/go-edgex/sdk/ws"
+	"github.com/coin-quant/go-edgex/sdk/ws/wstest"
+	"github.com/coin-quant/go-edgex/starkcurve"
+	"github.com/stretchr/testify/assert"
+)
+
+const testStarkPrivateKey = "04a266bc1e005725a278034bc4ab0f3075a7110a47d390b0b1b7841cabac0c4d"
+
+func testStarkPublicKey(t *testing.T) string {
+	priv, err := hex.DecodeString(testStarkPrivateKey)
+	assert.NoError(t, err)
+	x, _ := starkcurve.NewStarkCurve().ScalarBaseMult(priv)
+	return "0x" + hex.EncodeToString(x.Bytes())
+}
+
+func TestFakeServerRoutesPublicFrames(t *testing.T) {
+	server := wstest.NewServer(wstest.Config{})
+	defer server.Close()
+
+	manager := ws.NewManager(server.URL, 0, "")
+	defer manager.Close()
+	assert.NoError(t, manager.ConnectPublic(context.Background()))
+
+	tickers := make(chan []byte, 4)
+	depths := make(chan []byte, 4)
+	_, err := manager.SubscribeMarketTicker("10000001", func(message []byte) { tickers <- message })
+	assert.NoError(t, err)
+	_, err = manager.SubscribeDepth("10000001", func(message []byte) { depths <- message })
+	assert.NoError(t, err)
+	assert.NoError(t, server.WaitForSubscription(ws.TickerChannel("10000001"), time.Second))
+	assert.NoError(t, server.WaitForSubscription(ws.DepthChannel("10000001"), time.Second))
+
+	assert.NoError(t, server.SendTicker("10000001", map[string]string{"contractId": "10000001", "lastPrice": "100"}))
+	assert.NoError(t, server.SendDepth("10000001", "SNAPSHOT", map[string]string{"depthType": "SNAPSHOT"}))
+
+	select {
+	case message := <-tickers:
+		assert.Contains(t, string(message), `"lastPrice":"100"`)
+	case <-time.After(time.Second):
+		t.Fatal("timeout waiting for ticker")
+	}
+	select {
+	case message := <-depths:
+		assert.Contains(t, string(message), `"dataType":"Snapshot"`)
+	case <-time.After(time.Second):
+		t.Fatal("timeout waiting for depth")
+	}
+
+	server.Ping()
+	assert.Eventually(t, func() bool { return server.Pongs() >= 1 }, time.Second, 10*time.Millisecond)
+}
+
+func TestSubscribeRemover(t *testing.T) {
+	server := wstest.NewServer(wstest.Config{})
+	defer server.Close()
+
+	manager := ws.NewManager(server.URL, 0, "")
+	defer manager.Close()
+	assert.NoError(t, manager.ConnectPublic(context.Background()))
+
+	var first, second atomic.Int32
+	removeFirst, err := manager.SubscribeMarketTicker("10000001", func([]byte) { first.Add(1) })
+	assert.NoError(t, err)
+	removeSecond, err := manager.SubscribeMarketTicker("10000001", func([]byte) { second.Add(1) })
+	assert.NoError(t, err)
+	assert.NoError(t, server.WaitForSubscription(ws.TickerChannel("10000001"), time.Second))
+
+	// A removed handler no longer receives, the other one still does
+	removeFirst()
+	assert.NoError(t, server.SendTicker("10000001", map[string]string{"lastPrice": "100"}))
+	assert.Eventually(t, func() bool { return second.Load() == 1 }, time.Second, 10*time.Millisecond)
+	assert.Equal(t, int32(0), first.Load())
+	assert.Contains(t, server.Subscriptions(), ws.TickerChannel("10000001"))
+
+	// Removing the last handler unsubscribes the channel
+	removeSecond()
+	assert.Eventually(t, func() bool { return len(server.Subscriptions()) == 0 }, time.Second, 10*time.Millisecond)
+}
+
+func TestFakeServerSkipFrames(t *testing.T) {
+	server := wstest.NewServer(wstest.Config{})
+	defer server.Close()
+
+	manager := ws.NewManager(server.URL, 0, "")
+	defer manager.Close()
+	assert.NoError(t, manager.ConnectPublic(context.Background()))
+
+	var received atomic.Int32
+	_, err := manager.SubscribeTrades("10000001", func([]byte) { received.Add(1) })
+	assert.NoError(t, err)
+	assert.NoError(t, server.WaitForSubscription(ws.TradesChannel("10000001"), time.Second))
+
+	server.SkipFrames(ws.TradesChannel("10000001"), 2)
+	frames := make([]wstest.Frame, 5)
+	for i := range frames {
+		frames[i] = wstest.Frame{Channel: ws.TradesChannel("10000001"), DataType: "Changed", Data: []interface{}{}}
+	}
+	assert.NoError(t, server.Play(context.Background(), frames))
+
+	assert.Eventually(t, func() bool { return received.Load() == 3 }, time.Second, 10*time.Millisecond)
+}
+
+func TestFakeServerVerifiesPrivateAuth(t *testing.T) {
+	server := wstest.NewServer(wstest.Config{
+		AccountID:      542,
+		StarkPublicKey: testStarkPublicKey(t),
+	})
+	defer server.Close()
+
+	manager := ws.NewManager(server.URL, 542, testStarkPrivateKey)
+	defer manager.Close()
+	assert.NoError(t, manager.ConnectPrivate(context.Background()))
+
+	updates := make(chan []byte, 1)
+	assert.NoError(t, manager.OnPrivateMessage("ACCOUNT_UPDATE", func(message []byte) { updates <- message }))
+	assert.Eventually(t, func() bool { return server.Connections() == 1 }, time.Second, 10*time.Millisecond)
+	assert.NoError(t, server.SendPrivate("ACCOUNT_UPDATE", map[string]string{"event": "ACCOUNT_UPDATE"}))
+
+	select {
+	case message := <-updates:
+		assert.Contains(t, string(message), `"event":"ACCOUNT_UPDATE"`)
+	case <-time.After(time.Second):
+		t.Fatal("timeout waiting for private message")
+	}
+
+	wrongKey := ws.NewManager(server.URL, 542, "0123456789abcdef0123456789abcdef0123456789abcdef0123456789abcdef")
+	defer wrongKey.Close()
+	assert.Error(t, wrongKey.ConnectPrivate(context.Background()))
+	assert.Equal(t, 1, server.RejectedConnections())
+}
+
+func TestFakeServerDropResubscribes(t *testing.T) {
+	server := wstest.NewServer(wstest.Config{})
+	defer server.Close()
+
+	client := ws.NewClient(server.PublicURL(), false, 0, "")
+	client.SetHeartbeatConfig(ws.HeartbeatConfig{
+		AutoReconnect:       true,
+		ReconnectMinBackoff: 10 * time.Millisecond,
+	})
+	assert.NoError(t, client.Connect(context.Background()))
+	defer client.Close()
+
+	channel := ws.KLineChannel("10000001", "MINUTE_1")
+	assert.NoError(t, client.Subscribe(channel, nil))
+	assert.NoError(t, server.WaitForSubscription(channel, time.Second))
+
+	server.DropConnections()
+
+	assert.NoError(t, server.WaitForSubscription(channel, 2*time.Second))
+	assert.Eventually(t, func() bool {
+		return server.AcceptedConnections() == 2 && client.Health().ReconnectCount == 1
+	}, 2*time.Second, 10*time.Millisecond)
+	assert.Equal(t, []string{channel}, server.Subscriptions())
+}